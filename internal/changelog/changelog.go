@@ -0,0 +1,190 @@
+// Package changelog groups commit messages into changelogs using
+// conventional-commit semantics.
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/user/mattermost-tools/pkg/github"
+)
+
+const (
+	SectionBreaking = "Breaking Changes"
+	SectionFeatures = "Features"
+	SectionFixes    = "Bug Fixes"
+	SectionPerf     = "Performance"
+	SectionRefactor = "Refactoring"
+	SectionReverts  = "Reverts"
+	SectionChores   = "Chores"
+	SectionOther    = "Other"
+)
+
+// sectionOrder is the order sections appear in a changelog.
+var sectionOrder = []string{
+	SectionBreaking, SectionFeatures, SectionFixes, SectionPerf, SectionRefactor, SectionReverts, SectionChores, SectionOther,
+}
+
+var (
+	headerRegex       = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+	footerRegex       = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:\s*(.*)$`)
+	prSuffixRegex     = regexp.MustCompile(`\s*\(#\d+\)$`)
+	mergeSubjectRegex = regexp.MustCompile(`^Merge (pull request|branch|remote-tracking branch) `)
+)
+
+// Entry is one commit in a changelog.
+type Entry struct {
+	// Type is the lower-cased conventional-commit type, or "" for commits
+	// that do not follow the convention.
+	Type     string `json:"type"`
+	Scope    string `json:"scope,omitempty"`
+	Subject  string `json:"subject"`
+	Breaking bool   `json:"breaking"`
+	// BreakingNote is the text of a BREAKING CHANGE footer.
+	BreakingNote string `json:"breaking_note,omitempty"`
+	SHA          string `json:"sha"`
+	Author       string `json:"author,omitempty"`
+}
+
+type Section struct {
+	Title   string  `json:"title"`
+	Entries []Entry `json:"entries"`
+}
+
+// Repo is the changelog of one repository.
+type Repo struct {
+	Repo     string    `json:"repo"`
+	Sections []Section `json:"sections"`
+}
+
+// ParseCommit reads a commit message with conventional-commit semantics. It
+// returns false for merge commits, whose changes are listed by the commits
+// they merge.
+func ParseCommit(c github.Commit) (Entry, bool) {
+	subject := strings.TrimSpace(strings.Split(c.Commit.Message, "\n")[0])
+	if c.IsMerge() || mergeSubjectRegex.MatchString(subject) || subject == "" {
+		return Entry{}, false
+	}
+
+	entry := Entry{Subject: subject, SHA: c.SHA, Author: c.Author.Login}
+	if m := headerRegex.FindStringSubmatch(subject); m != nil {
+		entry.Type = strings.ToLower(m[1])
+		entry.Scope = strings.TrimSpace(m[2])
+		entry.Breaking = m[3] == "!"
+		entry.Subject = strings.TrimSpace(m[4])
+	} else if strings.HasPrefix(subject, "Revert \"") {
+		entry.Type = "revert"
+	}
+	if m := footerRegex.FindStringSubmatch(c.Commit.Message); m != nil {
+		entry.Breaking = true
+		entry.BreakingNote = strings.TrimSpace(m[1])
+	}
+	return entry, true
+}
+
+// Build groups commits into the changelog of repo. Merge commits are
+// skipped, and commits with the same type, scope and subject, such as
+// cherry-picks, are listed once.
+func Build(repo string, commits []github.Commit) Repo {
+	bySection := make(map[string][]Entry)
+	seen := make(map[string]bool)
+	for _, c := range commits {
+		entry, ok := ParseCommit(c)
+		if !ok {
+			continue
+		}
+		key := entry.Type + "|" + entry.Scope + "|" + strings.ToLower(prSuffixRegex.ReplaceAllString(entry.Subject, ""))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		section := sectionFor(entry)
+		bySection[section] = append(bySection[section], entry)
+	}
+
+	result := Repo{Repo: repo}
+	for _, title := range sectionOrder {
+		if entries := bySection[title]; len(entries) > 0 {
+			result.Sections = append(result.Sections, Section{Title: title, Entries: entries})
+		}
+	}
+	return result
+}
+
+func sectionFor(e Entry) string {
+	if e.Breaking {
+		return SectionBreaking
+	}
+	switch e.Type {
+	case "feat", "feature":
+		return SectionFeatures
+	case "fix", "bugfix", "hotfix":
+		return SectionFixes
+	case "perf":
+		return SectionPerf
+	case "refactor":
+		return SectionRefactor
+	case "revert":
+		return SectionReverts
+	case "chore", "build", "ci", "docs", "style", "test", "deps":
+		return SectionChores
+	default:
+		return SectionOther
+	}
+}
+
+// IsEmpty reports whether the changelog has no entries.
+func (r Repo) IsEmpty() bool {
+	return len(r.Sections) == 0
+}
+
+// Markdown renders the changelog under a heading with the repo name.
+func (r Repo) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("#### %s\n", r.Repo))
+	for _, section := range r.Sections {
+		sb.WriteString(fmt.Sprintf("\n**%s**\n", section.Title))
+		for _, e := range section.Entries {
+			sb.WriteString("- " + e.Markdown() + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Markdown renders the entry as a list item without the leading dash.
+func (e Entry) Markdown() string {
+	line := e.Subject
+	if e.Scope != "" {
+		line = fmt.Sprintf("**%s:** %s", e.Scope, line)
+	}
+	if e.SHA != "" {
+		line += fmt.Sprintf(" (%s)", shortSHA(e.SHA))
+	}
+	if e.BreakingNote != "" {
+		line += " — " + e.BreakingNote
+	}
+	return line
+}
+
+// Markdown renders the changelogs of several repos, skipping empty ones.
+func Markdown(repos []Repo) string {
+	var parts []string
+	for _, r := range repos {
+		if !r.IsEmpty() {
+			parts = append(parts, r.Markdown())
+		}
+	}
+	if len(parts) == 0 {
+		return "_No changes._\n"
+	}
+	return strings.Join(parts, "\n")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
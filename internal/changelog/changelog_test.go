@@ -0,0 +1,127 @@
+package changelog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/changelog"
+	"github.com/user/mattermost-tools/pkg/github"
+)
+
+func commit(sha, message string, parents int) github.Commit {
+	c := github.Commit{SHA: sha, Commit: github.CommitData{Message: message}}
+	for i := 0; i < parents; i++ {
+		c.Parents = append(c.Parents, github.CommitRef{SHA: "parent"})
+	}
+	return c
+}
+
+func TestParseCommit(t *testing.T) {
+	type tc struct {
+		name     string
+		commit   github.Commit
+		expected changelog.Entry
+		skipped  bool
+	}
+
+	cases := []tc{
+		{
+			name:     "type and scope",
+			commit:   commit("abc1234567", "Feat(auth): add SSO login\n\nDetails", 1),
+			expected: changelog.Entry{Type: "feat", Scope: "auth", Subject: "add SSO login", SHA: "abc1234567"},
+		},
+		{
+			name:     "bang marks breaking",
+			commit:   commit("abc1234567", "refactor(api)!: drop v1 endpoints", 1),
+			expected: changelog.Entry{Type: "refactor", Scope: "api", Subject: "drop v1 endpoints", Breaking: true, SHA: "abc1234567"},
+		},
+		{
+			name:   "breaking footer",
+			commit: commit("abc1234567", "fix: rename column\n\nBREAKING CHANGE: users.name is now users.full_name", 1),
+			expected: changelog.Entry{Type: "fix", Subject: "rename column", Breaking: true,
+				BreakingNote: "users.name is now users.full_name", SHA: "abc1234567"},
+		},
+		{
+			name:     "not conventional",
+			commit:   commit("abc1234567", "Update README", 1),
+			expected: changelog.Entry{Subject: "Update README", SHA: "abc1234567"},
+		},
+		{
+			name:     "git revert",
+			commit:   commit("abc1234567", "Revert \"feat: add SSO login\"", 1),
+			expected: changelog.Entry{Type: "revert", Subject: "Revert \"feat: add SSO login\"", SHA: "abc1234567"},
+		},
+		{name: "merge commit", commit: commit("abc1234567", "feat: merge train", 2), skipped: true},
+		{name: "merge pull request", commit: commit("abc1234567", "Merge pull request #12 from org/sso", 1), skipped: true},
+		{name: "merge branch", commit: commit("abc1234567", "Merge branch 'main' into dev", 1), skipped: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			entry, ok := changelog.ParseCommit(c.commit)
+			require.Equal(t, !c.skipped, ok)
+			if !c.skipped {
+				require.Equal(t, c.expected, entry)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	commits := []github.Commit{
+		commit("1111111aaa", "chore(deps): bump gorm", 1),
+		commit("2222222bbb", "feat(auth): add SSO login (#10)", 1),
+		commit("3333333ccc", "fix: nil user crash", 1),
+		commit("4444444ddd", "Merge pull request #10 from org/sso", 2),
+		commit("5555555eee", "feat(auth): add SSO login", 1),
+		commit("6666666fff", "feat(api)!: drop v1 endpoints", 1),
+		commit("7777777aaa", "perf: cache sessions", 1),
+		commit("8888888bbb", "Tidy up", 1),
+		commit("9999999ccc", "docs: describe SSO", 1),
+	}
+
+	result := changelog.Build("auth-service", commits)
+
+	require.Equal(t, "auth-service", result.Repo)
+	var titles []string
+	var subjects [][]string
+	for _, s := range result.Sections {
+		titles = append(titles, s.Title)
+		var list []string
+		for _, e := range s.Entries {
+			list = append(list, e.Subject)
+		}
+		subjects = append(subjects, list)
+	}
+	require.Equal(t, []string{
+		changelog.SectionBreaking, changelog.SectionFeatures, changelog.SectionFixes,
+		changelog.SectionPerf, changelog.SectionChores, changelog.SectionOther,
+	}, titles)
+	require.Equal(t, [][]string{
+		{"drop v1 endpoints"},
+		{"add SSO login (#10)"},
+		{"nil user crash"},
+		{"cache sessions"},
+		{"bump gorm", "describe SSO"},
+		{"Tidy up"},
+	}, subjects)
+
+	require.Equal(t, "#### auth-service\n\n"+
+		"**Breaking Changes**\n- **api:** drop v1 endpoints (6666666)\n\n"+
+		"**Features**\n- **auth:** add SSO login (#10) (2222222)\n\n"+
+		"**Bug Fixes**\n- nil user crash (3333333)\n\n"+
+		"**Performance**\n- cache sessions (7777777)\n\n"+
+		"**Chores**\n- **deps:** bump gorm (1111111)\n- describe SSO (9999999)\n\n"+
+		"**Other**\n- Tidy up (8888888)\n", result.Markdown())
+}
+
+func TestMarkdown(t *testing.T) {
+	repos := []changelog.Repo{
+		changelog.Build("empty", []github.Commit{commit("1111111aaa", "Merge branch 'main'", 2)}),
+		changelog.Build("billing", []github.Commit{commit("2222222bbb", "fix: rounding\n\nBREAKING CHANGE: totals are now in cents", 1)}),
+	}
+
+	require.Equal(t, "#### billing\n\n**Breaking Changes**\n- rounding (2222222) — totals are now in cents\n", changelog.Markdown(repos))
+	require.Equal(t, "_No changes._\n", changelog.Markdown(nil))
+}
@@ -1,17 +1,17 @@
 package changes
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 	"sync"
 
 	"github.com/spf13/cobra"
 
 	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/summarizer"
 	"github.com/user/mattermost-tools/pkg/github"
 	"github.com/user/mattermost-tools/pkg/mattermost"
 )
@@ -44,17 +44,16 @@ Example:
 	cmd.Flags().StringVar(&ignoreRepos, "ignore-repos", "", "Comma-separated list of repos to ignore (overrides config)")
 	cmd.Flags().StringVar(&repos, "repos", "", "Comma-separated list of specific repos to check (if set, only these repos are checked)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print message to stdout instead of posting")
-	cmd.Flags().BoolVar(&withDiff, "with-diff", false, "Include actual diff content for AI analysis (more detailed but slower)")
+	cmd.Flags().BoolVar(&withDiff, "with-diff", false, "Fetch the full diff for AI analysis instead of the per-file patches in the comparison, which GitHub omits for large files (slower)")
 	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Number of repos to process in parallel")
 
 	return cmd
 }
 
 type RepoChanges struct {
-	Repo      github.Repository
-	Compare   *github.CompareResult
-	Summary   string
-	IsBreaking bool
+	Repo    github.Repository
+	Compare *github.CompareResult
+	Summary summarizer.Result
 }
 
 func runChanges(cmd *cobra.Command, args []string) error {
@@ -111,6 +110,15 @@ func runChanges(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	cache, err := openSummaryCache(cfg)
+	if err != nil {
+		return err
+	}
+	sum, err := summarizer.New(cfg.Summarizer, cache)
+	if err != nil {
+		return fmt.Errorf("configuring summarizer: %w", err)
+	}
+
 	ghClient := github.NewClient(ghToken)
 
 	var repoList []github.Repository
@@ -179,18 +187,16 @@ func runChanges(cmd *cobra.Command, args []string) error {
 				}
 			}
 
-			summary, isBreaking, err := generateSummary(repo.Name, compare, diff)
+			result, err := summarizer.Summarize(ctx, sum, summarizer.Input{Repo: repo.Name, Compare: compare, Diff: diff})
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "WARNING: failed to generate summary for %s: %v\n", repo.Name, err)
-				summary = fmt.Sprintf("%d commits (AI summary unavailable)", compare.TotalCommits)
 			}
 
 			mu.Lock()
 			reposWithChanges = append(reposWithChanges, RepoChanges{
-				Repo:       repo,
-				Compare:    compare,
-				Summary:    summary,
-				IsBreaking: isBreaking,
+				Repo:    repo,
+				Compare: compare,
+				Summary: result,
 			})
 			mu.Unlock()
 		}(repo)
@@ -219,59 +225,6 @@ func runChanges(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func generateSummary(repoName string, compare *github.CompareResult, diff string) (string, bool, error) {
-	var commitInfo strings.Builder
-	commitInfo.WriteString(fmt.Sprintf("Repository: %s\n", repoName))
-	commitInfo.WriteString(fmt.Sprintf("Total commits: %d\n\n", compare.TotalCommits))
-
-	commitInfo.WriteString("Commits:\n")
-	for _, c := range compare.Commits {
-		msg := strings.Split(c.Commit.Message, "\n")[0]
-		commitInfo.WriteString(fmt.Sprintf("- %s: %s\n", c.SHA[:7], msg))
-	}
-
-	commitInfo.WriteString("\nFiles changed:\n")
-	for _, f := range compare.Files {
-		commitInfo.WriteString(fmt.Sprintf("- %s (%s, +%d/-%d)\n", f.Filename, f.Status, f.Additions, f.Deletions))
-	}
-
-	if diff != "" {
-		maxDiffLen := 50000
-		if len(diff) > maxDiffLen {
-			diff = diff[:maxDiffLen] + "\n... (diff truncated)"
-		}
-		commitInfo.WriteString("\nDiff:\n")
-		commitInfo.WriteString(diff)
-	}
-
-	prompt := fmt.Sprintf(`Analyze these git changes and provide a brief summary (2-3 sentences max).
-Focus on: what features/fixes are included, any breaking changes or important notes.
-If there are breaking changes, database migrations, API changes, or security updates, start your response with "BREAKING:" followed by the summary.
-Otherwise just provide the summary directly.
-
-%s`, commitInfo.String())
-
-	cmd := exec.Command("claude", "-p", prompt)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", false, fmt.Errorf("claude command failed: %w (stderr: %s)", err, stderr.String())
-	}
-
-	summary := strings.TrimSpace(stdout.String())
-	isBreaking := strings.HasPrefix(strings.ToUpper(summary), "BREAKING:")
-
-	if isBreaking {
-		summary = strings.TrimPrefix(summary, "BREAKING:")
-		summary = strings.TrimPrefix(summary, "breaking:")
-		summary = strings.TrimSpace(summary)
-	}
-
-	return summary, isBreaking, nil
-}
-
 func formatMessage(changes []RepoChanges, source, dest string) string {
 	var sb strings.Builder
 
@@ -280,7 +233,7 @@ func formatMessage(changes []RepoChanges, source, dest string) string {
 
 	for _, rc := range changes {
 		var emoji string
-		if rc.IsBreaking {
+		if rc.Summary.IsBreaking {
 			emoji = "🚨"
 		} else if rc.Compare.TotalCommits > 10 {
 			emoji = "📚"
@@ -292,8 +245,26 @@ func formatMessage(changes []RepoChanges, source, dest string) string {
 
 		sb.WriteString(fmt.Sprintf("**%s [%s](%s)** (%d commits)\n",
 			emoji, rc.Repo.Name, rc.Repo.HTMLURL, rc.Compare.TotalCommits))
-		sb.WriteString(fmt.Sprintf("%s\n\n", rc.Summary))
+		sb.WriteString(fmt.Sprintf("%s\n\n", rc.Summary.Markdown()))
 	}
 
 	return sb.String()
 }
+
+// openSummaryCache shares the serve database's summaries when its SQLite
+// file exists, so comparisons already summarized by the bot or dashboard are
+// reused. It returns nil when there is no database.
+func openSummaryCache(cfg *config.Config) (*summarizer.Cache, error) {
+	path := cfg.Serve.Dashboard.SQLitePath
+	if path == "" {
+		path = config.DefaultSQLitePath
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+	db, err := database.NewSQLiteDB(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening summary cache: %w", err)
+	}
+	return summarizer.NewCache(db, cfg.Summarizer.CacheTTL), nil
+}
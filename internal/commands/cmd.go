@@ -4,6 +4,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/user/mattermost-tools/internal/commands/changes"
+	"github.com/user/mattermost-tools/internal/commands/db"
+	"github.com/user/mattermost-tools/internal/commands/notes"
 	"github.com/user/mattermost-tools/internal/commands/prs"
 	"github.com/user/mattermost-tools/internal/commands/serve"
 )
@@ -17,6 +19,8 @@ func init() {
 	rootCmd.AddCommand(prs.NewCommand())
 	rootCmd.AddCommand(serve.NewCommand())
 	rootCmd.AddCommand(changes.NewCommand())
+	rootCmd.AddCommand(notes.NewCommand())
+	rootCmd.AddCommand(db.NewCommand())
 }
 
 func Execute() error {
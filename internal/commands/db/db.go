@@ -0,0 +1,166 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/database"
+)
+
+var (
+	configFile string
+	steps      int
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the release database schema",
+		Long: `Apply, roll back and inspect the versioned schema migrations of the release
+database (serve.dashboard.sqlite_path). "serve" applies pending migrations on
+start and refuses to run against a database migrated by a newer build. The
+baseline migration (version 1) cannot be rolled back.
+
+Example:
+  mmtools db status
+  mmtools db migrate
+  mmtools db rollback --steps 1`,
+	}
+
+	cmd.PersistentFlags().StringVarP(&configFile, "config", "c", "config.yaml", "Path to config file")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending migrations",
+		Args:  cobra.NoArgs,
+		RunE:  runMigrate,
+	})
+
+	rollback := &cobra.Command{
+		Use:   "rollback",
+		Short: "Revert the most recent migrations",
+		Args:  cobra.NoArgs,
+		RunE:  runRollback,
+	}
+	rollback.Flags().IntVarP(&steps, "steps", "n", 1, "Number of migrations to revert")
+	cmd.AddCommand(rollback)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "List migrations and whether they are applied",
+		Args:  cobra.NoArgs,
+		RunE:  runStatus,
+	})
+
+	return cmd
+}
+
+// openDB opens the configured database without migrating it. Unless create is
+// set the file must already exist.
+func openDB(create bool) (*gorm.DB, string, error) {
+	cfg, err := config.Load(configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("loading config: %w", err)
+	}
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	path := cfg.Serve.Dashboard.SQLitePath
+	if path == "" {
+		path = config.DefaultSQLitePath
+	}
+	if !create {
+		if _, err := os.Stat(path); err != nil {
+			return nil, "", fmt.Errorf("release database %s: %w", path, err)
+		}
+	}
+	db, err := database.OpenSQLite(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return db, path, nil
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	db, path, err := openDB(true)
+	if err != nil {
+		return err
+	}
+
+	applied, err := database.Migrate(db)
+	for _, m := range applied {
+		fmt.Fprintf(cmd.OutOrStdout(), "Applied %04d %s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s is up to date at version %d\n", path, database.LatestVersion())
+	}
+	return nil
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	if steps < 1 {
+		return fmt.Errorf("--steps must be at least 1")
+	}
+	db, path, err := openDB(false)
+	if err != nil {
+		return err
+	}
+
+	reverted, err := database.Rollback(db, steps)
+	for _, m := range reverted {
+		fmt.Fprintf(cmd.OutOrStdout(), "Rolled back %04d %s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	version, err := database.SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s is at version %d\n", path, version)
+	return nil
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	db, path, err := openDB(false)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := database.Status(db)
+	if err != nil {
+		return err
+	}
+	version, err := database.SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%s: version %d, this build supports up to %d\n\n", path, version, database.LatestVersion())
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.AppliedAt != 0 {
+			applied = time.Unix(s.AppliedAt, 0).Format("2006-01-02 15:04")
+		}
+		if s.Unknown {
+			applied += " (unknown to this build)"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,95 @@
+package notes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/releasenotes"
+)
+
+var (
+	configFile string
+	format     string
+	output     string
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notes <release-id>",
+		Short: "Export release notes as Markdown, HTML or JSON",
+		Long: `Render the release notes of a dashboard release: notes, breaking changes,
+per-repo summaries, PR links, contributors, infra changes, deploy order and
+approvals. Templates in serve.release.notes_templates replace the built-in ones.
+
+Example:
+  mmtools notes 3f2a9c1e-...                      # Markdown to stdout
+  mmtools notes 3f2a9c1e-... -f html -o notes.html`,
+		Args: cobra.ExactArgs(1),
+		RunE: runNotes,
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "config.yaml", "Path to config file")
+	cmd.Flags().StringVarP(&format, "format", "f", releasenotes.FormatMarkdown, "Output format: md, html or json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to this file instead of stdout")
+
+	return cmd
+}
+
+func runNotes(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := config.Load(configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	outputFormat, err := releasenotes.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	renderer, err := releasenotes.NewRenderer(cfg.Serve.Release.NotesTemplates)
+	if err != nil {
+		return fmt.Errorf("loading release notes templates: %w", err)
+	}
+
+	path := cfg.Serve.Dashboard.SQLitePath
+	if path == "" {
+		path = config.DefaultSQLitePath
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("release database %s: %w", path, err)
+	}
+	db, err := database.NewSQLiteDB(path)
+	if err != nil {
+		return fmt.Errorf("opening release database: %w", err)
+	}
+
+	doc, err := dashboard.NewService(db).ReleaseNotes(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("loading release %s: %w", args[0], err)
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := renderer.Render(w, doc, outputFormat); err != nil {
+		return fmt.Errorf("rendering release notes: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package prs
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/user/mattermost-tools/internal/mappings"
 	"github.com/user/mattermost-tools/pkg/github"
+	"github.com/user/mattermost-tools/pkg/mattermost"
 )
 
 type RepoPRs struct {
@@ -20,7 +22,13 @@ type FormatResult struct {
 	UnmappedUsers []string
 }
 
-func FormatMessage(repoPRs []RepoPRs, now time.Time) FormatResult {
+type AttachmentsResult struct {
+	Text          string
+	Attachments   []mattermost.Attachment
+	UnmappedUsers []string
+}
+
+func FormatMessage(ctx context.Context, repoPRs []RepoPRs, now time.Time) FormatResult {
 	var sb strings.Builder
 	unmappedSet := make(map[string]struct{})
 
@@ -44,7 +52,7 @@ func FormatMessage(repoPRs []RepoPRs, now time.Time) FormatResult {
 
 		for _, pr := range rp.PRs {
 			if !isBot(pr.User.Login) {
-				if _, ok := mappings.MattermostFromGitHub(pr.User.Login); !ok {
+				if _, ok := mappings.MattermostFromGitHub(ctx, pr.User.Login); !ok {
 					unmappedSet[pr.User.Login] = struct{}{}
 				}
 			}
@@ -57,39 +65,13 @@ func FormatMessage(repoPRs []RepoPRs, now time.Time) FormatResult {
 
 			stale := formatDuration(staleDuration)
 			age := formatDuration(now.Sub(pr.CreatedAt))
-
-			var waitingOn string
-			if len(pr.RequestedReviewers) == 0 {
-				waitingOn = "No reviewers assigned"
-			} else {
-				var reviewers []string
-				for _, r := range pr.RequestedReviewers {
-					if isBot(r.Login) {
-						continue
-					}
-					if mm, ok := mappings.MattermostFromGitHub(r.Login); ok {
-						reviewers = append(reviewers, "@"+mm)
-					} else {
-						reviewers = append(reviewers, r.Login)
-						unmappedSet[r.Login] = struct{}{}
-					}
-				}
-				if len(reviewers) == 0 {
-					waitingOn = "No reviewers assigned"
-				} else {
-					waitingOn = "Waiting on " + strings.Join(reviewers, ", ")
-				}
-			}
+			waitingOn := formatWaitingOn(ctx, pr, unmappedSet)
 
 			sb.WriteString(fmt.Sprintf("   %s stale · %s old · %s\n\n", stale, age, waitingOn))
 		}
 	}
 
-	var unmappedUsers []string
-	for u := range unmappedSet {
-		unmappedUsers = append(unmappedUsers, u)
-	}
-	sort.Strings(unmappedUsers)
+	unmappedUsers := sortedKeys(unmappedSet)
 
 	if len(unmappedUsers) > 0 {
 		sb.WriteString("---\n\n")
@@ -103,6 +85,94 @@ func FormatMessage(repoPRs []RepoPRs, now time.Time) FormatResult {
 	}
 }
 
+// FormatAttachments renders one message attachment per PR, colored by
+// staleness, for webhooks that support Mattermost message attachments.
+func FormatAttachments(ctx context.Context, repoPRs []RepoPRs, now time.Time) AttachmentsResult {
+	unmappedSet := make(map[string]struct{})
+	var attachments []mattermost.Attachment
+	total := 0
+
+	sort.Slice(repoPRs, func(i, j int) bool {
+		return repoPRs[i].Repo.Name < repoPRs[j].Repo.Name
+	})
+
+	for _, rp := range repoPRs {
+		sort.Slice(rp.PRs, func(i, j int) bool {
+			return now.Sub(rp.PRs[i].UpdatedAt) > now.Sub(rp.PRs[j].UpdatedAt)
+		})
+
+		for _, pr := range rp.PRs {
+			total++
+			if !isBot(pr.User.Login) {
+				if _, ok := mappings.MattermostFromGitHub(ctx, pr.User.Login); !ok {
+					unmappedSet[pr.User.Login] = struct{}{}
+				}
+			}
+
+			staleDuration := now.Sub(pr.UpdatedAt)
+			stale := formatDuration(staleDuration)
+			age := formatDuration(now.Sub(pr.CreatedAt))
+			waitingOn := formatWaitingOn(ctx, pr, unmappedSet)
+
+			attachments = append(attachments, mattermost.Attachment{
+				Fallback:   fmt.Sprintf("%s#%d %s (%s stale)", rp.Repo.Name, pr.Number, pr.Title, stale),
+				Color:      stalenessColor(staleDuration),
+				AuthorName: pr.User.Login,
+				Title:      fmt.Sprintf("%s#%d %s", rp.Repo.Name, pr.Number, pr.Title),
+				TitleLink:  pr.HTMLURL,
+				Text:       waitingOn,
+				Fields: []mattermost.AttachmentField{
+					{Title: "Stale", Value: stale, Short: true},
+					{Title: "Age", Value: age, Short: true},
+				},
+			})
+		}
+	}
+
+	unmappedUsers := sortedKeys(unmappedSet)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("#### %d PRs pending review in %d repositories\n", total, len(repoPRs)))
+	if len(unmappedUsers) > 0 {
+		sb.WriteString(fmt.Sprintf(":warning: **Unmapped GitHub users:** %s\n",
+			strings.Join(unmappedUsers, ", ")))
+	}
+
+	return AttachmentsResult{
+		Text:          sb.String(),
+		Attachments:   attachments,
+		UnmappedUsers: unmappedUsers,
+	}
+}
+
+func formatWaitingOn(ctx context.Context, pr github.PullRequest, unmappedSet map[string]struct{}) string {
+	var reviewers []string
+	for _, r := range pr.RequestedReviewers {
+		if isBot(r.Login) {
+			continue
+		}
+		if mm, ok := mappings.MattermostFromGitHub(ctx, r.Login); ok {
+			reviewers = append(reviewers, "@"+mm)
+		} else {
+			reviewers = append(reviewers, r.Login)
+			unmappedSet[r.Login] = struct{}{}
+		}
+	}
+	if len(reviewers) == 0 {
+		return "No reviewers assigned"
+	}
+	return "Waiting on " + strings.Join(reviewers, ", ")
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	var keys []string
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func isBot(login string) bool {
 	return strings.HasSuffix(login, "[bot]")
 }
@@ -133,3 +203,17 @@ func stalenessEmoji(d time.Duration) string {
 		return "🔴"
 	}
 }
+
+func stalenessColor(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch {
+	case days < 1:
+		return "#2eb886"
+	case days < 3:
+		return "#f2c744"
+	case days < 7:
+		return "#f28c28"
+	default:
+		return "#d0021b"
+	}
+}
@@ -1,6 +1,7 @@
 package prs_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -36,7 +37,7 @@ func TestFormatMessage_Success(t *testing.T) {
 		},
 	}
 
-	result := prs.FormatMessage(repoPRs, now)
+	result := prs.FormatMessage(context.Background(), repoPRs, now)
 
 	require.Contains(t, result.Message, "#### Pending review on [org/repo1]")
 	require.Contains(t, result.Message, "[#123]")
@@ -70,7 +71,7 @@ func TestFormatMessage_NoReviewers(t *testing.T) {
 		},
 	}
 
-	result := prs.FormatMessage(repoPRs, now)
+	result := prs.FormatMessage(context.Background(), repoPRs, now)
 
 	require.Contains(t, result.Message, "No reviewers assigned")
 }
@@ -101,8 +102,54 @@ func TestFormatMessage_UnmappedUsers(t *testing.T) {
 		},
 	}
 
-	result := prs.FormatMessage(repoPRs, now)
+	result := prs.FormatMessage(context.Background(), repoPRs, now)
 
 	require.Contains(t, result.UnmappedUsers, "unmapped-user")
 	require.Contains(t, result.Message, ":warning: **Unmapped GitHub users:**")
 }
+
+func TestFormatAttachments_StalenessColors(t *testing.T) {
+	now := time.Date(2025, 1, 13, 12, 0, 0, 0, time.UTC)
+
+	type tc struct {
+		name      string
+		updatedAt time.Time
+		color     string
+	}
+
+	cases := []tc{
+		{name: "fresh", updatedAt: now.Add(-2 * time.Hour), color: "#2eb886"},
+		{name: "two days", updatedAt: now.AddDate(0, 0, -2), color: "#f2c744"},
+		{name: "five days", updatedAt: now.AddDate(0, 0, -5), color: "#f28c28"},
+		{name: "two weeks", updatedAt: now.AddDate(0, 0, -14), color: "#d0021b"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			repoPRs := []prs.RepoPRs{
+				{
+					Repo: github.Repository{Name: "repo1", FullName: "org/repo1"},
+					PRs: []github.PullRequest{
+						{
+							Number:    7,
+							Title:     "fix: thing",
+							HTMLURL:   "https://github.com/org/repo1/pull/7",
+							CreatedAt: now.AddDate(0, -1, 0),
+							UpdatedAt: c.updatedAt,
+							User:      github.User{Login: "author1"},
+						},
+					},
+				},
+			}
+
+			result := prs.FormatAttachments(context.Background(), repoPRs, now)
+
+			require.Len(t, result.Attachments, 1)
+			require.Equal(t, c.color, result.Attachments[0].Color)
+			require.Equal(t, "repo1#7 fix: thing", result.Attachments[0].Title)
+			require.Equal(t, "https://github.com/org/repo1/pull/7", result.Attachments[0].TitleLink)
+			require.Equal(t, "No reviewers assigned", result.Attachments[0].Text)
+			require.Contains(t, result.Text, "1 PRs pending review in 1 repositories")
+		})
+	}
+}
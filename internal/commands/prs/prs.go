@@ -2,6 +2,7 @@ package prs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -10,6 +11,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/mappings"
 	"github.com/user/mattermost-tools/pkg/github"
 	"github.com/user/mattermost-tools/pkg/mattermost"
 )
@@ -18,6 +22,8 @@ var (
 	configFile  string
 	webhookURL  string
 	ignoreRepos string
+	channels    []string
+	attachments bool
 	dryRun      bool
 )
 
@@ -31,6 +37,8 @@ func NewCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&configFile, "config", "c", "config.yaml", "Path to config file")
 	cmd.Flags().StringVar(&webhookURL, "webhook-url", "", "Mattermost webhook URL (overrides config)")
 	cmd.Flags().StringVar(&ignoreRepos, "ignore-repos", "", "Comma-separated list of repos to ignore (overrides config)")
+	cmd.Flags().StringSliceVar(&channels, "channel", nil, "Channel to post to, repeatable (overrides config)")
+	cmd.Flags().BoolVar(&attachments, "attachments", false, "Render one message attachment per PR (overrides config)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print message to stdout instead of posting")
 
 	return cmd
@@ -81,61 +89,19 @@ func runPRs(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	ghClient := github.NewClient(ghToken)
-
-	fmt.Fprintf(os.Stderr, "Fetching repositories for %s...\n", org)
-	repos, err := ghClient.ListRepositories(ctx, org)
+	store, err := openMappingStore(cfg)
 	if err != nil {
-		return fmt.Errorf("listing repositories: %w", err)
+		return err
+	}
+	if store != nil {
+		mappings.SetStore(store)
 	}
 
-	teamMembersCache := make(map[string][]github.User)
-
-	var repoPRs []RepoPRs
-	for _, repo := range repos {
-		if repo.Archived {
-			continue
-		}
-		if _, ignored := ignoredRepos[repo.Name]; ignored {
-			continue
-		}
-
-		fmt.Fprintf(os.Stderr, "Fetching PRs for %s...\n", repo.Name)
-		prs, err := ghClient.ListPullRequests(ctx, org, repo.Name)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "WARNING: failed to fetch PRs for %s: %v\n", repo.Name, err)
-			continue
-		}
-
-		var openPRs []github.PullRequest
-		for _, pr := range prs {
-			if pr.Draft {
-				continue
-			}
-
-			for _, team := range pr.RequestedTeams {
-				members, ok := teamMembersCache[team.Slug]
-				if !ok {
-					fmt.Fprintf(os.Stderr, "Fetching members for team %s...\n", team.Slug)
-					members, err = ghClient.ListTeamMembers(ctx, org, team.Slug)
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "WARNING: failed to fetch team members for %s: %v\n", team.Slug, err)
-						members = []github.User{}
-					}
-					teamMembersCache[team.Slug] = members
-				}
-				pr.RequestedReviewers = append(pr.RequestedReviewers, members...)
-			}
-
-			openPRs = append(openPRs, pr)
-		}
+	ghClient := github.NewClient(ghToken)
 
-		if len(openPRs) > 0 {
-			repoPRs = append(repoPRs, RepoPRs{
-				Repo: repo,
-				PRs:  openPRs,
-			})
-		}
+	repoPRs, err := CollectPendingPRs(ctx, ghClient, org, ignoredRepos, os.Stderr)
+	if err != nil {
+		return err
 	}
 
 	if len(repoPRs) == 0 {
@@ -143,23 +109,76 @@ func runPRs(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	result := FormatMessage(repoPRs, time.Now())
+	now := time.Now()
+	useAttachments := attachments || cfg.PRs.Attachments
+
+	msg, unmappedUsers := BuildReminder(ctx, repoPRs, now, useAttachments, cfg.PRs)
 
-	if len(result.UnmappedUsers) > 0 {
+	if len(unmappedUsers) > 0 {
 		fmt.Fprintf(os.Stderr, "WARNING: unmapped GitHub users: %s\n",
-			strings.Join(result.UnmappedUsers, ", "))
+			strings.Join(unmappedUsers, ", "))
+	}
+
+	targets := channels
+	if len(targets) == 0 {
+		targets = cfg.PRs.Channels
+	}
+	if len(targets) == 0 {
+		targets = []string{""}
 	}
 
 	if dryRun {
-		fmt.Println(result.Message)
+		if useAttachments {
+			out, err := json.MarshalIndent(msg, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshaling message: %w", err)
+			}
+			fmt.Println(string(out))
+		} else {
+			fmt.Println(msg.Text)
+		}
 		return nil
 	}
 
 	webhookClient := mattermost.NewWebhook(webhook)
-	if err := webhookClient.Post(ctx, result.Message); err != nil {
-		return fmt.Errorf("posting to Mattermost: %w", err)
+	var failed []string
+	for _, channel := range targets {
+		msg.Channel = channel
+		if err := webhookClient.Send(ctx, msg); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: posting to %s: %v\n", channelLabel(channel), err)
+			failed = append(failed, channelLabel(channel))
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Posted PR reminder to %s.\n", channelLabel(channel))
 	}
 
-	fmt.Fprintf(os.Stderr, "Posted PR reminder to Mattermost.\n")
+	if len(failed) > 0 {
+		return fmt.Errorf("posting to Mattermost failed for: %s", strings.Join(failed, ", "))
+	}
 	return nil
 }
+
+// openMappingStore reads the accounts users linked with the bot from the
+// serve database when its SQLite file exists. It returns nil when there is no
+// database, leaving only the static mappings.
+func openMappingStore(cfg *config.Config) (mappings.Store, error) {
+	path := cfg.Serve.Dashboard.SQLitePath
+	if path == "" {
+		path = config.DefaultSQLitePath
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+	db, err := database.NewSQLiteDB(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening release database: %w", err)
+	}
+	return dashboard.NewService(db), nil
+}
+
+func channelLabel(channel string) string {
+	if channel == "" {
+		return "webhook default channel"
+	}
+	return channel
+}
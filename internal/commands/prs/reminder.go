@@ -0,0 +1,96 @@
+package prs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/pkg/github"
+	"github.com/user/mattermost-tools/pkg/mattermost"
+)
+
+// CollectPendingPRs fetches open, non-draft PRs of every active repo in the
+// org, expanding requested teams into their members. Progress and warnings
+// are written to logw.
+func CollectPendingPRs(ctx context.Context, ghClient *github.Client, org string, ignoredRepos map[string]struct{}, logw io.Writer) ([]RepoPRs, error) {
+	fmt.Fprintf(logw, "Fetching repositories for %s...\n", org)
+	repos, err := ghClient.ListRepositories(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("listing repositories: %w", err)
+	}
+
+	teamMembersCache := make(map[string][]github.User)
+
+	var repoPRs []RepoPRs
+	for _, repo := range repos {
+		if repo.Archived {
+			continue
+		}
+		if _, ignored := ignoredRepos[repo.Name]; ignored {
+			continue
+		}
+
+		fmt.Fprintf(logw, "Fetching PRs for %s...\n", repo.Name)
+		prs, err := ghClient.ListPullRequests(ctx, org, repo.Name)
+		if err != nil {
+			fmt.Fprintf(logw, "WARNING: failed to fetch PRs for %s: %v\n", repo.Name, err)
+			continue
+		}
+
+		var openPRs []github.PullRequest
+		for _, pr := range prs {
+			if pr.Draft {
+				continue
+			}
+
+			for _, team := range pr.RequestedTeams {
+				members, ok := teamMembersCache[team.Slug]
+				if !ok {
+					fmt.Fprintf(logw, "Fetching members for team %s...\n", team.Slug)
+					members, err = ghClient.ListTeamMembers(ctx, org, team.Slug)
+					if err != nil {
+						fmt.Fprintf(logw, "WARNING: failed to fetch team members for %s: %v\n", team.Slug, err)
+						members = []github.User{}
+					}
+					teamMembersCache[team.Slug] = members
+				}
+				pr.RequestedReviewers = append(pr.RequestedReviewers, members...)
+			}
+
+			openPRs = append(openPRs, pr)
+		}
+
+		if len(openPRs) > 0 {
+			repoPRs = append(repoPRs, RepoPRs{
+				Repo: repo,
+				PRs:  openPRs,
+			})
+		}
+	}
+
+	return repoPRs, nil
+}
+
+// BuildReminder renders the reminder as a webhook message with the identity
+// overrides from cfg. It also returns GitHub users without a Mattermost
+// mapping.
+func BuildReminder(ctx context.Context, repoPRs []RepoPRs, now time.Time, useAttachments bool, cfg config.PRsConfig) (mattermost.WebhookMessage, []string) {
+	var msg mattermost.WebhookMessage
+	var unmappedUsers []string
+	if useAttachments {
+		result := FormatAttachments(ctx, repoPRs, now)
+		msg.Text = result.Text
+		msg.Attachments = result.Attachments
+		unmappedUsers = result.UnmappedUsers
+	} else {
+		result := FormatMessage(ctx, repoPRs, now)
+		msg.Text = result.Message
+		unmappedUsers = result.UnmappedUsers
+	}
+	msg.Username = cfg.Username
+	msg.IconURL = cfg.IconURL
+
+	return msg, unmappedUsers
+}
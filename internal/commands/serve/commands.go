@@ -0,0 +1,732 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/jobs"
+	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/internal/mappings"
+	"github.com/user/mattermost-tools/internal/permissions"
+	"github.com/user/mattermost-tools/internal/summarizer"
+	"github.com/user/mattermost-tools/pkg/github"
+	"github.com/user/mattermost-tools/pkg/mattermost"
+	"github.com/user/mattermost-tools/pkg/release"
+)
+
+// botCommands holds the dependencies shared by the bot command handlers.
+type botCommands struct {
+	ghClient        *github.Client
+	org             string
+	ignoredRepos    map[string]struct{}
+	mmBot           *mattermost.Bot
+	releaseManager  *release.Manager
+	dashboardServer *dashboard.Server
+	releaseChannels *dashboard.ReleaseChannels
+	argocdTracker   *dashboard.ArgoCDTracker
+	baseURL         string
+	linkVerify      string
+	linkChallenges  *linkChallenges
+	jobs            *jobs.Manager
+	permissions     *permissions.Checker
+	freezeLocation  *time.Location
+	summarizer      summarizer.Summarizer
+	prSummaryBots   []string
+}
+
+func (c *botCommands) register(r *Router) {
+	r.Register(&Command{
+		Name:        "help",
+		Aliases:     []string{"h", "-h", "--help"},
+		Args:        "[command]",
+		Description: "List the commands you can use, or show details of one",
+		Example:     "help create-release",
+		MaxArgs:     1,
+		Handler:     r.HelpHandler,
+	})
+	r.Register(&Command{
+		Name:        "do-not-touch",
+		Aliases:     []string{"dnt"},
+		Description: "Do NOT use this command",
+		MaxArgs:     -1,
+		Handler:     c.doNotTouch,
+	})
+	r.Register(&Command{
+		Name:        "reviews",
+		Description: "Show PRs waiting for your review",
+		MaxArgs:     0,
+		Handler:     c.reviews,
+	})
+	r.Register(&Command{
+		Name:        "summarize-pr",
+		Aliases:     []string{"summarize", "summary"},
+		Args:        "<github-pr-url>",
+		Description: "Summarize a GitHub PR",
+		Example:     "summarize-pr https://github.com/org/repo/pull/123",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Details: "Shows the latest comment by one of the bots in `summarizer.pr_summary_bots` (default gemini-code-assist[bot]); " +
+			"without one, generates a summary from the PR's title, description, commits and diff.",
+		Handler: c.summarizePR,
+	})
+	r.Register(&Command{
+		Name:        "changes",
+		Args:        "<source-branch> <dest-branch>",
+		Description: "Compare branches and summarize undeployed changes",
+		Example:     "changes uat master",
+		MinArgs:     2,
+		MaxArgs:     2,
+		NeedsBot:    true,
+		Handler:     c.changes,
+	})
+	r.Register(&Command{
+		Name:        "release-prs",
+		Aliases:     []string{"releases", "pending-releases"},
+		Args:        "<source-branch> <dest-branch>",
+		Description: "Check for release PRs between branches",
+		Example:     "release-prs uat master",
+		MinArgs:     2,
+		MaxArgs:     2,
+		NeedsBot:    true,
+		Handler:     c.releasePRs,
+	})
+	r.Register(&Command{
+		Name:        "create-release",
+		Aliases:     []string{"new-release"},
+		Args:        "<source-branch> <dest-branch> | --hotfix <dest-branch> <repo@ref>... [--override-freeze]",
+		Description: "Create a release, or a hotfix release of selected repos from their own branch, tag or SHA",
+		Example:     "create-release --hotfix master auth-service@fix/token-expiry billing@v1.4.2",
+		MinArgs:     2,
+		MaxArgs:     -1,
+		Details: "A release compares every repository between the two branches. A hotfix release (`--hotfix`) " +
+			"includes only the listed repos, each compared from its own ref, and needs the approvals in `release.hotfix_approvals`.\n" +
+			"Add `--override-freeze` to create a release during a deployment freeze; this needs the `freeze-override` permission and is recorded in the history.",
+		NeedsBot: true,
+		Handler:  c.createRelease,
+	})
+	r.Register(&Command{
+		Name:        "refresh",
+		Description: "Refresh release status (in release channel)",
+		MaxArgs:     0,
+		NeedsBot:    true,
+		Handler:     c.refresh,
+	})
+	c.registerReleaseCommands(r)
+	c.registerLinkCommands(r)
+	c.registerJobCommands(r)
+	c.registerFreezeCommands(r)
+}
+
+func (c *botCommands) doNotTouch(ctx context.Context, req *Request, resp Responder) {
+	catURL := fmt.Sprintf("https://cataas.com/cat?t=%d", time.Now().UnixNano())
+	resp.Reply(ctx, fmt.Sprintf("🚨 **INCIDENT REPORTED**\n\n@%s touched the bot. This incident has been logged and will be reported to the appropriate authorities.\n\n![angry cat](%s)", req.UserName, catURL))
+}
+
+func (c *botCommands) reviews(ctx context.Context, req *Request, resp Responder) {
+	ghUsername, ok := mappings.GitHubFromMattermost(ctx, req.UserName)
+	if !ok {
+		resp.Ephemeral(ctx, fmt.Sprintf("Your Mattermost username (%s) is not mapped to a GitHub account.", req.UserName))
+		return
+	}
+
+	myPRs, err := c.findReviewPRs(ctx, ghUsername)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to fetch repositories: %v", err))
+		return
+	}
+
+	if len(myPRs) == 0 {
+		resp.Ephemeral(ctx, "🎉 No PRs waiting for your review!")
+		return
+	}
+
+	resp.Ephemeral(ctx, formatReviewsList(myPRs))
+}
+
+func (c *botCommands) findReviewPRs(ctx context.Context, ghUsername string) ([]reviewPR, error) {
+	repos, err := c.ghClient.ListRepositories(ctx, c.org)
+	if err != nil {
+		return nil, err
+	}
+
+	var myPRs []reviewPR
+	now := time.Now()
+
+	for _, repo := range repos {
+		if repo.Archived {
+			continue
+		}
+		if _, ignored := c.ignoredRepos[repo.Name]; ignored {
+			continue
+		}
+
+		prs, err := c.ghClient.ListPullRequests(ctx, c.org, repo.Name)
+		if err != nil {
+			continue
+		}
+
+		for _, pr := range prs {
+			if pr.Draft {
+				continue
+			}
+
+			for _, reviewer := range pr.RequestedReviewers {
+				if reviewer.Login == ghUsername {
+					myPRs = append(myPRs, reviewPR{
+						Repo:      repo,
+						PR:        pr,
+						Staleness: now.Sub(pr.UpdatedAt),
+					})
+					break
+				}
+			}
+		}
+	}
+
+	return myPRs, nil
+}
+
+func (c *botCommands) summarizePR(ctx context.Context, req *Request, resp Responder) {
+	owner, repo, number, ok := parsePRURL(req.Args[0])
+	if !ok {
+		resp.Ephemeral(ctx, "Invalid PR URL. Expected format: https://github.com/owner/repo/pull/123")
+		return
+	}
+	prLink := fmt.Sprintf("[%s/%s#%s](https://github.com/%s/%s/pull/%s)", owner, repo, number, owner, repo, number)
+
+	comments, err := c.ghClient.GetPRComments(ctx, owner, repo, number)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to fetch PR comments: %v", err))
+		return
+	}
+
+	if comment := latestCommentBy(comments, c.prSummaryBots); comment != nil {
+		resp.Reply(ctx, fmt.Sprintf("**PR Summary** (%s)\n\n%s", prLink, comment.Body))
+		return
+	}
+
+	if c.summarizer == nil {
+		resp.Ephemeral(ctx, "No summary comment found for this PR.")
+		return
+	}
+	resp.Ephemeral(ctx, fmt.Sprintf("⏳ No summary comment on %s, generating one...", prLink))
+	go c.generatePRSummary(resp, req.UserName, owner, repo, number, prLink)
+}
+
+// latestCommentBy returns the newest comment by one of logins, or nil.
+func latestCommentBy(comments []github.IssueComment, logins []string) *github.IssueComment {
+	for i := len(comments) - 1; i >= 0; i-- {
+		if slices.Contains(logins, comments[i].User.Login) {
+			return &comments[i]
+		}
+	}
+	return nil
+}
+
+// generatePRSummary summarizes a pull request from its title, description,
+// commits and diff. Like other summaries it is cached per merge base and
+// head commit, so it is regenerated only when the PR gets new commits.
+func (c *botCommands) generatePRSummary(resp Responder, userName, owner, repo, number, prLink string) {
+	ctx := context.Background()
+
+	pr, err := c.ghClient.GetPullRequest(ctx, owner, repo, number)
+	if err != nil {
+		resp.Reply(ctx, fmt.Sprintf("@%s ❌ Failed to fetch %s: %v", userName, prLink, err))
+		return
+	}
+
+	compare, err := c.ghClient.CompareBranches(ctx, owner, repo, pr.Base.SHA, pr.Head.SHA)
+	if err != nil {
+		resp.Reply(ctx, fmt.Sprintf("@%s ❌ Failed to fetch the changes of %s: %v", userName, prLink, err))
+		return
+	}
+	if compare == nil {
+		resp.Reply(ctx, fmt.Sprintf("@%s ✅ No changes found in %s", userName, prLink))
+		return
+	}
+
+	result, err := summarizer.Summarize(ctx, c.summarizer, summarizer.Input{
+		Repo:        owner + "/" + repo,
+		Compare:     compare,
+		Title:       pr.Title,
+		Description: pr.Body,
+	})
+	if err != nil {
+		logger.Warn().Err(err).Str("pr", prLink).Msg("PR summary failed")
+	}
+
+	resp.Reply(ctx, fmt.Sprintf("**PR Summary** (%s) · _generated_\n\n%s", prLink, result.Markdown()))
+}
+
+func (c *botCommands) changes(ctx context.Context, req *Request, resp Responder) {
+	sourceBranch, destBranch := req.Args[0], req.Args[1]
+	job, err := c.jobs.Enqueue(ctx, jobTypeChanges, jobTarget(req), branchArgs{Source: sourceBranch, Dest: destBranch})
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to queue job: %v", err))
+		return
+	}
+	resp.Ephemeral(ctx, fmt.Sprintf("⏳ Analyzing changes from `%s` to `%s`... Progress will be posted here. (job `%s`)", sourceBranch, destBranch, shortJobID(job.ID)))
+}
+
+func (c *botCommands) releasePRs(ctx context.Context, req *Request, resp Responder) {
+	sourceBranch, destBranch := req.Args[0], req.Args[1]
+	resp.Ephemeral(ctx, fmt.Sprintf("⏳ Checking release PRs from `%s` to `%s`...", sourceBranch, destBranch))
+	go c.processReleasePRsAsync(resp, req.UserName, sourceBranch, destBranch)
+}
+
+func (c *botCommands) createRelease(ctx context.Context, req *Request, resp Responder) {
+	if c.dashboardServer == nil {
+		resp.Ephemeral(ctx, "Dashboard not configured.")
+		return
+	}
+	args, err := parseCreateReleaseArgs(req.Args)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("❌ %s\nSee `%shelp create-release` for details.", err, req.Prefix))
+		return
+	}
+	if args.OverrideFreeze && !c.canOverrideFreeze(ctx, req) {
+		resp.Ephemeral(ctx, "⛔ You are not allowed to override deployment freezes.")
+		return
+	}
+	if !args.OverrideFreeze {
+		window, err := c.dashboardServer.Service().ActiveFreeze(ctx, time.Now())
+		if err != nil {
+			resp.Ephemeral(ctx, fmt.Sprintf("Failed to check freezes: %v", err))
+			return
+		}
+		if window != nil && freezeRefusal(ctx, resp, "create a release", &dashboard.FreezeError{Window: *window}) {
+			return
+		}
+	}
+	job, err := c.jobs.Enqueue(ctx, jobTypeCreateRelease, jobTarget(req), args)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to queue job: %v", err))
+		return
+	}
+	if args.HotfixRepos != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Creating hotfix release of %d repos to `%s`... (job `%s`)", len(args.HotfixRepos), args.Dest, shortJobID(job.ID)))
+		return
+	}
+	resp.Ephemeral(ctx, fmt.Sprintf("Creating release from `%s` to `%s`... (job `%s`)", args.Source, args.Dest, shortJobID(job.ID)))
+}
+
+type createReleaseArgs struct {
+	Source      string                `json:"source"`
+	Dest        string                `json:"dest"`
+	HotfixRepos []database.HotfixRepo `json:"hotfix_repos,omitempty"`
+	// OverrideFreeze is checked against the requester's permissions before
+	// the job is queued.
+	OverrideFreeze bool `json:"override_freeze,omitempty"`
+}
+
+// parseCreateReleaseArgs reads "<source> <dest>" or
+// "--hotfix <dest> <repo@ref>...", either followed by an optional
+// --override-freeze.
+func parseCreateReleaseArgs(args []string) (createReleaseArgs, error) {
+	args, override := stripOverrideFreeze(args)
+	if len(args) == 0 || args[0] != "--hotfix" {
+		if len(args) != 2 {
+			return createReleaseArgs{}, errors.New("usage: `create-release <source-branch> <dest-branch>` or `create-release --hotfix <dest-branch> <repo@ref>...`")
+		}
+		return createReleaseArgs{Source: args[0], Dest: args[1], OverrideFreeze: override}, nil
+	}
+
+	if len(args) < 3 {
+		return createReleaseArgs{}, errors.New("usage: `create-release --hotfix <dest-branch> <repo@ref>...`")
+	}
+	parsed := createReleaseArgs{Dest: args[1], HotfixRepos: []database.HotfixRepo{}, OverrideFreeze: override}
+	seen := make(map[string]struct{})
+	for _, arg := range args[2:] {
+		repo, ref, ok := strings.Cut(arg, "@")
+		if !ok || repo == "" || ref == "" {
+			return createReleaseArgs{}, fmt.Errorf("invalid hotfix repo `%s`, use `<repo>@<branch|tag|sha>`", arg)
+		}
+		if _, dup := seen[repo]; dup {
+			return createReleaseArgs{}, fmt.Errorf("repo `%s` is listed twice", repo)
+		}
+		seen[repo] = struct{}{}
+		parsed.HotfixRepos = append(parsed.HotfixRepos, database.HotfixRepo{Repo: repo, Ref: ref})
+	}
+	return parsed, nil
+}
+
+func (c *botCommands) refresh(ctx context.Context, req *Request, resp Responder) {
+	if c.releaseManager == nil {
+		resp.Ephemeral(ctx, "Release management not configured.")
+		return
+	}
+	if rel := c.releaseManager.GetReleaseByChannel(req.ChannelID); rel == nil {
+		resp.Ephemeral(ctx, "No active release in this channel.")
+		return
+	}
+	resp.Ephemeral(ctx, "Refreshing release status...")
+	go c.processRefreshReleaseAsync(resp, req.ChannelID)
+}
+
+func (c *botCommands) filteredRepos(ctx context.Context) ([]github.Repository, error) {
+	repos, err := c.ghClient.ListRepositories(ctx, c.org)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []github.Repository
+	for _, repo := range repos {
+		if repo.Archived {
+			continue
+		}
+		if _, ignored := c.ignoredRepos[repo.Name]; ignored {
+			continue
+		}
+		filtered = append(filtered, repo)
+	}
+	return filtered, nil
+}
+
+type repoChange struct {
+	Repo    github.Repository
+	Compare *github.CompareResult
+	Summary summarizer.Result
+}
+
+func (c *botCommands) runChangesJob(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+	var args branchArgs
+	if err := job.GetArgs(&args); err != nil {
+		return err
+	}
+	userName := job.RequestedBy
+
+	pp := c.newProgressPost(job, p, fmt.Sprintf("Analyzing changes `%s` → `%s`", args.Source, args.Dest))
+	pp.start(ctx)
+
+	results, err := c.collectChanges(ctx, args.Source, args.Dest, pp)
+	if err != nil {
+		pp.finish(context.Background(), jobFailureMessage(ctx, job, "Failed to fetch repositories", err))
+		return err
+	}
+
+	if len(results) == 0 {
+		pp.finish(ctx, fmt.Sprintf("@%s ✅ No changes found between `%s` and `%s`", userName, args.Source, args.Dest))
+		return nil
+	}
+
+	pp.finish(ctx, formatChanges(args.Source, args.Dest, results))
+
+	if c.releaseManager != nil {
+		// Best-effort refresh: if this channel has an active release, update it with current data.
+		// Errors are intentionally ignored since the changes command already succeeded.
+		_, _ = c.releaseManager.RefreshRelease(ctx, job.ChannelID)
+	}
+	return nil
+}
+
+// collectChanges compares the branches in every repo and summarizes the ones
+// with changes. pp may be nil.
+func (c *botCommands) collectChanges(ctx context.Context, sourceBranch, destBranch string, pp *progressPost) ([]repoChange, error) {
+	filteredRepos, err := c.filteredRepos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pp.SetTotal(len(filteredRepos))
+
+	var (
+		results []repoChange
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, 4)
+	)
+
+	for _, repo := range filteredRepos {
+		wg.Add(1)
+		go func(repo github.Repository) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			compare, err := c.ghClient.CompareBranches(ctx, c.org, repo.Name, destBranch, sourceBranch)
+			if err != nil {
+				pp.Failed()
+				return
+			}
+			if compare == nil || compare.TotalCommits == 0 || len(compare.Files) == 0 {
+				pp.Scanned(false)
+				return
+			}
+			pp.Scanned(true)
+
+			summary, err := generateChangeSummary(ctx, c.summarizer, repo.Name, compare)
+			pp.Summarized(err == nil)
+
+			mu.Lock()
+			results = append(results, repoChange{
+				Repo:    repo,
+				Compare: compare,
+				Summary: summary,
+			})
+			mu.Unlock()
+		}(repo)
+	}
+
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func formatChanges(sourceBranch, destBranch string, results []repoChange) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### 📦 Undeployed Changes: `%s` → `%s`\n\n", sourceBranch, destBranch))
+	sb.WriteString(fmt.Sprintf("Found changes in **%d** repositories:\n\n", len(results)))
+
+	for _, rc := range results {
+		var emoji string
+		if rc.Summary.IsBreaking {
+			emoji = "🚨"
+		} else if rc.Compare.TotalCommits > 10 {
+			emoji = "📚"
+		} else if rc.Compare.TotalCommits > 5 {
+			emoji = "📝"
+		} else {
+			emoji = "📄"
+		}
+
+		sb.WriteString(fmt.Sprintf("**%s [%s](%s)** (%d commits)\n",
+			emoji, rc.Repo.Name, rc.Repo.HTMLURL, rc.Compare.TotalCommits))
+		sb.WriteString(fmt.Sprintf("%s\n\n", rc.Summary.Markdown()))
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+func (c *botCommands) processReleasePRsAsync(resp Responder, userName, sourceBranch, destBranch string) {
+	ctx := context.Background()
+
+	filteredRepos, err := c.filteredRepos(ctx)
+	if err != nil {
+		resp.Reply(ctx, fmt.Sprintf("@%s ❌ Failed to fetch repositories: %v", userName, err))
+		return
+	}
+
+	type repoStatus struct {
+		Repo    github.Repository
+		Commits int
+		PR      *github.PullRequest
+	}
+
+	var (
+		results []repoStatus
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, 4)
+	)
+
+	for _, repo := range filteredRepos {
+		wg.Add(1)
+		go func(repo github.Repository) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			compare, err := c.ghClient.CompareBranches(ctx, c.org, repo.Name, destBranch, sourceBranch)
+			if err != nil || compare == nil || compare.TotalCommits == 0 || len(compare.Files) == 0 {
+				return
+			}
+
+			pr, _ := c.ghClient.FindPullRequest(ctx, c.org, repo.Name, sourceBranch, destBranch)
+
+			mu.Lock()
+			results = append(results, repoStatus{
+				Repo:    repo,
+				Commits: compare.TotalCommits,
+				PR:      pr,
+			})
+			mu.Unlock()
+		}(repo)
+	}
+
+	wg.Wait()
+
+	if len(results) == 0 {
+		resp.Reply(ctx, fmt.Sprintf("@%s ✅ No pending changes between `%s` and `%s`", userName, sourceBranch, destBranch))
+		return
+	}
+
+	var withPR, withoutPR []repoStatus
+	for _, r := range results {
+		if r.PR != nil {
+			withPR = append(withPR, r)
+		} else {
+			withoutPR = append(withoutPR, r)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### 🚀 Release PRs: `%s` → `%s`\n\n", sourceBranch, destBranch))
+
+	if len(withoutPR) > 0 {
+		sb.WriteString(fmt.Sprintf("**⚠️ Missing Release PRs (%d)**\n", len(withoutPR)))
+		for _, r := range withoutPR {
+			sb.WriteString(fmt.Sprintf("- [%s](%s) - %d commits, no PR\n", r.Repo.Name, r.Repo.HTMLURL, r.Commits))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(withPR) > 0 {
+		sb.WriteString(fmt.Sprintf("**✅ Open Release PRs (%d)**\n", len(withPR)))
+		for _, r := range withPR {
+			sb.WriteString(fmt.Sprintf("- [%s#%d](%s) - %d commits\n", r.Repo.Name, r.PR.Number, r.PR.HTMLURL, r.Commits))
+		}
+	}
+
+	resp.Reply(ctx, strings.TrimSpace(sb.String()))
+}
+
+// runCreateReleaseJob is not retried: a second attempt would create a second
+// release.
+func (c *botCommands) runCreateReleaseJob(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+	var args createReleaseArgs
+	if err := job.GetArgs(&args); err != nil {
+		return err
+	}
+	channelID, threadID, userName := job.ChannelID, job.ThreadID, job.RequestedBy
+	sourceBranch, destBranch := args.Source, args.Dest
+	log := logger.Get()
+	dashboardSvc := c.dashboardServer.Service()
+
+	log.Info().
+		Str("user", userName).
+		Str("source", sourceBranch).
+		Str("dest", destBranch).
+		Str("channel", channelID).
+		Msg("Creating release")
+
+	title := fmt.Sprintf("Creating release `%s` → `%s`", sourceBranch, destBranch)
+	if args.HotfixRepos != nil {
+		title = fmt.Sprintf("Creating hotfix release of %d repos to `%s`", len(args.HotfixRepos), destBranch)
+	}
+	pp := c.newProgressPost(job, p, title)
+	pp.start(ctx)
+
+	ownerUser, err := c.mmBot.GetUserByUsername(ctx, userName)
+	if err != nil || ownerUser == nil {
+		log.Error().Err(err).Str("user", userName).Msg("Failed to find user")
+		pp.finish(ctx, "❌ Failed to find user @"+userName)
+		if err == nil {
+			err = fmt.Errorf("user %s not found", userName)
+		}
+		return err
+	}
+
+	rel, err := dashboardSvc.CreateRelease(ctx, dashboard.CreateReleaseRequest{
+		SourceBranch:   sourceBranch,
+		DestBranch:     destBranch,
+		CreatedBy:      ownerUser.ID,
+		ChannelID:      channelID,
+		HotfixRepos:    args.HotfixRepos,
+		OverrideFreeze: args.OverrideFreeze,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create release")
+		pp.finish(ctx, "❌ Failed to create release: "+err.Error())
+		return err
+	}
+
+	log.Info().Str("release_id", rel.ID).Msg("Release created, gathering repo data")
+
+	var repos []dashboard.RepoData
+	if rel.IsHotfix {
+		targets := make([]repoTarget, 0, len(args.HotfixRepos))
+		for _, r := range args.HotfixRepos {
+			targets = append(targets, repoTarget{Name: r.Repo, Ref: r.Ref})
+		}
+		repos, err = gatherRepoTargets(ctx, c.ghClient, c.summarizer, c.org, targets, destBranch, pp)
+	} else {
+		repos, err = gatherRepoData(ctx, c.ghClient, c.summarizer, c.org, c.ignoredRepos, sourceBranch, destBranch, pp)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("release_id", rel.ID).Msg("Failed to gather repos")
+		pp.finish(context.Background(), jobFailureMessage(ctx, job, "Failed to gather repos", err))
+		return err
+	}
+
+	log.Info().Str("release_id", rel.ID).Int("repo_count", len(repos)).Msg("Repos gathered, saving to database")
+
+	if err := dashboardSvc.AddRepos(ctx, rel.ID, repos); err != nil {
+		log.Error().Err(err).Str("release_id", rel.ID).Msg("Failed to add repos")
+		pp.finish(ctx, "❌ Failed to add repos: "+err.Error())
+		return err
+	}
+
+	releaseURL := c.releaseURL(rel.ID)
+	message := "## Release: `" + sourceBranch + "` → `" + destBranch + "`\n**Repositories:** " +
+		strconv.Itoa(len(repos)) + "\n[View Dashboard](" + releaseURL + ")"
+	if rel.IsHotfix {
+		message = formatHotfixCreated(rel, args.HotfixRepos, repos, c.dashboardServer.Service().RequiredApprovals(rel), releaseURL)
+	}
+
+	if c.releaseChannels != nil {
+		channel, err := c.releaseChannels.Create(ctx, rel.ID, userName)
+		if err != nil {
+			log.Error().Err(err).Str("release_id", rel.ID).Msg("Failed to create release channel")
+			message += "\n\n⚠️ Failed to create a dedicated release channel: " + err.Error()
+		} else {
+			if err := c.mmBot.PostMessage(ctx, channel.ID, message+"\n\n_Requested by @"+userName+"_"); err != nil {
+				log.Error().Err(err).Msg("Failed to post message to release channel")
+			}
+			message = "Release channel created: ~" + channel.Name + "\n[View Dashboard](" + releaseURL + ")"
+		}
+	}
+
+	log.Info().Str("release_id", rel.ID).Str("url", releaseURL).Msg("Posting release message")
+	pp.finish(ctx, message)
+
+	if threadID != "" {
+		dashboardSvc.SetMattermostPostID(ctx, rel.ID, threadID)
+	}
+	log.Info().Str("release_id", rel.ID).Msg("Release creation complete")
+	return nil
+}
+
+func formatHotfixCreated(rel *database.Release, requested []database.HotfixRepo, repos []dashboard.RepoData, approvals []string, releaseURL string) string {
+	found := make(map[string]struct{}, len(repos))
+	for _, r := range repos {
+		found[r.RepoName] = struct{}{}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## 🩹 Hotfix → `%s`\n", rel.DestBranch))
+	for _, r := range requested {
+		mark := "✅"
+		if _, ok := found[r.Repo]; !ok {
+			mark = "⚠️ no changes or ref not found"
+		}
+		sb.WriteString(fmt.Sprintf("- `%s` @ `%s` %s\n", r.Repo, r.Ref, mark))
+	}
+	sb.WriteString(fmt.Sprintf("**Approvals needed:** %s\n", strings.ToUpper(strings.Join(approvals, ", "))))
+	sb.WriteString("[View Dashboard](" + releaseURL + ")")
+	return sb.String()
+}
+
+func (c *botCommands) processRefreshReleaseAsync(resp Responder, channelID string) {
+	ctx := context.Background()
+
+	if _, err := c.releaseManager.RefreshRelease(ctx, channelID); err != nil {
+		resp.Reply(ctx, fmt.Sprintf("Failed to refresh: %v", err))
+		return
+	}
+
+	resp.Reply(ctx, "✅ Release summary updated.")
+}
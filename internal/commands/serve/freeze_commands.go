@@ -0,0 +1,286 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/permissions"
+)
+
+// overrideFreezeFlag lets approve and create-release go ahead during a freeze.
+const overrideFreezeFlag = "--override-freeze"
+
+func (c *botCommands) registerFreezeCommands(r *Router) {
+	r.Register(&Command{
+		Name:        "freezes",
+		Description: "Show the active and upcoming deployment freezes",
+		MaxArgs:     0,
+		Handler:     c.listFreezes,
+	})
+	r.Register(&Command{
+		Name:        "freeze",
+		Args:        "<start|now> <end|duration> <reason>",
+		Description: "Add a deployment freeze window; times are YYYY-MM-DD or YYYY-MM-DDTHH:MM",
+		Example:     "freeze 2026-12-24 2027-01-02 Holidays",
+		MinArgs:     3,
+		MaxArgs:     -1,
+		Details: "Times are in the `freeze.timezone` of the config; a date alone means midnight. " +
+			"The end can also be a duration after the start, such as `2h` or `3d`. While a freeze is active, " +
+			"releases cannot be created or approved and their PRs are marked unmergeable.",
+		Handler: c.addFreeze,
+	})
+	r.Register(&Command{
+		Name:        "unfreeze",
+		Args:        "<freeze-id>",
+		Description: "Remove a deployment freeze window added via chat or the dashboard",
+		Details:     "Only the freeze's owner can remove it, unless the `freeze` permission rule explicitly allows you.",
+		Example:     "unfreeze 3",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Permission:  dashboard.FreezePermission,
+		Handler:     c.removeFreeze,
+	})
+	r.Register(&Command{
+		Name:        "override-freeze",
+		Description: "Allow the PRs of the release in this channel or thread to be merged during the current freeze",
+		MaxArgs:     0,
+		Permission:  dashboard.FreezeOverridePermission,
+		Handler:     c.overrideFreeze,
+	})
+}
+
+// canOverrideFreeze reports whether the requester may override freezes,
+// which needs an explicit allow rule.
+func (c *botCommands) canOverrideFreeze(ctx context.Context, req *Request) bool {
+	return c.permissions.Granted(ctx, dashboard.FreezeOverridePermission, permissions.Subject{
+		UserName:  req.UserName,
+		TeamID:    req.TeamID,
+		ChannelID: req.ChannelID,
+	})
+}
+
+// stripOverrideFreeze removes the --override-freeze flag from args and
+// reports whether it was present.
+func stripOverrideFreeze(args []string) ([]string, bool) {
+	rest := make([]string, 0, len(args))
+	found := false
+	for _, arg := range args {
+		if strings.EqualFold(arg, overrideFreezeFlag) {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// freezeRefusal explains err to the requester when it is a freeze refusal.
+func freezeRefusal(ctx context.Context, resp Responder, what string, err error) bool {
+	var freezeErr *dashboard.FreezeError
+	if !errors.As(err, &freezeErr) {
+		return false
+	}
+	resp.Ephemeral(ctx, fmt.Sprintf("🧊 Cannot %s: %s\nSomeone with the `%s` permission can add `%s`.",
+		what, freezeErr.Error(), dashboard.FreezeOverridePermission, overrideFreezeFlag))
+	return true
+}
+
+func (c *botCommands) listFreezes(ctx context.Context, req *Request, resp Responder) {
+	if c.dashboardServer == nil {
+		resp.Ephemeral(ctx, "Dashboard not configured.")
+		return
+	}
+
+	now := time.Now()
+	windows, err := c.dashboardServer.Service().ListFreezes(ctx, now)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to list freezes: %v", err))
+		return
+	}
+	if len(windows) == 0 {
+		resp.Ephemeral(ctx, "🟢 No deployment freezes scheduled.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("### 🧊 Deployment Freezes\n\n| ID | From | Until | Reason | Owner | Source |\n|---|---|---|---|---|---|\n")
+	for _, w := range windows {
+		id := strconv.FormatUint(uint64(w.ID), 10)
+		if w.StartsAt <= now.Unix() {
+			id += " 🔴"
+		}
+		owner := "-"
+		if w.Owner != "" {
+			owner = "@" + w.Owner
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n", id,
+			c.formatFreezeTime(w.StartsAt), c.formatFreezeTime(w.EndsAt), w.Reason, owner, w.Source))
+	}
+	resp.Reply(ctx, strings.TrimSpace(sb.String()))
+}
+
+func (c *botCommands) addFreeze(ctx context.Context, req *Request, resp Responder) {
+	if c.dashboardServer == nil {
+		resp.Ephemeral(ctx, "Dashboard not configured.")
+		return
+	}
+
+	if !c.permissions.Granted(ctx, dashboard.FreezePermission, permissions.Subject{
+		UserName:  req.UserName,
+		TeamID:    req.TeamID,
+		ChannelID: req.ChannelID,
+	}) {
+		resp.Ephemeral(ctx, "⛔ You are not allowed to add deployment freezes.")
+		return
+	}
+
+	start, end, err := parseFreezeSpan(req.Args[0], req.Args[1], time.Now(), c.freezeLocation)
+	if err != nil {
+		resp.Ephemeral(ctx, "❌ "+err.Error())
+		return
+	}
+
+	window, err := c.dashboardServer.Service().CreateFreeze(ctx, database.FreezeWindow{
+		Reason:   strings.Join(req.Args[2:], " "),
+		Owner:    req.UserName,
+		StartsAt: start.Unix(),
+		EndsAt:   end.Unix(),
+	})
+	if errors.Is(err, dashboard.ErrInvalidFreeze) {
+		resp.Ephemeral(ctx, "❌ The freeze must end after it starts.")
+		return
+	}
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to add freeze: %v", err))
+		return
+	}
+
+	resp.Reply(ctx, fmt.Sprintf("🧊 Deployment freeze `%d` added by @%s from %s until %s: %s",
+		window.ID, req.UserName, c.formatFreezeTime(window.StartsAt), c.formatFreezeTime(window.EndsAt), window.Reason))
+}
+
+func (c *botCommands) removeFreeze(ctx context.Context, req *Request, resp Responder) {
+	if c.dashboardServer == nil {
+		resp.Ephemeral(ctx, "Dashboard not configured.")
+		return
+	}
+
+	id, err := strconv.ParseUint(req.Args[0], 10, 64)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Invalid freeze ID `%s`.", req.Args[0]))
+		return
+	}
+
+	svc := c.dashboardServer.Service()
+	window, err := svc.GetFreeze(ctx, uint(id))
+	if errors.Is(err, dashboard.ErrFreezeNotFound) {
+		resp.Ephemeral(ctx, fmt.Sprintf("Freeze `%d` not found.", id))
+		return
+	}
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to remove freeze: %v", err))
+		return
+	}
+	if window.Source != dashboard.FreezeSourceConfig && window.Owner != req.UserName && !c.permissions.Granted(ctx, dashboard.FreezePermission, permissions.Subject{
+		UserName:  req.UserName,
+		TeamID:    req.TeamID,
+		ChannelID: req.ChannelID,
+	}) {
+		resp.Ephemeral(ctx, fmt.Sprintf("⛔ Freeze `%d` is owned by @%s; only they can remove it.", id, window.Owner))
+		return
+	}
+
+	err = svc.DeleteFreeze(ctx, uint(id))
+	switch {
+	case errors.Is(err, dashboard.ErrFreezeNotFound):
+		resp.Ephemeral(ctx, fmt.Sprintf("Freeze `%d` not found.", id))
+	case errors.Is(err, dashboard.ErrConfigFreeze):
+		resp.Ephemeral(ctx, fmt.Sprintf("Freeze `%d` is defined in the config file; remove it there.", id))
+	case err != nil:
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to remove freeze: %v", err))
+	default:
+		resp.Reply(ctx, fmt.Sprintf("🟢 Deployment freeze `%d` removed by @%s", id, req.UserName))
+	}
+}
+
+func (c *botCommands) overrideFreeze(ctx context.Context, req *Request, resp Responder) {
+	if !c.canOverrideFreeze(ctx, req) {
+		resp.Ephemeral(ctx, "⛔ You are not allowed to override deployment freezes.")
+		return
+	}
+	release, ok := c.releaseForRequest(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	window, err := c.dashboardServer.Service().OverrideFreezeForMerge(ctx, release.ID, req.UserName)
+	switch {
+	case errors.Is(err, dashboard.ErrNotFrozen):
+		resp.Ephemeral(ctx, "No deployment freeze is in effect.")
+	case errors.Is(err, dashboard.ErrAlreadyOverride):
+		resp.Ephemeral(ctx, "The current freeze is already overridden for this release.")
+	case err != nil:
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to override freeze: %v", err))
+	default:
+		resp.Reply(ctx, fmt.Sprintf("⚠️ @%s overrode the deployment freeze (%s) for `%s` → `%s`; its PRs may be merged.\n[View Dashboard](%s)",
+			req.UserName, window.Reason, release.SourceBranch, release.DestBranch, c.releaseURL(release.ID)))
+	}
+}
+
+func (c *botCommands) formatFreezeTime(unix int64) string {
+	return time.Unix(unix, 0).In(c.freezeLocation).Format("2006-01-02 15:04 MST")
+}
+
+// parseFreezeSpan reads a freeze start ("now", a date or a date and time) and
+// end (the same, or a duration after the start such as "2h" or "3d") in loc.
+// A date alone means midnight.
+func parseFreezeSpan(startArg, endArg string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	start := now
+	if !strings.EqualFold(startArg, "now") {
+		t, err := parseFreezeTime(startArg, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start = t
+	}
+
+	if d, err := parseFreezeDuration(endArg); err == nil {
+		return start, start.Add(d), nil
+	}
+	end, err := parseFreezeTime(endArg, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, end, nil
+}
+
+func parseFreezeTime(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time `%s`, use `YYYY-MM-DD` or `YYYY-MM-DDTHH:MM`", s)
+}
+
+// parseFreezeDuration accepts Go durations plus whole days such as "3d".
+func parseFreezeDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration `%s`", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration `%s`", s)
+	}
+	return d, nil
+}
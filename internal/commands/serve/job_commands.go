@@ -0,0 +1,202 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/jobs"
+	"github.com/user/mattermost-tools/internal/permissions"
+)
+
+const (
+	jobTypeChanges       = "changes"
+	jobTypeCreateRelease = "create-release"
+)
+
+type branchArgs struct {
+	Source string `json:"source"`
+	Dest   string `json:"dest"`
+}
+
+func jobTarget(req *Request) jobs.Target {
+	return jobs.Target{
+		RequestedBy: req.UserName,
+		ChannelID:   req.ChannelID,
+		ThreadID:    req.ThreadID,
+	}
+}
+
+func shortJobID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// jobFailureMessage describes a failed attempt for the job's progress post:
+// stopped, retrying, or failed for good.
+func jobFailureMessage(ctx context.Context, job *database.Job, what string, err error) string {
+	switch {
+	case ctx.Err() != nil:
+		return fmt.Sprintf("🚫 Stopped before finishing (job `%s`)", shortJobID(job.ID))
+	case !jobs.FinalAttempt(job):
+		return fmt.Sprintf("⚠️ %s: %v\nRetrying (attempt %d of %d, job `%s`)...", what, err, job.Attempts, job.MaxAttempts, shortJobID(job.ID))
+	default:
+		return fmt.Sprintf("@%s ❌ %s: %v", job.RequestedBy, what, err)
+	}
+}
+
+func (c *botCommands) registerJobHandlers(m *jobs.Manager) {
+	m.Register(jobTypeChanges, 3, c.runChangesJob)
+	m.Register(jobTypeCreateRelease, 1, c.runCreateReleaseJob)
+}
+
+func (c *botCommands) registerJobCommands(r *Router) {
+	r.Register(&Command{
+		Name:        "jobs",
+		Args:        "[all]",
+		Description: "List running and queued jobs and your recent ones",
+		MaxArgs:     1,
+		Handler:     c.listJobs,
+	})
+	r.Register(&Command{
+		Name:        "cancel",
+		Args:        "<job-id>",
+		Description: "Cancel a queued or running job",
+		Example:     "cancel 3f2a9c1e",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Handler:     c.cancelJob,
+	})
+}
+
+func (c *botCommands) listJobs(ctx context.Context, req *Request, resp Responder) {
+	var list []database.Job
+	if len(req.Args) > 0 && strings.EqualFold(req.Args[0], "all") {
+		all, err := c.jobs.List(ctx, jobs.ListOptions{Limit: 15})
+		if err != nil {
+			resp.Ephemeral(ctx, fmt.Sprintf("Failed to list jobs: %v", err))
+			return
+		}
+		list = all
+	} else {
+		active, err := c.jobs.List(ctx, jobs.ListOptions{Active: true})
+		if err != nil {
+			resp.Ephemeral(ctx, fmt.Sprintf("Failed to list jobs: %v", err))
+			return
+		}
+		mine, err := c.jobs.List(ctx, jobs.ListOptions{RequestedBy: req.UserName, Limit: 5})
+		if err != nil {
+			resp.Ephemeral(ctx, fmt.Sprintf("Failed to list jobs: %v", err))
+			return
+		}
+		list = mergeJobs(active, mine)
+	}
+
+	if len(list) == 0 {
+		resp.Ephemeral(ctx, "No jobs.")
+		return
+	}
+	resp.Ephemeral(ctx, formatJobs(list))
+}
+
+// mergeJobs appends the jobs of b not already in a.
+func mergeJobs(a, b []database.Job) []database.Job {
+	seen := make(map[string]struct{}, len(a))
+	for _, j := range a {
+		seen[j.ID] = struct{}{}
+	}
+	for _, j := range b {
+		if _, ok := seen[j.ID]; !ok {
+			a = append(a, j)
+		}
+	}
+	return a
+}
+
+func formatJobs(list []database.Job) string {
+	var sb strings.Builder
+	sb.WriteString("### ⚙️ Jobs\n\n")
+	sb.WriteString("| ID | Type | Status | Progress | Requested by | Created |\n|---|---|---|---|---|---|\n")
+	for _, j := range list {
+		progress := "—"
+		if j.ProgressTotal > 0 {
+			progress = fmt.Sprintf("%d/%d", j.ProgressDone, j.ProgressTotal)
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | @%s | %s |\n",
+			shortJobID(j.ID), j.Type, jobStatusLabel(j), progress, j.RequestedBy,
+			time.Unix(j.CreatedAt, 0).Format("01-02 15:04")))
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func jobStatusLabel(j database.Job) string {
+	label := j.Status
+	switch j.Status {
+	case jobs.StatusRunning:
+		label = "⏳ running"
+		if j.MaxAttempts > 1 {
+			label += fmt.Sprintf(" (attempt %d/%d)", j.Attempts, j.MaxAttempts)
+		}
+	case jobs.StatusQueued:
+		label = "🕒 queued"
+		if j.Error != "" {
+			label += " for retry"
+		}
+	case jobs.StatusSucceeded:
+		label = "✅ succeeded"
+	case jobs.StatusFailed:
+		label = "❌ failed: " + truncate(j.Error, 60)
+	case jobs.StatusCancelled:
+		label = "🚫 cancelled"
+		if j.CancelledBy != "" {
+			label += " by @" + j.CancelledBy
+		}
+	}
+	return label
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
+
+func (c *botCommands) cancelJob(ctx context.Context, req *Request, resp Responder) {
+	job, err := c.jobs.Get(ctx, req.Args[0])
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Job `%s`: %v", req.Args[0], err))
+		return
+	}
+
+	if job.RequestedBy != req.UserName && !c.permissions.Granted(ctx, jobs.CancelAnyPermission, permissions.Subject{
+		UserName:  req.UserName,
+		TeamID:    req.TeamID,
+		ChannelID: req.ChannelID,
+	}) {
+		resp.Ephemeral(ctx, fmt.Sprintf("⛔ Job `%s` was requested by @%s; only they can cancel it.", shortJobID(job.ID), job.RequestedBy))
+		return
+	}
+
+	err = c.jobs.Cancel(ctx, job.ID, req.UserName)
+	if errors.Is(err, jobs.ErrJobFinished) {
+		resp.Ephemeral(ctx, fmt.Sprintf("Job `%s` already finished.", shortJobID(job.ID)))
+		return
+	}
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to cancel job: %v", err))
+		return
+	}
+
+	resp.Reply(ctx, fmt.Sprintf("🚫 Job `%s` (%s) cancelled by @%s", shortJobID(job.ID), job.Type, req.UserName))
+}
@@ -0,0 +1,187 @@
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/internal/mappings"
+)
+
+const (
+	LinkVerifyNone = "none"
+	LinkVerifyGist = "gist"
+
+	defaultLinkChallengeTTL = 30 * time.Minute
+)
+
+type linkChallenge struct {
+	login     string
+	token     string
+	expiresAt time.Time
+}
+
+// linkChallenges holds pending gist challenges keyed by Mattermost username.
+type linkChallenges struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	pending map[string]linkChallenge
+}
+
+func newLinkChallenges(ttl time.Duration) *linkChallenges {
+	if ttl <= 0 {
+		ttl = defaultLinkChallengeTTL
+	}
+	return &linkChallenges{
+		ttl:     ttl,
+		pending: make(map[string]linkChallenge),
+	}
+}
+
+// get returns the unexpired challenge for the user and login, if any.
+func (l *linkChallenges) get(mmUser, login string) (linkChallenge, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ch, ok := l.pending[strings.ToLower(mmUser)]
+	if !ok || !strings.EqualFold(ch.login, login) || time.Now().After(ch.expiresAt) {
+		return linkChallenge{}, false
+	}
+	return ch, true
+}
+
+func (l *linkChallenges) issue(mmUser, login string) (linkChallenge, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return linkChallenge{}, fmt.Errorf("generating token: %w", err)
+	}
+
+	ch := linkChallenge{
+		login:     login,
+		token:     "mmtools-link-" + hex.EncodeToString(buf),
+		expiresAt: time.Now().Add(l.ttl),
+	}
+
+	l.mu.Lock()
+	l.pending[strings.ToLower(mmUser)] = ch
+	l.mu.Unlock()
+	return ch, nil
+}
+
+func (l *linkChallenges) clear(mmUser string) {
+	l.mu.Lock()
+	delete(l.pending, strings.ToLower(mmUser))
+	l.mu.Unlock()
+}
+
+func (c *botCommands) registerLinkCommands(r *Router) {
+	r.Register(&Command{
+		Name:        "link-github",
+		Args:        "[login]",
+		Description: "Link your Mattermost account to a GitHub login, or show the current link",
+		Example:     "link-github octocat",
+		MaxArgs:     1,
+		Handler:     c.linkGitHub,
+	})
+}
+
+func (c *botCommands) linkGitHub(ctx context.Context, req *Request, resp Responder) {
+	if len(req.Args) == 0 {
+		if gh, ok := mappings.GitHubFromMattermost(ctx, req.UserName); ok {
+			resp.Ephemeral(ctx, fmt.Sprintf("@%s is linked to GitHub `%s`.", req.UserName, gh))
+			return
+		}
+		resp.Ephemeral(ctx, fmt.Sprintf("@%s is not linked to a GitHub account. Use `link-github <login>`.", req.UserName))
+		return
+	}
+
+	if c.dashboardServer == nil {
+		resp.Ephemeral(ctx, "Dashboard not configured. Account links are stored in the dashboard database.")
+		return
+	}
+
+	login := strings.TrimPrefix(req.Args[0], "@")
+	ghUser, err := c.ghClient.GetUser(ctx, login)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to look up GitHub user: %v", err))
+		return
+	}
+	if ghUser == nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("GitHub user `%s` not found.", login))
+		return
+	}
+
+	if mm, ok := mappings.MattermostFromGitHub(ctx, ghUser.Login); ok && !strings.EqualFold(mm, req.UserName) {
+		resp.Ephemeral(ctx, fmt.Sprintf("GitHub `%s` is already linked to @%s.", ghUser.Login, mm))
+		return
+	}
+
+	if c.linkVerify == LinkVerifyGist {
+		if !c.verifyGistChallenge(ctx, req, resp, ghUser.Login) {
+			return
+		}
+	}
+
+	var email string
+	if c.mmBot != nil {
+		mmUser, err := c.mmBot.GetUserByUsername(ctx, req.UserName)
+		if err != nil {
+			logger.Warn().Err(err).Str("user", req.UserName).Msg("Failed to fetch Mattermost user for GitHub link")
+		} else if mmUser != nil {
+			email = mmUser.Email
+		}
+	}
+
+	_, err = c.dashboardServer.Service().LinkGitHubAccount(ctx, req.UserName, email, ghUser.Login)
+	if errors.Is(err, dashboard.ErrGitHubAlreadyLinked) {
+		resp.Ephemeral(ctx, fmt.Sprintf("GitHub `%s` is already linked to another Mattermost user.", ghUser.Login))
+		return
+	}
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to link account: %v", err))
+		return
+	}
+
+	logger.Info().Str("user", req.UserName).Str("github", ghUser.Login).Msg("Linked GitHub account")
+	resp.Ephemeral(ctx, fmt.Sprintf("✅ @%s is now linked to GitHub `%s`.", req.UserName, ghUser.Login))
+}
+
+// verifyGistChallenge issues a challenge on the first call and checks the
+// user's public gists for its token on the next. When it returns false it
+// has already replied with what to do next.
+func (c *botCommands) verifyGistChallenge(ctx context.Context, req *Request, resp Responder, login string) bool {
+	ch, pending := c.linkChallenges.get(req.UserName, login)
+	if !pending {
+		ch, err := c.linkChallenges.issue(req.UserName, login)
+		if err != nil {
+			resp.Ephemeral(ctx, fmt.Sprintf("Failed to create challenge: %v", err))
+			return false
+		}
+		resp.Ephemeral(ctx, fmt.Sprintf("To prove you own `%s`, create a public gist at https://gist.github.com with the description `%s`, then run `link-github %s` again within %s.",
+			login, ch.token, login, c.linkChallenges.ttl))
+		return false
+	}
+
+	gists, err := c.ghClient.ListUserGists(ctx, login)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to list gists: %v", err))
+		return false
+	}
+	for _, g := range gists {
+		if g.Public && strings.Contains(g.Description, ch.token) {
+			c.linkChallenges.clear(req.UserName)
+			return true
+		}
+	}
+
+	resp.Ephemeral(ctx, fmt.Sprintf("No public gist by `%s` with the description `%s` yet. Create it and run `link-github %s` again.",
+		login, ch.token, login))
+	return false
+}
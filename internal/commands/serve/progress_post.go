@@ -0,0 +1,192 @@
+package serve
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/jobs"
+	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/pkg/mattermost"
+)
+
+const progressUpdateInterval = 3 * time.Second
+
+// progressPost keeps a single bot post up to date while a command scans
+// repositories, and replaces it with the result when the command finishes.
+// Counts are mirrored to the job's Progress. A nil *progressPost ignores all
+// calls, so scanning helpers can be shared with callers that post nothing.
+type progressPost struct {
+	mmBot     *mattermost.Bot
+	channelID string
+	threadID  string
+	userName  string
+	title     string
+	job       *jobs.Progress
+
+	// updateMu serializes edits of the post so a slow periodic update cannot
+	// land after the final message.
+	updateMu sync.Mutex
+
+	mu          sync.Mutex
+	postID      string
+	total       int
+	scanned     int
+	withChanges int
+	summarized  int
+	failures    int
+	lastUpdate  time.Time
+	finished    bool
+}
+
+func (c *botCommands) newProgressPost(job *database.Job, p *jobs.Progress, title string) *progressPost {
+	return &progressPost{
+		mmBot:     c.mmBot,
+		channelID: job.ChannelID,
+		threadID:  job.ThreadID,
+		userName:  job.RequestedBy,
+		title:     title,
+		job:       p,
+	}
+}
+
+// start posts the initial message. Without a post, updates are skipped and
+// finish falls back to a new reply.
+func (pp *progressPost) start(ctx context.Context) {
+	if pp == nil {
+		return
+	}
+	pp.mu.Lock()
+	message := pp.render()
+	pp.mu.Unlock()
+
+	postID, err := pp.mmBot.PostMessageInThreadWithID(ctx, pp.channelID, pp.threadID, message)
+	if err != nil {
+		logger.Warn().Err(err).Str("channel", pp.channelID).Msg("Failed to post progress message")
+		return
+	}
+
+	pp.mu.Lock()
+	pp.postID = postID
+	pp.lastUpdate = time.Now()
+	pp.mu.Unlock()
+}
+
+func (pp *progressPost) SetTotal(total int) {
+	if pp == nil {
+		return
+	}
+	pp.job.SetTotal(total)
+	pp.record(func() { pp.total = total })
+}
+
+// Scanned marks one repository compared; changed reports whether it has
+// changes to summarize.
+func (pp *progressPost) Scanned(changed bool) {
+	if pp == nil {
+		return
+	}
+	if !changed {
+		pp.job.Advance()
+	}
+	pp.record(func() {
+		pp.scanned++
+		if changed {
+			pp.withChanges++
+		}
+	})
+}
+
+// Summarized marks the summary of a changed repository done. Repositories
+// with changes count towards the job's progress only once summarized.
+func (pp *progressPost) Summarized(ok bool) {
+	if pp == nil {
+		return
+	}
+	pp.job.Advance()
+	pp.record(func() {
+		pp.summarized++
+		if !ok {
+			pp.failures++
+		}
+	})
+}
+
+// Failed marks a repository that could not be compared.
+func (pp *progressPost) Failed() {
+	if pp == nil {
+		return
+	}
+	pp.job.Advance()
+	pp.record(func() {
+		pp.scanned++
+		pp.failures++
+	})
+}
+
+// record applies a change to the counts and refreshes the post if the last
+// refresh is old enough and no other one is in flight.
+func (pp *progressPost) record(change func()) {
+	pp.mu.Lock()
+	change()
+	due := pp.postID != "" && !pp.finished && time.Since(pp.lastUpdate) >= progressUpdateInterval
+	pp.mu.Unlock()
+	if !due || !pp.updateMu.TryLock() {
+		return
+	}
+	defer pp.updateMu.Unlock()
+
+	pp.mu.Lock()
+	if pp.finished {
+		pp.mu.Unlock()
+		return
+	}
+	postID, message := pp.postID, pp.render()
+	pp.lastUpdate = time.Now()
+	pp.mu.Unlock()
+
+	if err := pp.mmBot.UpdatePost(context.Background(), postID, message); err != nil {
+		logger.Warn().Err(err).Str("post_id", postID).Msg("Failed to update progress message")
+	}
+}
+
+// render formats the progress message. pp.mu must be held.
+func (pp *progressPost) render() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⏳ %s\n\n", pp.title))
+	if pp.total == 0 {
+		sb.WriteString("Fetching repositories...")
+	} else {
+		sb.WriteString(fmt.Sprintf("| Repos scanned | With changes | Summaries | Failures |\n|---|---|---|---|\n| %d/%d | %d | %d/%d | %d |",
+			pp.scanned, pp.total, pp.withChanges, pp.summarized, pp.withChanges, pp.failures))
+	}
+	sb.WriteString(fmt.Sprintf("\n\n_Requested by @%s_", pp.userName))
+	return sb.String()
+}
+
+// finish replaces the progress post with the final message, or replies with
+// it when there is no post to replace.
+func (pp *progressPost) finish(ctx context.Context, text string) {
+	pp.updateMu.Lock()
+	defer pp.updateMu.Unlock()
+
+	pp.mu.Lock()
+	pp.finished = true
+	postID := pp.postID
+	pp.mu.Unlock()
+
+	message := fmt.Sprintf("%s\n\n_Requested by @%s_", text, pp.userName)
+	if postID != "" {
+		err := pp.mmBot.UpdatePost(ctx, postID, message)
+		if err == nil {
+			return
+		}
+		logger.Warn().Err(err).Str("post_id", postID).Msg("Failed to replace progress message, replying instead")
+	}
+	if err := pp.mmBot.PostMessageInThread(ctx, pp.channelID, pp.threadID, message); err != nil {
+		logger.Error().Err(err).Str("channel", pp.channelID).Msg("Failed to post command reply")
+	}
+}
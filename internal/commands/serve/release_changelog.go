@@ -0,0 +1,24 @@
+package serve
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/user/mattermost-tools/internal/changelog"
+)
+
+func (c *botCommands) changelog(ctx context.Context, req *Request, resp Responder) {
+	release, ok := c.releaseForArgs(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	repos, err := c.dashboardServer.Changelog(ctx, release.ID)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to build changelog: %v", err))
+		return
+	}
+
+	resp.Reply(ctx, fmt.Sprintf("### 📝 Changelog: `%s` → `%s`\n[View Dashboard](%s)\n\n%s",
+		release.SourceBranch, release.DestBranch, c.releaseURL(release.ID), changelog.Markdown(repos)))
+}
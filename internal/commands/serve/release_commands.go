@@ -0,0 +1,430 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/summarizer"
+)
+
+func (c *botCommands) registerReleaseCommands(r *Router) {
+	r.Register(&Command{
+		Name:        "approve",
+		Args:        "<dev|qa> [--override-freeze]",
+		Description: "Approve the release for this channel or thread",
+		Example:     "approve qa",
+		MinArgs:     1,
+		MaxArgs:     2,
+		Details:     "Add `--override-freeze` to approve during a deployment freeze; this needs the `freeze-override` permission and is recorded in the history.",
+		Handler:     c.approve,
+	})
+	r.Register(&Command{
+		Name:        "decline",
+		Args:        "[reason]",
+		Description: "Decline the release for this channel or thread",
+		MaxArgs:     -1,
+		Handler:     c.decline,
+	})
+	r.Register(&Command{
+		Name:        "status",
+		Description: "Show repos, confirmations, CI and rollout for the release in this channel or thread",
+		MaxArgs:     0,
+		Handler:     c.status,
+	})
+	r.Register(&Command{
+		Name:        "history",
+		Args:        "[release-id]",
+		Description: "Show the timeline of the release in this channel or thread, or of the given release",
+		MaxArgs:     1,
+		Handler:     c.history,
+	})
+	r.Register(&Command{
+		Name:        "changelog",
+		Args:        "[release-id]",
+		Description: "Show the conventional-commit changelog of the release in this channel or thread, or of the given release",
+		MaxArgs:     1,
+		Handler:     c.changelog,
+	})
+	r.Register(&Command{
+		Name:        "deploy-status",
+		Aliases:     []string{"deployed"},
+		Args:        "<repo> [env]",
+		Description: "Show what is running for a repo in each ArgoCD environment",
+		Example:     "deploy-status auth-service uat",
+		MinArgs:     1,
+		MaxArgs:     2,
+		Handler:     c.deployStatus,
+	})
+}
+
+// releaseForRequest finds the dashboard release bound to the request's thread
+// or channel, replying with an explanation when there is none.
+func (c *botCommands) releaseForRequest(ctx context.Context, req *Request, resp Responder) (*database.Release, bool) {
+	if c.dashboardServer == nil {
+		resp.Ephemeral(ctx, "Dashboard not configured.")
+		return nil, false
+	}
+
+	release, err := c.dashboardServer.Service().FindReleaseForChannel(ctx, req.ChannelID, req.ThreadID)
+	if errors.Is(err, dashboard.ErrReleaseNotFound) {
+		resp.Ephemeral(ctx, "No release found for this channel or thread.")
+		return nil, false
+	}
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to find release: %v", err))
+		return nil, false
+	}
+	return release, true
+}
+
+// releaseForArgs finds the release named by the request's first argument,
+// or the one bound to its thread or channel when there is no argument.
+func (c *botCommands) releaseForArgs(ctx context.Context, req *Request, resp Responder) (*database.Release, bool) {
+	if len(req.Args) == 0 {
+		return c.releaseForRequest(ctx, req, resp)
+	}
+	if c.dashboardServer == nil {
+		resp.Ephemeral(ctx, "Dashboard not configured.")
+		return nil, false
+	}
+	release, err := c.dashboardServer.Service().GetRelease(ctx, req.Args[0])
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Release `%s` not found.", req.Args[0]))
+		return nil, false
+	}
+	return release, true
+}
+
+func (c *botCommands) approve(ctx context.Context, req *Request, resp Responder) {
+	args, override := stripOverrideFreeze(req.Args)
+	if len(args) != 1 {
+		resp.Ephemeral(ctx, fmt.Sprintf("Usage: `%sapprove <dev|qa> [--override-freeze]`", req.Prefix))
+		return
+	}
+	approvalType := strings.ToLower(args[0])
+	if approvalType != "dev" && approvalType != "qa" {
+		resp.Ephemeral(ctx, fmt.Sprintf("Unknown approval type `%s`. Use `dev` or `qa`.", args[0]))
+		return
+	}
+	if override && !c.canOverrideFreeze(ctx, req) {
+		resp.Ephemeral(ctx, "⛔ You are not allowed to override deployment freezes.")
+		return
+	}
+
+	release, ok := c.releaseForRequest(ctx, req, resp)
+	if !ok {
+		return
+	}
+	if !dashboard.AcceptsApprovals(release.Status) {
+		resp.Ephemeral(ctx, fmt.Sprintf("This release is %s and can no longer be approved.", release.Status))
+		return
+	}
+
+	svc := c.dashboardServer.Service()
+	approve := svc.ApproveRelease
+	if override {
+		approve = svc.ApproveReleaseOverridingFreeze
+	}
+	if err := approve(ctx, release.ID, approvalType, req.UserName); err != nil {
+		if !freezeRefusal(ctx, resp, "approve", err) {
+			resp.Ephemeral(ctx, fmt.Sprintf("Failed to approve release: %v", err))
+		}
+		return
+	}
+
+	svc.RecordHistory(ctx, release.ID, "approval_added", req.UserName, map[string]any{
+		"type": approvalType,
+		"via":  "chat",
+	})
+
+	resp.Reply(ctx, fmt.Sprintf("✅ **%s approval** added by @%s for `%s` → `%s`\n[View Dashboard](%s)",
+		strings.ToUpper(approvalType), req.UserName, release.SourceBranch, release.DestBranch, c.releaseURL(release.ID)))
+}
+
+func (c *botCommands) decline(ctx context.Context, req *Request, resp Responder) {
+	release, ok := c.releaseForRequest(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	reason := strings.Join(req.Args, " ")
+	details := map[string]any{"via": "chat"}
+	if reason != "" {
+		details["reason"] = reason
+	}
+	if err := c.dashboardServer.Service().DeclineRelease(ctx, release.ID, req.UserName, details); err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to decline release: %v", err))
+		return
+	}
+
+	message := fmt.Sprintf("❌ Release `%s` → `%s` **declined** by @%s", release.SourceBranch, release.DestBranch, req.UserName)
+	if reason != "" {
+		message += "\n> " + reason
+	}
+	resp.Reply(ctx, message)
+}
+
+func (c *botCommands) status(ctx context.Context, req *Request, resp Responder) {
+	release, ok := c.releaseForRequest(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	svc := c.dashboardServer.Service()
+	releaseWithRepos, err := svc.GetReleaseWithRepos(ctx, release.ID)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to load release: %v", err))
+		return
+	}
+
+	ciStatuses, err := svc.GetCIStatusesForRelease(ctx, release.ID)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to load CI status: %v", err))
+		return
+	}
+	deployStatuses, err := svc.GetDeploymentStatusesForRelease(ctx, release.ID)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to load deployment status: %v", err))
+		return
+	}
+
+	resp.Reply(ctx, formatReleaseStatus(releaseWithRepos, svc.RequiredApprovals(release), ciStatuses, deployStatuses, c.releaseURL(release.ID)))
+}
+
+func (c *botCommands) releaseURL(releaseID string) string {
+	return c.baseURL + "/releases/" + releaseID
+}
+
+func formatReleaseStatus(release *dashboard.ReleaseWithRepos, requiredApprovals []string, ciStatuses []database.RepoCIStatus, deployStatuses []database.RepoDeploymentStatus, releaseURL string) string {
+	ciByRepo := make(map[uint]database.RepoCIStatus)
+	for _, s := range ciStatuses {
+		ciByRepo[s.ReleaseRepoID] = s
+	}
+
+	envs := make(map[string]struct{})
+	deployByRepo := make(map[uint][]database.RepoDeploymentStatus)
+	for _, s := range deployStatuses {
+		deployByRepo[s.ReleaseRepoID] = append(deployByRepo[s.ReleaseRepoID], s)
+		envs[s.Environment] = struct{}{}
+	}
+
+	var sb strings.Builder
+	if release.IsHotfix {
+		sb.WriteString(fmt.Sprintf("### 🩹 Hotfix → `%s` · **%s**\n", release.DestBranch, release.Status))
+	} else {
+		sb.WriteString(fmt.Sprintf("### 📦 Release `%s` → `%s` · **%s**\n", release.SourceBranch, release.DestBranch, release.Status))
+	}
+	sb.WriteString(fmt.Sprintf("Created %s · [View Dashboard](%s)\n\n", time.Unix(release.CreatedAt, 0).Format("2006-01-02 15:04"), releaseURL))
+	sb.WriteString(fmt.Sprintf("**Approvals:** Dev %s · QA %s\n",
+		approvalState(release.DevApprovedBy, slices.Contains(requiredApprovals, dashboard.ApprovalDev)),
+		approvalState(release.QAApprovedBy, slices.Contains(requiredApprovals, dashboard.ApprovalQA))))
+	if release.Status == dashboard.StatusDeclined && release.DeclinedBy != "" {
+		sb.WriteString(fmt.Sprintf("**Declined by:** @%s\n", release.DeclinedBy))
+	}
+
+	var active, confirmed, breaking, highRisk, migrations int
+	for _, repo := range release.Repos {
+		if repo.Excluded {
+			continue
+		}
+		active++
+		if dashboard.IsRepoConfirmed(&repo) {
+			confirmed++
+		}
+		if repo.IsBreaking {
+			breaking++
+		}
+		if repo.RiskLevel == summarizer.RiskHigh {
+			highRisk++
+		}
+		if repo.HasMigration {
+			migrations++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("**Repositories:** %d · Confirmed %d/%d", active, confirmed, active))
+	if breaking > 0 {
+		sb.WriteString(fmt.Sprintf(" · 🚨 %d breaking", breaking))
+	}
+	if highRisk > 0 {
+		sb.WriteString(fmt.Sprintf(" · 🔴 %d high risk", highRisk))
+	}
+	if migrations > 0 {
+		sb.WriteString(fmt.Sprintf(" · 🗄️ %d with DB migrations", migrations))
+	}
+	sb.WriteString("\n\n")
+
+	if active == 0 {
+		sb.WriteString("_No repositories in this release._")
+		return sb.String()
+	}
+
+	envNames := make([]string, 0, len(envs))
+	for env := range envs {
+		envNames = append(envNames, env)
+	}
+	sort.Strings(envNames)
+
+	sb.WriteString("| Repo | Commits | PR | Confirmed | CI |")
+	for _, env := range envNames {
+		sb.WriteString(" " + env + " |")
+	}
+	sb.WriteString("\n|---|---|---|---|---|")
+	for range envNames {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("\n")
+
+	for _, repo := range release.Repos {
+		if repo.Excluded {
+			continue
+		}
+
+		name := repo.RepoName
+		if release.IsHotfix && repo.SourceRef != "" {
+			name += " @ `" + repo.SourceRef + "`"
+		}
+		if repo.IsBreaking {
+			name = "🚨 " + name
+		}
+		if flags := repoFlags(&repo); flags != "" {
+			name += " " + flags
+		}
+
+		pr := "—"
+		if repo.PRNumber > 0 {
+			pr = fmt.Sprintf("[#%d](%s)", repo.PRNumber, repo.PRURL)
+			if repo.PRMerged {
+				pr += " merged"
+			}
+		}
+
+		confirmedMark := "⏳"
+		if dashboard.IsRepoConfirmed(&repo) {
+			confirmedMark = "✅"
+		}
+
+		ci := "—"
+		if s, ok := ciByRepo[repo.ID]; ok {
+			ci = s.Status
+		}
+
+		sb.WriteString(fmt.Sprintf("| %s | %d | %s | %s | %s |", name, repo.CommitCount, pr, confirmedMark, ci))
+
+		byEnv := make(map[string]database.RepoDeploymentStatus)
+		for _, s := range deployByRepo[repo.ID] {
+			byEnv[s.Environment] = s
+		}
+		for _, env := range envNames {
+			s, ok := byEnv[env]
+			if !ok {
+				sb.WriteString(" — |")
+				continue
+			}
+			sb.WriteString(fmt.Sprintf(" %s |", rolloutLabel(s)))
+		}
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+func approvalState(approvedBy string, required bool) string {
+	if approvedBy == "" {
+		if !required {
+			return "not required"
+		}
+		return "⏳ pending"
+	}
+	return "✅ @" + approvedBy
+}
+
+func rolloutLabel(s database.RepoDeploymentStatus) string {
+	label := s.RolloutStatus
+	if s.CurrentVersion != "" {
+		label += " `" + s.CurrentVersion + "`"
+	}
+	return label
+}
+
+func (c *botCommands) deployStatus(ctx context.Context, req *Request, resp Responder) {
+	if c.argocdTracker == nil {
+		resp.Ephemeral(ctx, "ArgoCD not configured.")
+		return
+	}
+
+	repoName := req.Args[0]
+	var envs []string
+	if len(req.Args) > 1 {
+		envs = append(envs, req.Args[1])
+	}
+
+	statuses, err := c.argocdTracker.QueryLiveStatus(ctx, repoName, envs...)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to query ArgoCD: %v", err))
+		return
+	}
+
+	resp.Reply(ctx, formatLiveStatus(repoName, statuses))
+}
+
+func formatLiveStatus(repoName string, statuses []dashboard.LiveAppStatus) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### 🚢 Live status: `%s`\n\n", repoName))
+	sb.WriteString("| Env | App | Version | Sync | Health |\n|---|---|---|---|---|\n")
+
+	for _, s := range statuses {
+		switch {
+		case s.Error != "":
+			sb.WriteString(fmt.Sprintf("| %s | %s | — | ⚠️ %s | — |\n", s.Environment, s.AppName, s.Error))
+		case !s.Found:
+			sb.WriteString(fmt.Sprintf("| %s | %s | — | not found | — |\n", s.Environment, s.AppName))
+		default:
+			sb.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s | %s %s |\n",
+				s.Environment, s.AppName, s.CurrentVersion, s.SyncStatus, healthEmoji(s.HealthStatus), s.HealthStatus))
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+func healthEmoji(health string) string {
+	switch health {
+	case "Healthy":
+		return "✅"
+	case "Progressing":
+		return "⏳"
+	case "Degraded", "Missing":
+		return "❌"
+	default:
+		return "❔"
+	}
+}
+
+// repoFlags marks a repo's elevated risk, migrations and API or config
+// changes for the status table.
+func repoFlags(repo *database.ReleaseRepo) string {
+	var flags string
+	switch repo.RiskLevel {
+	case summarizer.RiskHigh:
+		flags += "🔴"
+	case summarizer.RiskMedium:
+		flags += "🟠"
+	}
+	if repo.HasMigration {
+		flags += "🗄️"
+	}
+	if apiChanges, _ := repo.GetAPIChanges(); len(apiChanges) > 0 {
+		flags += "🔌"
+	}
+	if configChanges, _ := repo.GetConfigChanges(); len(configChanges) > 0 {
+		flags += "⚙️"
+	}
+	return flags
+}
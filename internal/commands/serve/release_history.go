@@ -0,0 +1,159 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/mappings"
+)
+
+// historyLimit caps the timeline at the most recent entries; the dashboard
+// keeps the full history.
+const historyLimit = 30
+
+func (c *botCommands) history(ctx context.Context, req *Request, resp Responder) {
+	release, ok := c.releaseForArgs(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	entries, err := c.dashboardServer.Service().GetHistory(ctx, release.ID)
+	if err != nil {
+		resp.Ephemeral(ctx, fmt.Sprintf("Failed to load history: %v", err))
+		return
+	}
+
+	resp.Reply(ctx, c.formatHistory(ctx, release, entries))
+}
+
+// formatHistory renders entries, which GetHistory returns newest first, as an
+// oldest-first timeline.
+func (c *botCommands) formatHistory(ctx context.Context, release *database.Release, entries []database.ReleaseHistory) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### 🕓 History: `%s` → `%s` · **%s**\n", release.SourceBranch, release.DestBranch, release.Status))
+	sb.WriteString(fmt.Sprintf("[View Dashboard](%s)\n\n", c.releaseURL(release.ID)))
+
+	if len(entries) == 0 {
+		sb.WriteString("_No history recorded yet._")
+		return sb.String()
+	}
+	if len(entries) > historyLimit {
+		sb.WriteString(fmt.Sprintf("_Showing the last %d of %d entries._\n\n", historyLimit, len(entries)))
+		entries = entries[:historyLimit]
+	}
+
+	actors := make(map[string]string)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		actor, ok := actors[e.Actor]
+		if !ok {
+			actor = c.resolveActor(ctx, e.Actor)
+			actors[e.Actor] = actor
+		}
+
+		var details map[string]any
+		if e.Details != "" {
+			_ = json.Unmarshal([]byte(e.Details), &details)
+		}
+
+		emoji, text := describeHistory(e.Action, details)
+		sb.WriteString(fmt.Sprintf("- `%s` %s %s %s\n", time.Unix(e.CreatedAt, 0).Format("01-02 15:04"), emoji, actor, text))
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// resolveActor turns a history actor into a Mattermost mention. Actors are
+// dashboard user emails, Mattermost usernames from chat, or "system" and
+// "scheduler" for automatic changes.
+func (c *botCommands) resolveActor(ctx context.Context, actor string) string {
+	switch actor {
+	case "":
+		return "_unknown_"
+	case "system", "scheduler":
+		return "_" + actor + "_"
+	}
+	if !strings.Contains(actor, "@") {
+		return "@" + actor
+	}
+
+	user, err := c.dashboardServer.Service().GetUserByEmail(ctx, actor)
+	if err != nil || user == nil {
+		return actor
+	}
+	if user.MattermostUser != "" {
+		return "@" + user.MattermostUser
+	}
+	if user.GitHubUser != "" {
+		if mm, ok := mappings.MattermostFromGitHub(ctx, user.GitHubUser); ok {
+			return "@" + mm
+		}
+	}
+	return actor
+}
+
+func describeHistory(action string, details map[string]any) (string, string) {
+	str := func(key string) string {
+		if v, ok := details[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	via := ""
+	if str("via") == "chat" {
+		via = " via chat"
+	}
+
+	switch action {
+	case "release_created":
+		return "🆕", fmt.Sprintf("created the release `%s` → `%s`", str("source_branch"), str("dest_branch"))
+	case "repos_synced":
+		return "🔄", fmt.Sprintf("synced %s repositories", str("count"))
+	case "release_refreshed":
+		return "🔄", "refreshed the release"
+	case "channel_created":
+		return "💬", fmt.Sprintf("created ~%s and invited %s participants", str("channel"), str("invited"))
+	case "channel_archived":
+		return "🔒", fmt.Sprintf("archived the release channel (%s)", str("status"))
+	case "repo_confirmed":
+		return "✅", fmt.Sprintf("confirmed `%s`", str("repo"))
+	case "repo_unconfirmed":
+		return "↩️", fmt.Sprintf("withdrew confirmation of `%s`", str("repo"))
+	case "repo_excluded":
+		return "➖", fmt.Sprintf("excluded `%s`", str("repo"))
+	case "repo_included":
+		return "➕", fmt.Sprintf("included `%s`", str("repo"))
+	case "repo_dependencies_updated":
+		return "🔗", fmt.Sprintf("updated dependencies of `%s`", str("repo"))
+	case "approval_added":
+		return "👍", fmt.Sprintf("added **%s** approval%s", strings.ToUpper(str("type")), via)
+	case "approval_revoked":
+		return "👎", fmt.Sprintf("revoked **%s** approval", strings.ToUpper(str("type")))
+	case "participants_poked":
+		return "🔔", fmt.Sprintf("poked %s pending participants", str("count"))
+	case "release_declined":
+		text := "declined the release" + via
+		if reason := str("reason"); reason != "" {
+			text += ": " + reason
+		}
+		return "❌", text
+	case "notes_updated":
+		return "📝", "updated the release notes"
+	case "breaking_changes_updated":
+		return "🚨", "updated the breaking changes"
+	case "status_changed":
+		text := fmt.Sprintf("moved the release from **%s** to **%s**", str("from"), str("to"))
+		if reason := str("reason"); reason != "" {
+			text += ": " + reason
+		}
+		return "🚦", text
+	case "freeze_overridden":
+		return "🧊", fmt.Sprintf("overrode the deployment freeze (%s) to %s", str("reason"), str("action"))
+	default:
+		return "•", strings.ReplaceAll(action, "_", " ")
+	}
+}
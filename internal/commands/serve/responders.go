@@ -0,0 +1,97 @@
+package serve
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/pkg/mattermost"
+)
+
+// threadResponder posts every reply into the thread the command was issued in.
+type threadResponder struct {
+	mmBot     *mattermost.Bot
+	channelID string
+	threadID  string
+	userName  string
+}
+
+func newThreadResponder(mmBot *mattermost.Bot, channelID, threadID, userName string) *threadResponder {
+	return &threadResponder{
+		mmBot:     mmBot,
+		channelID: channelID,
+		threadID:  threadID,
+		userName:  userName,
+	}
+}
+
+func (t *threadResponder) Reply(ctx context.Context, text string) {
+	message := fmt.Sprintf("%s\n\n_Requested by @%s_", text, t.userName)
+	if err := t.mmBot.PostMessageInThread(ctx, t.channelID, t.threadID, message); err != nil {
+		logger.Error().Err(err).Str("channel", t.channelID).Msg("Failed to post command reply")
+	}
+}
+
+func (t *threadResponder) Ephemeral(ctx context.Context, text string) {
+	t.Reply(ctx, text)
+}
+
+// httpResponder answers slash commands and outgoing webhooks. Replies made
+// while the handler runs become the HTTP response; later replies from async
+// work are posted to the channel through the bot.
+type httpResponder struct {
+	mu        sync.Mutex
+	thread    *threadResponder
+	pending   []string
+	inChannel bool
+	flushed   bool
+}
+
+func newHTTPResponder(mmBot *mattermost.Bot, channelID, userName string) *httpResponder {
+	return &httpResponder{
+		thread: newThreadResponder(mmBot, channelID, "", userName),
+	}
+}
+
+func (h *httpResponder) Reply(ctx context.Context, text string) {
+	h.respond(ctx, text, true)
+}
+
+func (h *httpResponder) Ephemeral(ctx context.Context, text string) {
+	h.respond(ctx, text, false)
+}
+
+func (h *httpResponder) respond(ctx context.Context, text string, inChannel bool) {
+	h.mu.Lock()
+	if !h.flushed {
+		h.pending = append(h.pending, text)
+		h.inChannel = h.inChannel || inChannel
+		h.mu.Unlock()
+		return
+	}
+	h.mu.Unlock()
+
+	if h.thread.mmBot == nil {
+		logger.Warn().Str("channel", h.thread.channelID).Msg("Dropping async command reply: bot not configured")
+		return
+	}
+	h.thread.Reply(ctx, text)
+}
+
+func (h *httpResponder) flush(w http.ResponseWriter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.flushed = true
+	resp := SlashCommandResponse{
+		ResponseType: "ephemeral",
+		Text:         strings.Join(h.pending, "\n\n"),
+	}
+	if h.inChannel {
+		resp.ResponseType = "in_channel"
+	}
+	respondJSON(w, resp)
+}
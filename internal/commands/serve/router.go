@@ -0,0 +1,260 @@
+package serve
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/mattermost-tools/internal/permissions"
+)
+
+const (
+	TransportSlash     = "slash"
+	TransportMention   = "mention"
+	TransportWebSocket = "websocket"
+)
+
+// Request is a parsed bot command, independent of the transport it arrived on.
+type Request struct {
+	Command   string
+	Args      []string
+	UserID    string
+	UserName  string
+	ChannelID string
+	TeamID    string
+	ThreadID  string
+	Transport string
+	// Prefix is what the user types before the command name, e.g. "@pusheen "
+	// for mentions or "/" for dedicated slash commands. Used in usage hints
+	// and help.
+	Prefix string
+}
+
+// Responder writes command output back to wherever the command came from.
+type Responder interface {
+	// Reply posts a message visible to everyone in the channel or thread.
+	Reply(ctx context.Context, text string)
+	// Ephemeral posts a message meant for the requester only, when the
+	// transport supports it, and falls back to Reply otherwise.
+	Ephemeral(ctx context.Context, text string)
+}
+
+type HandlerFunc func(ctx context.Context, req *Request, resp Responder)
+
+type Command struct {
+	Name        string
+	Aliases     []string
+	Args        string
+	Description string
+	Example     string
+	MinArgs     int
+	// MaxArgs of -1 means any number of arguments.
+	MaxArgs int
+	// Details are extra usage notes shown by "help <command>".
+	Details string
+	// Permission is the permissions rule key guarding the command;
+	// defaults to Name.
+	Permission string
+	NeedsBot   bool
+	Handler    HandlerFunc
+}
+
+func (c *Command) permissionKey() string {
+	if c.Permission != "" {
+		return c.Permission
+	}
+	return c.Name
+}
+
+func (c *Command) Usage(prefix string) string {
+	usage := prefix + c.Name
+	if c.Args != "" {
+		usage += " " + c.Args
+	}
+	return usage
+}
+
+// defaultBotUsername is used in mention prefixes until the bot's own
+// username is known.
+const defaultBotUsername = "pusheen"
+
+type Router struct {
+	commands    map[string]*Command
+	lookup      map[string]*Command
+	permissions *permissions.Checker
+	botReady    bool
+	botUsername string
+}
+
+func NewRouter(permissions *permissions.Checker, botReady bool) *Router {
+	return &Router{
+		commands:    make(map[string]*Command),
+		lookup:      make(map[string]*Command),
+		permissions: permissions,
+		botReady:    botReady,
+		botUsername: defaultBotUsername,
+	}
+}
+
+// SetBotUsername sets the username mentions address. Call it before
+// dispatching requests.
+func (r *Router) SetBotUsername(name string) {
+	if name != "" {
+		r.botUsername = name
+	}
+}
+
+// MentionPrefix is the Prefix of requests that mention the bot.
+func (r *Router) MentionPrefix() string {
+	return "@" + r.botUsername + " "
+}
+
+func (r *Router) Register(cmd *Command) {
+	if _, exists := r.lookup[cmd.Name]; exists {
+		panic(fmt.Sprintf("command %q registered twice", cmd.Name))
+	}
+	r.commands[cmd.Name] = cmd
+	r.lookup[cmd.Name] = cmd
+	for _, alias := range cmd.Aliases {
+		if _, exists := r.lookup[alias]; exists {
+			panic(fmt.Sprintf("command alias %q registered twice", alias))
+		}
+		r.lookup[alias] = cmd
+	}
+}
+
+func (r *Router) Lookup(name string) (*Command, bool) {
+	cmd, ok := r.lookup[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// Commands returns registered commands sorted by name.
+func (r *Router) Commands() []*Command {
+	cmds := make([]*Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].Name < cmds[j].Name
+	})
+	return cmds
+}
+
+func (r *Router) Allowed(ctx context.Context, cmd *Command, req *Request) bool {
+	return r.permissions.Allowed(ctx, cmd.permissionKey(), permissions.Subject{
+		UserName:  req.UserName,
+		TeamID:    req.TeamID,
+		ChannelID: req.ChannelID,
+	})
+}
+
+// Dispatch resolves the command, checks permissions and arguments, and runs
+// its handler. An empty command name runs "help".
+func (r *Router) Dispatch(ctx context.Context, req *Request, resp Responder) {
+	name := strings.ToLower(req.Command)
+	if name == "" {
+		name = "help"
+	}
+
+	cmd, ok := r.Lookup(name)
+	if !ok {
+		debugLog("[router] Unknown command %q from %s via %s", name, req.UserName, req.Transport)
+		resp.Ephemeral(ctx, fmt.Sprintf("Unknown command: `%s`\n\n%s", name, r.Help(ctx, req)))
+		return
+	}
+	req.Command = cmd.Name
+
+	if !r.Allowed(ctx, cmd, req) {
+		debugLog("[router] Permission denied for user %s on command %s", req.UserName, cmd.Name)
+		resp.Ephemeral(ctx, fmt.Sprintf("⛔ You don't have permission to use the `%s` command.", cmd.Name))
+		return
+	}
+
+	if len(req.Args) < cmd.MinArgs || (cmd.MaxArgs >= 0 && len(req.Args) > cmd.MaxArgs) {
+		msg := fmt.Sprintf("Usage: `%s`", cmd.Usage(req.Prefix))
+		if cmd.Example != "" {
+			msg += fmt.Sprintf("\nExample: `%s%s`", req.Prefix, cmd.Example)
+		}
+		resp.Ephemeral(ctx, msg)
+		return
+	}
+
+	if cmd.NeedsBot && !r.botReady {
+		resp.Ephemeral(ctx, "Bot not configured. Set mattermost_url and mattermost_token in config.")
+		return
+	}
+
+	debugLog("[router] Command: %q, Args: %v, User: %s, Transport: %s", cmd.Name, req.Args, req.UserName, req.Transport)
+	cmd.Handler(ctx, req, resp)
+}
+
+// Help lists the commands the requester may use, written with the request's
+// prefix.
+func (r *Router) Help(ctx context.Context, req *Request) string {
+	prefix := r.helpPrefix(req)
+
+	var sb strings.Builder
+	sb.WriteString("**Available Commands:**\n")
+	for _, cmd := range r.Commands() {
+		if !r.Allowed(ctx, cmd, req) {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n• **%s** - %s\n", cmd.Usage(""), cmd.Description))
+		if cmd.Example != "" {
+			sb.WriteString(fmt.Sprintf("  Example: `%s%s`\n", prefix, cmd.Example))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("\nUse `%shelp <command>` for details.", prefix))
+	return sb.String()
+}
+
+// HelpHandler serves "help" and "help <command>".
+func (r *Router) HelpHandler(ctx context.Context, req *Request, resp Responder) {
+	if len(req.Args) == 0 {
+		resp.Ephemeral(ctx, r.Help(ctx, req))
+		return
+	}
+	text, ok := r.CommandHelp(ctx, req, req.Args[0])
+	if !ok {
+		resp.Ephemeral(ctx, fmt.Sprintf("Unknown command: `%s`\n\n%s", req.Args[0], r.Help(ctx, req)))
+		return
+	}
+	resp.Ephemeral(ctx, text)
+}
+
+// CommandHelp describes one command in detail. It returns false when the
+// command does not exist or the requester may not use it, so help does not
+// reveal hidden commands.
+func (r *Router) CommandHelp(ctx context.Context, req *Request, name string) (string, bool) {
+	cmd, ok := r.Lookup(name)
+	if !ok || !r.Allowed(ctx, cmd, req) {
+		return "", false
+	}
+	prefix := r.helpPrefix(req)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s** - %s\n\nUsage: `%s`\n", cmd.Name, cmd.Description, cmd.Usage(prefix)))
+	if cmd.Details != "" {
+		sb.WriteString("\n" + cmd.Details + "\n")
+	}
+	if cmd.Example != "" {
+		sb.WriteString(fmt.Sprintf("Example: `%s%s`\n", prefix, cmd.Example))
+	}
+	if len(cmd.Aliases) > 0 {
+		aliases := make([]string, len(cmd.Aliases))
+		for i, alias := range cmd.Aliases {
+			aliases[i] = "`" + alias + "`"
+		}
+		sb.WriteString("Aliases: " + strings.Join(aliases, ", ") + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("Permission rule: `%s`", cmd.permissionKey()))
+	return sb.String(), true
+}
+
+func (r *Router) helpPrefix(req *Request) string {
+	if req.Prefix != "" {
+		return req.Prefix
+	}
+	return r.MentionPrefix()
+}
@@ -0,0 +1,200 @@
+package serve_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/commands/serve"
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/permissions"
+)
+
+type recordingResponder struct {
+	replies    []string
+	ephemerals []string
+}
+
+func (r *recordingResponder) Reply(_ context.Context, text string) {
+	r.replies = append(r.replies, text)
+}
+
+func (r *recordingResponder) Ephemeral(_ context.Context, text string) {
+	r.ephemerals = append(r.ephemerals, text)
+}
+
+func newTestRouter(legacyPermissions map[string][]string, botReady bool) (*serve.Router, *[]*serve.Request) {
+	var handled []*serve.Request
+	router := serve.NewRouter(permissions.NewChecker(config.PermissionsConfig{}, legacyPermissions, nil), botReady)
+	router.Register(&serve.Command{
+		Name:    "help",
+		MaxArgs: -1,
+		Handler: func(ctx context.Context, req *serve.Request, resp serve.Responder) {
+			handled = append(handled, req)
+		},
+	})
+	router.Register(&serve.Command{
+		Name:     "changes",
+		Aliases:  []string{"diff"},
+		Args:     "<source-branch> <dest-branch>",
+		Example:  "changes uat master",
+		MinArgs:  2,
+		MaxArgs:  2,
+		NeedsBot: true,
+		Handler: func(ctx context.Context, req *serve.Request, resp serve.Responder) {
+			handled = append(handled, req)
+			resp.Reply(ctx, "ok")
+		},
+	})
+	return router, &handled
+}
+
+func TestRouter_Dispatch(t *testing.T) {
+	type tc struct {
+		name        string
+		permissions map[string][]string
+		botReady    bool
+		req         serve.Request
+		handled     string
+		ephemeral   string
+	}
+
+	cases := []tc{
+		{
+			name:     "alias resolves to canonical command",
+			botReady: true,
+			req:      serve.Request{Command: "DIFF", Args: []string{"uat", "master"}, UserName: "alice"},
+			handled:  "changes",
+		},
+		{
+			name:     "empty command runs help",
+			botReady: true,
+			req:      serve.Request{UserName: "alice"},
+			handled:  "help",
+		},
+		{
+			name:      "unknown command",
+			botReady:  true,
+			req:       serve.Request{Command: "nope", UserName: "alice"},
+			ephemeral: "Unknown command: `nope`",
+		},
+		{
+			name:      "wrong argument count shows usage with transport prefix",
+			botReady:  true,
+			req:       serve.Request{Command: "changes", Args: []string{"uat"}, UserName: "alice", Prefix: "/"},
+			ephemeral: "Usage: `/changes <source-branch> <dest-branch>`\nExample: `/changes uat master`",
+		},
+		{
+			name:        "permission checked against canonical name",
+			botReady:    true,
+			permissions: map[string][]string{"changes": {"bob"}},
+			req:         serve.Request{Command: "diff", Args: []string{"uat", "master"}, UserName: "alice"},
+			ephemeral:   "permission to use the `changes` command",
+		},
+		{
+			name:        "permitted user",
+			botReady:    true,
+			permissions: map[string][]string{"changes": {"Alice"}},
+			req:         serve.Request{Command: "changes", Args: []string{"uat", "master"}, UserName: "alice"},
+			handled:     "changes",
+		},
+		{
+			name:      "bot required",
+			req:       serve.Request{Command: "changes", Args: []string{"uat", "master"}, UserName: "alice"},
+			ephemeral: "Bot not configured",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			router, handled := newTestRouter(c.permissions, c.botReady)
+			resp := &recordingResponder{}
+			req := c.req
+
+			router.Dispatch(context.Background(), &req, resp)
+
+			if c.handled != "" {
+				require.Len(t, *handled, 1)
+				require.Equal(t, c.handled, (*handled)[0].Command)
+				require.Empty(t, resp.ephemerals)
+				return
+			}
+			require.Empty(t, *handled)
+			require.Len(t, resp.ephemerals, 1)
+			require.Contains(t, resp.ephemerals[0], c.ephemeral)
+		})
+	}
+}
+
+func TestRouter_RegisterDuplicatePanics(t *testing.T) {
+	router, _ := newTestRouter(nil, true)
+
+	require.Panics(t, func() {
+		router.Register(&serve.Command{Name: "other", Aliases: []string{"diff"}})
+	})
+}
+
+func TestRouter_Help(t *testing.T) {
+	type tc struct {
+		name        string
+		permissions map[string][]string
+		req         serve.Request
+		contains    []string
+		notContains []string
+	}
+
+	cases := []tc{
+		{
+			name:     "lists commands with the bot's username",
+			req:      serve.Request{UserName: "alice"},
+			contains: []string{"**changes <source-branch> <dest-branch>**", "Example: `@mmbot changes uat master`", "`@mmbot help <command>`"},
+		},
+		{
+			name:     "uses the request prefix",
+			req:      serve.Request{UserName: "alice", Prefix: "/mmtools "},
+			contains: []string{"Example: `/mmtools changes uat master`"},
+		},
+		{
+			name:        "hides commands the requester may not use",
+			permissions: map[string][]string{"changes": {"bob"}},
+			req:         serve.Request{UserName: "alice"},
+			contains:    []string{"**help**"},
+			notContains: []string{"changes"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			router, _ := newTestRouter(c.permissions, true)
+			router.SetBotUsername("mmbot")
+			req := c.req
+
+			help := router.Help(context.Background(), &req)
+
+			for _, s := range c.contains {
+				require.Contains(t, help, s)
+			}
+			for _, s := range c.notContains {
+				require.NotContains(t, help, s)
+			}
+		})
+	}
+}
+
+func TestRouter_CommandHelp(t *testing.T) {
+	router, _ := newTestRouter(map[string][]string{"changes": {"bob"}}, true)
+	req := &serve.Request{UserName: "bob", Prefix: "/"}
+
+	text, ok := router.CommandHelp(context.Background(), req, "diff")
+	require.True(t, ok)
+	require.Contains(t, text, "Usage: `/changes <source-branch> <dest-branch>`")
+	require.Contains(t, text, "Aliases: `diff`")
+	require.Contains(t, text, "Permission rule: `changes`")
+
+	_, ok = router.CommandHelp(context.Background(), &serve.Request{UserName: "alice"}, "changes")
+	require.False(t, ok, "commands the requester may not use stay hidden")
+
+	_, ok = router.CommandHelp(context.Background(), req, "nope")
+	require.False(t, ok)
+}
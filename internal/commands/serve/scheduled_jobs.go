@@ -0,0 +1,158 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/commands/prs"
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/internal/scheduler"
+	"github.com/user/mattermost-tools/pkg/mattermost"
+)
+
+const (
+	JobPRReminder    = "pr_reminder"
+	JobPokeReleases  = "poke_releases"
+	JobChangesDigest = "changes_digest"
+)
+
+// newScheduler builds the scheduler for the configured jobs, failing on bad
+// schedules or jobs whose dependencies are not configured.
+func (c *botCommands) newScheduler(cfg *config.Config, db *gorm.DB) (*scheduler.Scheduler, error) {
+	loc := time.Local
+	if tz := cfg.Serve.Scheduler.Timezone; tz != "" {
+		var err error
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("loading timezone: %w", err)
+		}
+	}
+
+	s := scheduler.New(scheduler.NewDBStore(db))
+	for _, jc := range cfg.Serve.Scheduler.Jobs {
+		name := jc.Name
+		if name == "" {
+			name = jc.Type
+		}
+
+		sched, err := scheduler.Parse(jc.Schedule, loc)
+		if err != nil {
+			return nil, fmt.Errorf("job %q: %w", name, err)
+		}
+
+		run, err := c.scheduledJob(cfg, jc)
+		if err != nil {
+			return nil, fmt.Errorf("job %q: %w", name, err)
+		}
+
+		if err := s.Add(scheduler.Job{Name: name, Schedule: sched, Run: run}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (c *botCommands) scheduledJob(cfg *config.Config, jc config.ScheduledJobConfig) (func(ctx context.Context) error, error) {
+	switch jc.Type {
+	case JobPRReminder:
+		return c.prReminderJob(cfg, jc)
+	case JobPokeReleases:
+		if c.dashboardServer == nil || c.mmBot == nil {
+			return nil, fmt.Errorf("%s requires the dashboard and the Mattermost bot", jc.Type)
+		}
+		return c.pokeReleasesJob, nil
+	case JobChangesDigest:
+		if c.mmBot == nil {
+			return nil, fmt.Errorf("%s requires the Mattermost bot", jc.Type)
+		}
+		if jc.ChannelID == "" || jc.Source == "" || jc.Dest == "" {
+			return nil, fmt.Errorf("%s requires channel_id, source and dest", jc.Type)
+		}
+		return func(ctx context.Context) error {
+			return c.changesDigestJob(ctx, jc.ChannelID, jc.Source, jc.Dest)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown job type %q", jc.Type)
+	}
+}
+
+func (c *botCommands) prReminderJob(cfg *config.Config, jc config.ScheduledJobConfig) (func(ctx context.Context) error, error) {
+	webhookURL := cfg.PRs.WebhookURL
+	if webhookURL == "" {
+		webhookURL = os.Getenv("MATTERMOST_WEBHOOK_URL")
+	}
+	if webhookURL == "" {
+		return nil, fmt.Errorf("%s requires prs.webhook_url or MATTERMOST_WEBHOOK_URL", jc.Type)
+	}
+
+	targets := jc.Channels
+	if len(targets) == 0 {
+		targets = cfg.PRs.Channels
+	}
+	if len(targets) == 0 {
+		targets = []string{""}
+	}
+
+	webhook := mattermost.NewWebhook(webhookURL)
+	return func(ctx context.Context) error {
+		repoPRs, err := prs.CollectPendingPRs(ctx, c.ghClient, c.org, c.ignoredRepos, debugWriter{prefix: "[pr_reminder] "})
+		if err != nil {
+			return err
+		}
+		if len(repoPRs) == 0 {
+			logger.Info().Msg("No pending PRs, skipping reminder")
+			return nil
+		}
+
+		msg, unmappedUsers := prs.BuildReminder(ctx, repoPRs, time.Now(), cfg.PRs.Attachments, cfg.PRs)
+		if len(unmappedUsers) > 0 {
+			logger.Warn().Strs("users", unmappedUsers).Msg("Unmapped GitHub users in PR reminder")
+		}
+
+		var errs []error
+		for _, channel := range targets {
+			msg.Channel = channel
+			if err := webhook.Send(ctx, msg); err != nil {
+				errs = append(errs, fmt.Errorf("posting to %q: %w", channel, err))
+			}
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+func (c *botCommands) pokeReleasesJob(ctx context.Context) error {
+	poked, err := c.dashboardServer.PokeOpenReleases(ctx, "scheduler")
+	logger.Info().Int("releases", poked).Msg("Poked release participants")
+	return err
+}
+
+func (c *botCommands) changesDigestJob(ctx context.Context, channelID, sourceBranch, destBranch string) error {
+	results, err := c.collectChanges(ctx, sourceBranch, destBranch, nil)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		logger.Info().Str("source", sourceBranch).Str("dest", destBranch).Msg("No changes, skipping digest")
+		return nil
+	}
+
+	return c.mmBot.PostMessage(ctx, channelID, formatChanges(sourceBranch, destBranch, results))
+}
+
+// debugWriter sends progress output from shared helpers to the debug log.
+type debugWriter struct {
+	prefix string
+}
+
+func (w debugWriter) Write(p []byte) (int, error) {
+	debugLog("%s%s", w.prefix, strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
@@ -7,29 +7,35 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"os/exec"
 	"os/signal"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
-	"gorm.io/gorm"
 
 	"github.com/user/mattermost-tools/internal/config"
 	"github.com/user/mattermost-tools/internal/dashboard"
 	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/jobs"
 	"github.com/user/mattermost-tools/internal/logger"
 	"github.com/user/mattermost-tools/internal/mappings"
+	"github.com/user/mattermost-tools/internal/permissions"
+	"github.com/user/mattermost-tools/internal/releasenotes"
+	"github.com/user/mattermost-tools/internal/scheduler"
+	"github.com/user/mattermost-tools/internal/summarizer"
 	"github.com/user/mattermost-tools/pkg/github"
 	"github.com/user/mattermost-tools/pkg/mattermost"
 	"github.com/user/mattermost-tools/pkg/release"
 	"github.com/user/mattermost-tools/web"
 )
 
+// workerShutdownTimeout bounds how long shutdown waits for running jobs to
+// notice cancellation.
+const workerShutdownTimeout = 30 * time.Second
+
 var (
 	configFile string
 	port       int
@@ -96,19 +102,15 @@ func runServe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("GITHUB_TOKEN environment variable is required")
 	}
 
-	var db *gorm.DB
-	if cfg.Serve.Dashboard.Enabled {
-		sqlitePath := cfg.Serve.Dashboard.SQLitePath
-		if sqlitePath == "" {
-			sqlitePath = "./releases.db"
-		}
-		var err error
-		db, err = database.NewSQLiteDB(sqlitePath)
-		if err != nil {
-			return fmt.Errorf("initializing database: %w", err)
-		}
-		log.Info().Str("path", sqlitePath).Msg("Dashboard database initialized")
+	sqlitePath := cfg.Serve.Dashboard.SQLitePath
+	if sqlitePath == "" {
+		sqlitePath = config.DefaultSQLitePath
 	}
+	db, err := database.NewSQLiteDB(sqlitePath)
+	if err != nil {
+		return fmt.Errorf("initializing database: %w", err)
+	}
+	log.Info().Str("path", sqlitePath).Msg("Database initialized")
 
 	listenPort := port
 	if !cmd.Flags().Changed("port") && cfg.Serve.Port > 0 {
@@ -132,6 +134,34 @@ func runServe(cmd *cobra.Command, args []string) error {
 		mmBot = mattermost.NewBot(cfg.Serve.MattermostURL, cfg.Serve.MattermostToken)
 	}
 
+	permissionsCfg := cfg.Serve.Permissions
+	if permissionsCfg.TeamID == "" {
+		permissionsCfg.TeamID = cfg.Serve.Release.TeamID
+	}
+	var lookup permissions.Lookup
+	if mmBot != nil {
+		lookup = mmBot
+	}
+	checker := permissions.NewChecker(permissionsCfg, cfg.Serve.CommandPermissions, lookup)
+
+	jobManager := jobs.NewManager(db, cfg.Serve.JobWorkers)
+
+	summaryCache := summarizer.NewCache(db, cfg.Summarizer.CacheTTL)
+	if pruned, err := summaryCache.Prune(context.Background()); err != nil {
+		log.Warn().Err(err).Msg("Failed to prune summary cache")
+	} else if pruned > 0 {
+		log.Info().Int64("count", pruned).Msg("Pruned expired summaries")
+	}
+	sum, err := summarizer.New(cfg.Summarizer, summaryCache)
+	if err != nil {
+		return fmt.Errorf("configuring summarizer: %w", err)
+	}
+
+	prSummaryBots := cfg.Summarizer.PRSummaryBots
+	if prSummaryBots == nil {
+		prSummaryBots = config.DefaultPRSummaryBots
+	}
+
 	var dashboardServer *dashboard.Server
 	if cfg.Serve.Dashboard.Enabled && db != nil {
 		sessionSecret := []byte(cfg.Serve.MattermostToken)
@@ -157,6 +187,25 @@ func runServe(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("initializing dashboard server: %w", err)
 		}
+		dashboardServer.SetPermissions(checker)
+		dashboardServer.SetJobs(jobManager)
+		dashboardServer.SetSummarizer(sum)
+		dashboardServer.SetSummaryCache(summaryCache)
+		notesRenderer, err := releasenotes.NewRenderer(cfg.Serve.Release.NotesTemplates)
+		if err != nil {
+			return fmt.Errorf("loading release notes templates: %w", err)
+		}
+		dashboardServer.SetNotesRenderer(notesRenderer)
+		dashboardServer.Service().SetHotfixApprovals(cfg.Serve.Release.HotfixApprovals)
+		mappings.SetStore(dashboardServer.Service())
+
+		freezes, err := dashboard.ConfigFreezes(cfg.Serve.Freeze)
+		if err != nil {
+			return fmt.Errorf("configuring freeze windows: %w", err)
+		}
+		if err := dashboardServer.Service().SyncConfigFreezes(context.Background(), freezes); err != nil {
+			return fmt.Errorf("syncing freeze windows: %w", err)
+		}
 		log.Info().Str("url", cfg.Serve.Dashboard.BaseURL).Msg("Dashboard enabled")
 	}
 
@@ -178,14 +227,47 @@ func runServe(cmd *cobra.Command, args []string) error {
 	if dashboardServer != nil && mmBot != nil {
 		baseURL := cfg.Serve.Dashboard.BaseURL
 		dashboardServer.Service().SetFullApprovalCallback(func(rel *database.Release) {
-			message := fmt.Sprintf("✅ **Release Ready to Deploy**\n`%s` → `%s`\nApproved by: Dev (%s), QA (%s)\n[View Details](%s/releases/%s)",
-				rel.SourceBranch, rel.DestBranch,
-				rel.DevApprovedBy, rel.QAApprovedBy,
+			var approvers []string
+			if rel.DevApprovedBy != "" {
+				approvers = append(approvers, "Dev ("+rel.DevApprovedBy+")")
+			}
+			if rel.QAApprovedBy != "" {
+				approvers = append(approvers, "QA ("+rel.QAApprovedBy+")")
+			}
+			kind := "Release"
+			if rel.IsHotfix {
+				kind = "🩹 Hotfix"
+			}
+			message := fmt.Sprintf("✅ **%s Ready to Deploy**\n`%s` → `%s`\nApproved by: %s\n[View Details](%s/releases/%s)",
+				kind, rel.SourceBranch, rel.DestBranch,
+				strings.Join(approvers, ", "),
 				baseURL, rel.ID)
 			mmBot.PostMessage(context.Background(), rel.ChannelID, message)
 		})
 	}
 
+	var releaseChannels *dashboard.ReleaseChannels
+	if dashboardServer != nil && mmBot != nil && cfg.Serve.Release.DedicatedChannel {
+		if cfg.Serve.Release.TeamID == "" {
+			return fmt.Errorf("release.team_id is required when release.dedicated_channel is enabled")
+		}
+		releaseChannels = dashboard.NewReleaseChannels(dashboardServer.Service(), mmBot, dashboard.ReleaseChannelConfig{
+			TeamID:           cfg.Serve.Release.TeamID,
+			Private:          cfg.Serve.Release.PrivateChannel,
+			Prefix:           cfg.Serve.Release.ChannelPrefix,
+			DefaultReviewers: cfg.Serve.Release.DefaultReviewers,
+			DefaultQA:        cfg.Serve.Release.DefaultQA,
+			BaseURL:          cfg.Serve.Dashboard.BaseURL,
+		})
+		dashboardServer.SetReleaseChannels(releaseChannels)
+		dashboardServer.Service().SetReleaseClosedCallback(func(rel *database.Release) {
+			if err := releaseChannels.Archive(context.Background(), rel); err != nil {
+				log.Error().Err(err).Str("release_id", rel.ID).Msg("Failed to archive release channel")
+			}
+		})
+		log.Info().Msg("Dedicated release channels enabled")
+	}
+
 	if dashboardServer != nil && ghClient != nil {
 		ciTracker = dashboard.NewCITracker(dashboardServer.Service(), ghClient, org, 30*time.Second)
 		dashboardServer.SetCITracker(ciTracker)
@@ -203,6 +285,27 @@ func runServe(cmd *cobra.Command, args []string) error {
 		if ciTracker != nil {
 			ciTracker.OnCISuccess(argocdTracker.InitDeploymentTrackingForRepo)
 		}
+		if mmBot != nil {
+			baseURL := cfg.Serve.Dashboard.BaseURL
+			argocdTracker.OnReleaseDeployed(func(ctx context.Context, rel *database.Release, env string) {
+				kind := "Release"
+				if rel.IsHotfix {
+					kind = "🩹 Hotfix"
+				}
+				message := fmt.Sprintf("🚀 **%s Deployed**\n`%s` → `%s` is healthy in **%s**\n[View Details](%s/releases/%s)",
+					kind, rel.SourceBranch, rel.DestBranch, env, baseURL, rel.ID)
+				if err := mmBot.PostMessage(ctx, rel.ChannelID, message); err != nil {
+					log.Warn().Err(err).Str("release_id", rel.ID).Msg("Failed to post release deployed notice")
+				}
+			})
+		}
+	}
+
+	var freezeGuard *dashboard.FreezeGuard
+	if dashboardServer != nil && ghClient != nil && cfg.Serve.Freeze.CommitStatus {
+		freezeGuard = dashboard.NewFreezeGuard(dashboardServer.Service(), ghClient, org, cfg.Serve.Dashboard.BaseURL, time.Minute)
+		freezeGuard.Start()
+		log.Info().Msg("Freeze guard started")
 	}
 
 	allowedTokens := make(map[string]struct{})
@@ -210,11 +313,66 @@ func runServe(cmd *cobra.Command, args []string) error {
 		allowedTokens[t] = struct{}{}
 	}
 
+	freezeLocation, err := dashboard.FreezeLocation(cfg.Serve.Freeze)
+	if err != nil {
+		return fmt.Errorf("configuring freeze windows: %w", err)
+	}
+
+	router := NewRouter(checker, mmBot != nil)
+	if mmBot != nil {
+		me, err := mmBot.GetMe(context.Background())
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to get bot user, help will mention the default username")
+		} else {
+			router.SetBotUsername(me.Username)
+		}
+	}
+	commands := &botCommands{
+		ghClient:        ghClient,
+		org:             org,
+		ignoredRepos:    ignoredRepos,
+		mmBot:           mmBot,
+		releaseManager:  releaseManager,
+		dashboardServer: dashboardServer,
+		releaseChannels: releaseChannels,
+		argocdTracker:   argocdTracker,
+		baseURL:         cfg.Serve.Dashboard.BaseURL,
+		linkVerify:      strings.ToLower(cfg.Serve.GitHubLink.Verify),
+		linkChallenges:  newLinkChallenges(cfg.Serve.GitHubLink.ChallengeTTL),
+		jobs:            jobManager,
+		permissions:     checker,
+		freezeLocation:  freezeLocation,
+		summarizer:      sum,
+		prSummaryBots:   prSummaryBots,
+	}
+	commands.register(router)
+	commands.registerJobHandlers(jobManager)
+
+	// Cancelled on shutdown: workers requeue their running jobs and the
+	// scheduler stops starting new runs.
+	runCtx, stopRunning := context.WithCancel(context.Background())
+	defer stopRunning()
+
+	if err := jobManager.Start(runCtx); err != nil {
+		return fmt.Errorf("starting job queue: %w", err)
+	}
+
+	var sched *scheduler.Scheduler
+	if len(cfg.Serve.Scheduler.Jobs) > 0 {
+		sched, err = commands.newScheduler(cfg, db)
+		if err != nil {
+			return fmt.Errorf("configuring scheduler: %w", err)
+		}
+		go sched.Start(runCtx)
+		log.Info().Int("jobs", len(cfg.Serve.Scheduler.Jobs)).Msg("Scheduler started")
+	}
+
 	mux := http.NewServeMux()
-	mux.HandleFunc("/summarize-pr", withDebug("summarize-pr", withTokenAuth(allowedTokens, handleSummarizePR(ghClient))))
-	mux.HandleFunc("/reviews", withDebug("reviews", withTokenAuth(allowedTokens, handleReviews(ghClient, org, ignoredRepos))))
-	mux.HandleFunc("/changes", withDebug("changes", withTokenAuth(allowedTokens, handleChanges(ghClient, org, ignoredRepos, mmBot, releaseManager))))
-	mux.HandleFunc("/bot-mention", withDebug("bot-mention", withTokenAuth(allowedTokens, handleBotMention(ghClient, org, ignoredRepos, mmBot, releaseManager))))
+	for _, name := range []string{"summarize-pr", "reviews", "changes"} {
+		mux.HandleFunc("/"+name, withDebug(name, withTokenAuth(allowedTokens, handleSlashCommand(router, mmBot, name))))
+	}
+	mux.HandleFunc("/command", withDebug("command", withTokenAuth(allowedTokens, handleSlashCommand(router, mmBot, ""))))
+	mux.HandleFunc("/bot-mention", withDebug("bot-mention", withTokenAuth(allowedTokens, handleBotMention(router, mmBot))))
 	mux.HandleFunc("/health", handleHealth)
 
 	if dashboardServer != nil {
@@ -266,7 +424,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 					if event.Event != "posted" {
 						return
 					}
-					handleWebSocketMessage(wsClient, mmBot, ghClient, org, ignoredRepos, cfg.Serve.CommandPermissions, releaseManager, cfg.Serve.Release, dashboardServer, cfg.Serve.Dashboard.BaseURL, event)
+					handleWebSocketMessage(wsClient, mmBot, router, event)
 				})
 
 				if err := wsClient.Listen(ctx); err != nil {
@@ -290,12 +448,17 @@ func runServe(cmd *cobra.Command, args []string) error {
 	<-quit
 
 	log.Info().Msg("Shutting down server")
+	stopRunning()
+	waitForWorkers(jobManager, sched)
 	if ciTracker != nil {
 		ciTracker.Stop()
 	}
 	if argocdTracker != nil {
 		argocdTracker.Stop()
 	}
+	if freezeGuard != nil {
+		freezeGuard.Stop()
+	}
 	if wsClient != nil {
 		wsClient.Close()
 	}
@@ -305,12 +468,32 @@ func runServe(cmd *cobra.Command, args []string) error {
 	return server.Shutdown(ctx)
 }
 
+// waitForWorkers waits for job workers and scheduled runs to return after
+// their context was cancelled, giving up after workerShutdownTimeout so a
+// handler that ignores cancellation cannot block shutdown.
+func waitForWorkers(jobManager *jobs.Manager, sched *scheduler.Scheduler) {
+	done := make(chan struct{})
+	go func() {
+		jobManager.Wait()
+		if sched != nil {
+			sched.Wait()
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(workerShutdownTimeout):
+		logger.Warn().Dur("timeout", workerShutdownTimeout).Msg("Jobs still running at shutdown")
+	}
+}
+
 func handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
 
-func handleWebSocketMessage(wsClient *mattermost.WebSocketClient, mmBot *mattermost.Bot, ghClient *github.Client, org string, ignoredRepos map[string]struct{}, permissions map[string][]string, releaseManager *release.Manager, releaseCfg config.ReleaseConfig, dashboardServer *dashboard.Server, dashboardBaseURL string, event *mattermost.WebSocketEvent) {
+func handleWebSocketMessage(wsClient *mattermost.WebSocketClient, mmBot *mattermost.Bot, router *Router, event *mattermost.WebSocketEvent) {
 	post, err := wsClient.ParsePost(event)
 	if err != nil {
 		debugLog("[WS] Failed to parse post: %v", err)
@@ -333,176 +516,97 @@ func handleWebSocketMessage(wsClient *mattermost.WebSocketClient, mmBot *matterm
 
 	debugLog("[WS] Bot mentioned in channel %s by %s: %s", post.ChannelID, post.Username, post.Message)
 
-	message := post.Message
-	message = removeMention(message, wsClient.GetBotUsername())
-	message = strings.TrimSpace(message)
-
-	parts := strings.Fields(message)
-	threadID := post.ThreadID()
-	ctx := context.Background()
+	message := strings.TrimSpace(removeMention(post.Message, wsClient.GetBotUsername()))
+	req := parseCommandText(message)
+	req.UserID = post.UserID
+	req.UserName = post.Username
+	req.ChannelID = post.ChannelID
+	req.ThreadID = post.ThreadID()
+	req.Transport = TransportWebSocket
+	req.Prefix = router.MentionPrefix()
 
-	if len(parts) == 0 {
-		mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("%s\n_Requested by @%s_", botHelpText, post.Username))
-		return
-	}
-
-	command := strings.ToLower(parts[0])
-	args := parts[1:]
-
-	debugLog("[WS] Command: %q, Args: %v, ThreadID: %s", command, args, threadID)
-
-	if !hasPermission(permissions, command, post.Username) {
-		debugLog("[WS] Permission denied for user %s on command %s", post.Username, command)
-		mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("⛔ You don't have permission to use the `%s` command.\n\n_Requested by @%s_", command, post.Username))
-		return
-	}
-
-	switch command {
-	case "help", "-h", "--help", "h":
-		mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("%s\n_Requested by @%s_", botHelpText, post.Username))
-
-	case "do-not-touch", "dnt":
-		catURL := fmt.Sprintf("https://cataas.com/cat?t=%d", time.Now().UnixNano())
-		mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("🚨 **INCIDENT REPORTED**\n\n@%s touched the bot. This incident has been logged and will be reported to the appropriate authorities.\n\n![angry cat](%s)\n\n_Requested by @%s_", post.Username, catURL, post.Username))
-
-	case "reviews":
-		handleReviewsWS(ctx, mmBot, ghClient, org, ignoredRepos, post.ChannelID, threadID, post.Username, post.Username)
-
-	case "summarize-pr", "summarize", "summary":
-		if len(args) == 0 {
-			mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("Usage: `@pusheen summarize-pr <github-pr-url>`\n\n_Requested by @%s_", post.Username))
-			return
-		}
-		handleSummarizePRWS(ctx, mmBot, ghClient, post.ChannelID, threadID, post.Username, args[0])
-
-	case "changes":
-		if len(args) != 2 {
-			mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("Usage: `@pusheen changes <source-branch> <dest-branch>`\nExample: `@pusheen changes uat master`\n\n_Requested by @%s_", post.Username))
-			return
-		}
-		mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("⏳ Analyzing changes from `%s` to `%s`... Results will be posted shortly.\n\n_Requested by @%s_", args[0], args[1], post.Username))
-		go processChangesAsync(ghClient, org, ignoredRepos, mmBot, post.ChannelID, threadID, post.Username, args[0], args[1], releaseManager)
+	resp := newThreadResponder(mmBot, post.ChannelID, req.ThreadID, post.Username)
+	router.Dispatch(context.Background(), req, resp)
+}
 
-	case "release-prs", "releases", "pending-releases":
-		if len(args) != 2 {
-			mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("Usage: `@pusheen release-prs <source-branch> <dest-branch>`\nExample: `@pusheen release-prs uat master`\n\n_Requested by @%s_", post.Username))
+// handleSlashCommand serves a slash command. With a fixed command name the
+// whole text is its arguments; otherwise the first word selects the command.
+func handleSlashCommand(router *Router, mmBot *mattermost.Bot, command string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
-		mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("⏳ Checking release PRs from `%s` to `%s`...\n\n_Requested by @%s_", args[0], args[1], post.Username))
-		go processReleasePRsAsync(ghClient, org, ignoredRepos, mmBot, post.ChannelID, threadID, post.Username, args[0], args[1])
 
-	case "create-release", "new-release":
-		if len(args) != 2 {
-			mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("Usage: `@pusheen create-release <source-branch> <dest-branch>`\nExample: `@pusheen create-release uat master`\n\n_Requested by @%s_", post.Username))
-			return
-		}
-		if dashboardServer == nil {
-			mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("Dashboard not configured.\n\n_Requested by @%s_", post.Username))
+		if err := r.ParseForm(); err != nil {
+			respondError(w, "Failed to parse request")
 			return
 		}
-		mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("Creating release from `%s` to `%s`...\n\n_Requested by @%s_", args[0], args[1], post.Username))
-		go processCreateReleaseAsync(dashboardServer.Service(), ghClient, org, ignoredRepos, mmBot, dashboardBaseURL, post.ChannelID, threadID, post.Username, args[0], args[1])
 
-	case "refresh":
-		if releaseManager == nil {
-			mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("Release management not configured.\n\n_Requested by @%s_", post.Username))
-			return
-		}
-		rel := releaseManager.GetReleaseByChannel(post.ChannelID)
-		if rel == nil {
-			mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("No active release in this channel.\n\n_Requested by @%s_", post.Username))
-			return
+		text := strings.TrimSpace(r.FormValue("text"))
+		var req *Request
+		if command != "" {
+			req = &Request{Command: command, Args: strings.Fields(text), Prefix: "/"}
+		} else {
+			req = parseCommandText(text)
+			req.Prefix = r.FormValue("command") + " "
 		}
-		mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("Refreshing release status...\n\n_Requested by @%s_", post.Username))
-		go processRefreshReleaseAsync(releaseManager, mmBot, post.ChannelID, threadID, post.Username)
+		req.UserID = r.FormValue("user_id")
+		req.UserName = r.FormValue("user_name")
+		req.ChannelID = r.FormValue("channel_id")
+		req.TeamID = r.FormValue("team_id")
+		req.Transport = TransportSlash
 
-	default:
-		mmBot.PostMessageInThread(ctx, post.ChannelID, threadID, fmt.Sprintf("Unknown command: `%s`\n\n%s\n_Requested by @%s_", command, botHelpText, post.Username))
+		resp := newHTTPResponder(mmBot, req.ChannelID, req.UserName)
+		router.Dispatch(r.Context(), req, resp)
+		resp.flush(w)
 	}
 }
 
-func handleReviewsWS(ctx context.Context, mmBot *mattermost.Bot, ghClient *github.Client, org string, ignoredRepos map[string]struct{}, channelID, threadID, mmUsername, requestedBy string) {
-	ghUsername, ok := mappings.GitHubFromMattermost(mmUsername)
-	if !ok {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("Your Mattermost username (%s) is not mapped to a GitHub account.\n\n_Requested by @%s_", mmUsername, requestedBy))
-		return
-	}
-
-	repos, err := ghClient.ListRepositories(ctx, org)
-	if err != nil {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("Failed to fetch repositories: %v\n\n_Requested by @%s_", err, requestedBy))
-		return
-	}
-
-	var myPRs []reviewPR
-	now := time.Now()
-
-	for _, repo := range repos {
-		if repo.Archived {
-			continue
-		}
-		if _, ignored := ignoredRepos[repo.Name]; ignored {
-			continue
+func handleBotMention(router *Router, mmBot *mattermost.Bot) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
 		}
 
-		prs, err := ghClient.ListPullRequests(ctx, org, repo.Name)
-		if err != nil {
-			continue
+		if err := r.ParseForm(); err != nil {
+			debugLog("[bot-mention] Failed to parse form: %v", err)
+			respondError(w, "Failed to parse request")
+			return
 		}
 
-		for _, pr := range prs {
-			if pr.Draft {
-				continue
-			}
+		text := strings.TrimSpace(r.FormValue("text"))
+		triggerWord := r.FormValue("trigger_word")
 
-			for _, reviewer := range pr.RequestedReviewers {
-				if reviewer.Login == ghUsername {
-					myPRs = append(myPRs, reviewPR{
-						Repo:      repo,
-						PR:        pr,
-						Staleness: now.Sub(pr.UpdatedAt),
-					})
-					break
-				}
-			}
-		}
-	}
+		debugLog("[bot-mention] Raw text: %q", text)
+		debugLog("[bot-mention] Trigger word: %q", triggerWord)
 
-	if len(myPRs) == 0 {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("🎉 No PRs waiting for your review!\n\n_Requested by @%s_", requestedBy))
-		return
-	}
+		text = strings.TrimSpace(strings.TrimPrefix(text, triggerWord))
 
-	mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("%s\n_Requested by @%s_", formatReviewsList(myPRs), requestedBy))
-}
+		req := parseCommandText(text)
+		req.UserID = r.FormValue("user_id")
+		req.UserName = r.FormValue("user_name")
+		req.ChannelID = r.FormValue("channel_id")
+		req.TeamID = r.FormValue("team_id")
+		req.Transport = TransportMention
+		req.Prefix = router.MentionPrefix()
 
-func handleSummarizePRWS(ctx context.Context, mmBot *mattermost.Bot, ghClient *github.Client, channelID, threadID, requestedBy, prURL string) {
-	owner, repo, number, ok := parsePRURL(prURL)
-	if !ok {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("Invalid PR URL. Expected format: https://github.com/owner/repo/pull/123\n\n_Requested by @%s_", requestedBy))
-		return
-	}
-
-	comments, err := ghClient.GetPRComments(ctx, owner, repo, number)
-	if err != nil {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("Failed to fetch PR comments: %v\n\n_Requested by @%s_", err, requestedBy))
-		return
+		resp := newHTTPResponder(mmBot, req.ChannelID, req.UserName)
+		router.Dispatch(r.Context(), req, resp)
+		resp.flush(w)
 	}
+}
 
-	var latestSummary *github.IssueComment
-	for i := len(comments) - 1; i >= 0; i-- {
-		if comments[i].User.Login == "gemini-code-assist[bot]" {
-			latestSummary = &comments[i]
-			break
-		}
+func parseCommandText(text string) *Request {
+	parts := strings.Fields(text)
+	if len(parts) == 0 {
+		return &Request{}
 	}
-
-	if latestSummary == nil {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("No gemini-code-assist summary found for this PR.\n\n_Requested by @%s_", requestedBy))
-		return
+	return &Request{
+		Command: strings.ToLower(parts[0]),
+		Args:    parts[1:],
 	}
-
-	mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("**PR Summary** ([%s/%s#%s](https://github.com/%s/%s/pull/%s))\n\n%s\n\n_Requested by @%s_", owner, repo, number, owner, repo, number, latestSummary.Body, requestedBy))
 }
 
 func withTokenAuth(allowedTokens map[string]struct{}, next http.HandlerFunc) http.HandlerFunc {
@@ -596,154 +700,6 @@ func removeMention(message, username string) string {
 	return message[:idx] + message[idx+len(lowerMention):]
 }
 
-func hasPermission(permissions map[string][]string, command, username string) bool {
-	if permissions == nil {
-		return true
-	}
-
-	allowedUsers, exists := permissions[command]
-	if !exists || len(allowedUsers) == 0 {
-		return true
-	}
-
-	for _, u := range allowedUsers {
-		if strings.EqualFold(u, username) {
-			return true
-		}
-	}
-	return false
-}
-
-func handleSummarizePR(ghClient *github.Client) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		if err := r.ParseForm(); err != nil {
-			respondError(w, "Failed to parse request")
-			return
-		}
-
-		text := r.FormValue("text")
-		if text == "" {
-			respondError(w, "Usage: /summarize-pr <github-pr-url>")
-			return
-		}
-
-		owner, repo, number, ok := parsePRURL(text)
-		if !ok {
-			respondError(w, "Invalid PR URL. Expected format: https://github.com/owner/repo/pull/123")
-			return
-		}
-
-		ctx := r.Context()
-		comments, err := ghClient.GetPRComments(ctx, owner, repo, number)
-		if err != nil {
-			respondError(w, fmt.Sprintf("Failed to fetch PR comments: %v", err))
-			return
-		}
-
-		var latestSummary *github.IssueComment
-		for i := len(comments) - 1; i >= 0; i-- {
-			if comments[i].User.Login == "gemini-code-assist[bot]" {
-				latestSummary = &comments[i]
-				break
-			}
-		}
-
-		if latestSummary == nil {
-			respondJSON(w, SlashCommandResponse{
-				ResponseType: "ephemeral",
-				Text:         "No gemini-code-assist summary found for this PR.",
-			})
-			return
-		}
-
-		respondJSON(w, SlashCommandResponse{
-			ResponseType: "in_channel",
-			Text:         fmt.Sprintf("**PR Summary** ([%s/%s#%s](https://github.com/%s/%s/pull/%s))\n\n%s", owner, repo, number, owner, repo, number, latestSummary.Body),
-		})
-	}
-}
-
-func handleReviews(ghClient *github.Client, org string, ignoredRepos map[string]struct{}) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		if err := r.ParseForm(); err != nil {
-			respondError(w, "Failed to parse request")
-			return
-		}
-
-		mmUsername := r.FormValue("user_name")
-		ghUsername, ok := mappings.GitHubFromMattermost(mmUsername)
-		if !ok {
-			respondError(w, fmt.Sprintf("Your Mattermost username (%s) is not mapped to a GitHub account.", mmUsername))
-			return
-		}
-
-		ctx := r.Context()
-
-		repos, err := ghClient.ListRepositories(ctx, org)
-		if err != nil {
-			respondError(w, fmt.Sprintf("Failed to fetch repositories: %v", err))
-			return
-		}
-
-		var myPRs []reviewPR
-		now := time.Now()
-
-		for _, repo := range repos {
-			if repo.Archived {
-				continue
-			}
-			if _, ignored := ignoredRepos[repo.Name]; ignored {
-				continue
-			}
-
-			prs, err := ghClient.ListPullRequests(ctx, org, repo.Name)
-			if err != nil {
-				continue
-			}
-
-			for _, pr := range prs {
-				if pr.Draft {
-					continue
-				}
-
-				for _, reviewer := range pr.RequestedReviewers {
-					if reviewer.Login == ghUsername {
-						myPRs = append(myPRs, reviewPR{
-							Repo:      repo,
-							PR:        pr,
-							Staleness: now.Sub(pr.UpdatedAt),
-						})
-						break
-					}
-				}
-			}
-		}
-
-		if len(myPRs) == 0 {
-			respondJSON(w, SlashCommandResponse{
-				ResponseType: "ephemeral",
-				Text:         "🎉 No PRs waiting for your review!",
-			})
-			return
-		}
-
-		respondJSON(w, SlashCommandResponse{
-			ResponseType: "ephemeral",
-			Text:         formatReviewsList(myPRs),
-		})
-	}
-}
-
 type reviewPR struct {
 	Repo      github.Repository
 	PR        github.PullRequest
@@ -789,116 +745,13 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%d days", days)
 }
 
-const botHelpText = `**Available Commands:**
-
-• **changes <source> <dest>** - Compare branches and summarize undeployed changes
-  Example: ` + "`@pusheen changes uat master`" + `
-
-• **release-prs <source> <dest>** - Check for release PRs between branches
-  Example: ` + "`@pusheen release-prs uat master`" + `
-
-• **create-release <source> <dest>** - Create a release playbook run
-  Example: ` + "`@pusheen create-release uat master`" + `
-
-• **refresh** - Refresh release status (in release channel)
-
-• **reviews** - Show PRs waiting for your review
-  Example: ` + "`@pusheen reviews`" + `
-
-• **summarize-pr <url>** - Get AI summary from a GitHub PR
-  Example: ` + "`@pusheen summarize-pr https://github.com/org/repo/pull/123`" + `
-
-• **do-not-touch** - Do NOT use this command
-
-• **help** - Show this help message
-`
-
-func handleBotMention(ghClient *github.Client, org string, ignoredRepos map[string]struct{}, mmBot *mattermost.Bot, releaseManager *release.Manager) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		if err := r.ParseForm(); err != nil {
-			debugLog("[bot-mention] Failed to parse form: %v", err)
-			respondError(w, "Failed to parse request")
-			return
-		}
-
-		text := strings.TrimSpace(r.FormValue("text"))
-		triggerWord := r.FormValue("trigger_word")
-
-		debugLog("[bot-mention] Raw text: %q", text)
-		debugLog("[bot-mention] Trigger word: %q", triggerWord)
-
-		text = strings.TrimPrefix(text, triggerWord)
-		text = strings.TrimSpace(text)
-
-		debugLog("[bot-mention] After removing trigger: %q", text)
-
-		parts := strings.Fields(text)
-		debugLog("[bot-mention] Parsed parts: %v (count=%d)", parts, len(parts))
-
-		if len(parts) == 0 {
-			debugLog("[bot-mention] No command found, showing help")
-			respondJSON(w, SlashCommandResponse{Text: botHelpText})
-			return
-		}
-
-		command := strings.ToLower(parts[0])
-		args := parts[1:]
-
-		debugLog("[bot-mention] Command: %q, Args: %v", command, args)
-
-		switch command {
-		case "help", "-h", "--help", "h":
-			respondJSON(w, SlashCommandResponse{Text: botHelpText})
-
-		case "reviews":
-			handleReviewsFromMention(w, r, ghClient, org, ignoredRepos)
-
-		case "summarize-pr", "summarize", "summary":
-			if len(args) == 0 {
-				respondError(w, "Usage: `@pusheen summarize-pr <github-pr-url>`")
-				return
-			}
-			handleSummarizePRFromMention(w, r, ghClient, args[0])
-
-		case "changes":
-			if len(args) != 2 {
-				respondError(w, "Usage: `@pusheen changes <source-branch> <dest-branch>`\nExample: `@pusheen changes uat master`")
-				return
-			}
-			handleChangesFromMention(w, r, ghClient, org, ignoredRepos, mmBot, releaseManager, args[0], args[1])
-
-		default:
-			respondJSON(w, SlashCommandResponse{
-				Text: fmt.Sprintf("Unknown command: `%s`\n\n%s", command, botHelpText),
-			})
-		}
-	}
-}
-
-func handleReviewsFromMention(w http.ResponseWriter, r *http.Request, ghClient *github.Client, org string, ignoredRepos map[string]struct{}) {
-	mmUsername := r.FormValue("user_name")
-	ghUsername, ok := mappings.GitHubFromMattermost(mmUsername)
-	if !ok {
-		respondError(w, fmt.Sprintf("Your Mattermost username (%s) is not mapped to a GitHub account.", mmUsername))
-		return
-	}
-
-	ctx := r.Context()
-
+func gatherRepoData(ctx context.Context, ghClient *github.Client, sum summarizer.Summarizer, org string, ignoredRepos map[string]struct{}, sourceBranch, destBranch string, pp *progressPost) ([]dashboard.RepoData, error) {
 	repos, err := ghClient.ListRepositories(ctx, org)
 	if err != nil {
-		respondError(w, fmt.Sprintf("Failed to fetch repositories: %v", err))
-		return
+		return nil, err
 	}
 
-	var myPRs []reviewPR
-	now := time.Now()
-
+	var targets []repoTarget
 	for _, repo := range repos {
 		if repo.Archived {
 			continue
@@ -906,414 +759,47 @@ func handleReviewsFromMention(w http.ResponseWriter, r *http.Request, ghClient *
 		if _, ignored := ignoredRepos[repo.Name]; ignored {
 			continue
 		}
-
-		prs, err := ghClient.ListPullRequests(ctx, org, repo.Name)
-		if err != nil {
-			continue
-		}
-
-		for _, pr := range prs {
-			if pr.Draft {
-				continue
-			}
-
-			for _, reviewer := range pr.RequestedReviewers {
-				if reviewer.Login == ghUsername {
-					myPRs = append(myPRs, reviewPR{
-						Repo:      repo,
-						PR:        pr,
-						Staleness: now.Sub(pr.UpdatedAt),
-					})
-					break
-				}
-			}
-		}
-	}
-
-	if len(myPRs) == 0 {
-		respondJSON(w, SlashCommandResponse{
-			Text: "🎉 No PRs waiting for your review!",
-		})
-		return
+		targets = append(targets, repoTarget{Name: repo.Name, Ref: sourceBranch})
 	}
-
-	respondJSON(w, SlashCommandResponse{
-		Text: formatReviewsList(myPRs),
-	})
+	return gatherRepoTargets(ctx, ghClient, sum, org, targets, destBranch, pp)
 }
 
-func handleSummarizePRFromMention(w http.ResponseWriter, r *http.Request, ghClient *github.Client, prURL string) {
-	owner, repo, number, ok := parsePRURL(prURL)
-	if !ok {
-		respondError(w, "Invalid PR URL. Expected format: https://github.com/owner/repo/pull/123")
-		return
-	}
-
-	ctx := r.Context()
-	comments, err := ghClient.GetPRComments(ctx, owner, repo, number)
-	if err != nil {
-		respondError(w, fmt.Sprintf("Failed to fetch PR comments: %v", err))
-		return
-	}
-
-	var latestSummary *github.IssueComment
-	for i := len(comments) - 1; i >= 0; i-- {
-		if comments[i].User.Login == "gemini-code-assist[bot]" {
-			latestSummary = &comments[i]
-			break
-		}
-	}
-
-	if latestSummary == nil {
-		respondJSON(w, SlashCommandResponse{
-			Text: "No gemini-code-assist summary found for this PR.",
-		})
-		return
-	}
-
-	respondJSON(w, SlashCommandResponse{
-		ResponseType: "in_channel",
-		Text:         fmt.Sprintf("**PR Summary** ([%s/%s#%s](https://github.com/%s/%s/pull/%s))\n\n%s", owner, repo, number, owner, repo, number, latestSummary.Body),
-	})
+// repoTarget is a repo to compare against the destination branch and the
+// ref to compare from.
+type repoTarget struct {
+	Name string
+	Ref  string
 }
 
-func handleChangesFromMention(w http.ResponseWriter, r *http.Request, ghClient *github.Client, org string, ignoredRepos map[string]struct{}, mmBot *mattermost.Bot, releaseManager *release.Manager, sourceBranch, destBranch string) {
-	if mmBot == nil {
-		respondError(w, "Bot not configured. Set mattermost_url and mattermost_token in config.")
-		return
-	}
-
-	channelID := r.FormValue("channel_id")
-	userName := r.FormValue("user_name")
-
-	respondJSON(w, SlashCommandResponse{
-		Text: fmt.Sprintf("⏳ Analyzing changes from `%s` to `%s`... Results will be posted shortly.", sourceBranch, destBranch),
-	})
-
-	go processChangesAsync(ghClient, org, ignoredRepos, mmBot, channelID, "", userName, sourceBranch, destBranch, releaseManager)
-}
+func gatherRepoTargets(ctx context.Context, ghClient *github.Client, sum summarizer.Summarizer, org string, targets []repoTarget, destBranch string, pp *progressPost) ([]dashboard.RepoData, error) {
+	pp.SetTotal(len(targets))
 
-func handleChanges(ghClient *github.Client, org string, ignoredRepos map[string]struct{}, mmBot *mattermost.Bot, releaseManager *release.Manager) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		if err := r.ParseForm(); err != nil {
-			respondError(w, "Failed to parse request")
-			return
-		}
-
-		if mmBot == nil {
-			respondError(w, "Bot not configured. Set mattermost_url and mattermost_token in config.")
-			return
-		}
-
-		text := strings.TrimSpace(r.FormValue("text"))
-		parts := strings.Fields(text)
-		if len(parts) != 2 {
-			respondError(w, "Usage: /changes <source-branch> <dest-branch>\nExample: /changes uat master")
-			return
-		}
-
-		sourceBranch := parts[0]
-		destBranch := parts[1]
-		channelID := r.FormValue("channel_id")
-		userName := r.FormValue("user_name")
-
-		respondJSON(w, SlashCommandResponse{
-			ResponseType: "ephemeral",
-			Text:         fmt.Sprintf("⏳ Analyzing changes from `%s` to `%s`... Results will be posted shortly.", sourceBranch, destBranch),
-		})
-
-		go processChangesAsync(ghClient, org, ignoredRepos, mmBot, channelID, "", userName, sourceBranch, destBranch, releaseManager)
-	}
-}
-
-func processChangesAsync(ghClient *github.Client, org string, ignoredRepos map[string]struct{}, mmBot *mattermost.Bot, channelID, threadID, userName, sourceBranch, destBranch string, releaseManager *release.Manager) {
-	ctx := context.Background()
-
-	repos, err := ghClient.ListRepositories(ctx, org)
-	if err != nil {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("@%s ❌ Failed to fetch repositories: %v\n\n_Requested by @%s_", userName, err, userName))
-		return
-	}
-
-	var filteredRepos []github.Repository
-	for _, repo := range repos {
-		if repo.Archived {
-			continue
-		}
-		if _, ignored := ignoredRepos[repo.Name]; ignored {
-			continue
-		}
-		filteredRepos = append(filteredRepos, repo)
-	}
-
-	type repoChange struct {
-		Repo       github.Repository
-		Compare    *github.CompareResult
-		Summary    string
-		IsBreaking bool
-	}
-
-	var (
-		results []repoChange
-		mu      sync.Mutex
-		wg      sync.WaitGroup
-		sem     = make(chan struct{}, 4)
-	)
+	var results []dashboard.RepoData
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4)
 
-	for _, repo := range filteredRepos {
+	for _, repo := range targets {
 		wg.Add(1)
-		go func(repo github.Repository) {
+		go func(repo repoTarget) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			compare, err := ghClient.CompareBranches(ctx, org, repo.Name, destBranch, sourceBranch)
-			if err != nil || compare == nil || compare.TotalCommits == 0 || len(compare.Files) == 0 {
+			if ctx.Err() != nil {
 				return
 			}
 
-			summary, isBreaking := generateChangeSummary(repo.Name, compare)
-
-			mu.Lock()
-			results = append(results, repoChange{
-				Repo:       repo,
-				Compare:    compare,
-				Summary:    summary,
-				IsBreaking: isBreaking,
-			})
-			mu.Unlock()
-		}(repo)
-	}
-
-	wg.Wait()
-
-	if len(results) == 0 {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("@%s ✅ No changes found between `%s` and `%s`\n\n_Requested by @%s_", userName, sourceBranch, destBranch, userName))
-		return
-	}
-
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("### 📦 Undeployed Changes: `%s` → `%s`\n\n", sourceBranch, destBranch))
-	sb.WriteString(fmt.Sprintf("Found changes in **%d** repositories:\n\n", len(results)))
-
-	for _, rc := range results {
-		var emoji string
-		if rc.IsBreaking {
-			emoji = "🚨"
-		} else if rc.Compare.TotalCommits > 10 {
-			emoji = "📚"
-		} else if rc.Compare.TotalCommits > 5 {
-			emoji = "📝"
-		} else {
-			emoji = "📄"
-		}
-
-		sb.WriteString(fmt.Sprintf("**%s [%s](%s)** (%d commits)\n",
-			emoji, rc.Repo.Name, rc.Repo.HTMLURL, rc.Compare.TotalCommits))
-		sb.WriteString(fmt.Sprintf("%s\n\n", rc.Summary))
-	}
-
-	sb.WriteString(fmt.Sprintf("_Requested by @%s_", userName))
-	mmBot.PostMessageInThread(ctx, channelID, threadID, sb.String())
-
-	if releaseManager != nil {
-		// Best-effort refresh: if this channel has an active release, update it with current data.
-		// Errors are intentionally ignored since the changes command already succeeded.
-		_, _ = releaseManager.RefreshRelease(ctx, channelID)
-	}
-}
-
-func processReleasePRsAsync(ghClient *github.Client, org string, ignoredRepos map[string]struct{}, mmBot *mattermost.Bot, channelID, threadID, userName, sourceBranch, destBranch string) {
-	ctx := context.Background()
-
-	repos, err := ghClient.ListRepositories(ctx, org)
-	if err != nil {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("@%s ❌ Failed to fetch repositories: %v\n\n_Requested by @%s_", userName, err, userName))
-		return
-	}
-
-	var filteredRepos []github.Repository
-	for _, repo := range repos {
-		if repo.Archived {
-			continue
-		}
-		if _, ignored := ignoredRepos[repo.Name]; ignored {
-			continue
-		}
-		filteredRepos = append(filteredRepos, repo)
-	}
-
-	type repoStatus struct {
-		Repo     github.Repository
-		Commits  int
-		PR       *github.PullRequest
-		HasError bool
-	}
-
-	var (
-		results []repoStatus
-		mu      sync.Mutex
-		wg      sync.WaitGroup
-		sem     = make(chan struct{}, 4)
-	)
-
-	for _, repo := range filteredRepos {
-		wg.Add(1)
-		go func(repo github.Repository) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			compare, err := ghClient.CompareBranches(ctx, org, repo.Name, destBranch, sourceBranch)
-			if err != nil || compare == nil || compare.TotalCommits == 0 || len(compare.Files) == 0 {
+			compare, err := ghClient.CompareBranches(ctx, org, repo.Name, destBranch, repo.Ref)
+			if err != nil {
+				pp.Failed()
 				return
 			}
-
-			pr, _ := ghClient.FindPullRequest(ctx, org, repo.Name, sourceBranch, destBranch)
-
-			mu.Lock()
-			results = append(results, repoStatus{
-				Repo:    repo,
-				Commits: compare.TotalCommits,
-				PR:      pr,
-			})
-			mu.Unlock()
-		}(repo)
-	}
-
-	wg.Wait()
-
-	if len(results) == 0 {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("@%s ✅ No pending changes between `%s` and `%s`\n\n_Requested by @%s_", userName, sourceBranch, destBranch, userName))
-		return
-	}
-
-	var withPR, withoutPR []repoStatus
-	for _, r := range results {
-		if r.PR != nil {
-			withPR = append(withPR, r)
-		} else {
-			withoutPR = append(withoutPR, r)
-		}
-	}
-
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("### 🚀 Release PRs: `%s` → `%s`\n\n", sourceBranch, destBranch))
-
-	if len(withoutPR) > 0 {
-		sb.WriteString(fmt.Sprintf("**⚠️ Missing Release PRs (%d)**\n", len(withoutPR)))
-		for _, r := range withoutPR {
-			sb.WriteString(fmt.Sprintf("- [%s](%s) - %d commits, no PR\n", r.Repo.Name, r.Repo.HTMLURL, r.Commits))
-		}
-		sb.WriteString("\n")
-	}
-
-	if len(withPR) > 0 {
-		sb.WriteString(fmt.Sprintf("**✅ Open Release PRs (%d)**\n", len(withPR)))
-		for _, r := range withPR {
-			sb.WriteString(fmt.Sprintf("- [%s#%d](%s) - %d commits\n", r.Repo.Name, r.PR.Number, r.PR.HTMLURL, r.Commits))
-		}
-	}
-
-	sb.WriteString(fmt.Sprintf("\n_Requested by @%s_", userName))
-	mmBot.PostMessageInThread(ctx, channelID, threadID, sb.String())
-}
-
-func processCreateReleaseAsync(dashboardSvc *dashboard.Service, ghClient *github.Client, org string, ignoredRepos map[string]struct{}, mmBot *mattermost.Bot, baseURL, channelID, threadID, userName, sourceBranch, destBranch string) {
-	ctx := context.Background()
-	log := logger.Get()
-
-	log.Info().
-		Str("user", userName).
-		Str("source", sourceBranch).
-		Str("dest", destBranch).
-		Str("channel", channelID).
-		Msg("Creating release")
-
-	ownerUser, err := mmBot.GetUserByUsername(ctx, userName)
-	if err != nil || ownerUser == nil {
-		log.Error().Err(err).Str("user", userName).Msg("Failed to find user")
-		mmBot.PostMessageInThread(ctx, channelID, threadID, "Failed to find user @"+userName+"\n\n_Requested by @"+userName+"_")
-		return
-	}
-
-	rel, err := dashboardSvc.CreateRelease(ctx, dashboard.CreateReleaseRequest{
-		SourceBranch: sourceBranch,
-		DestBranch:   destBranch,
-		CreatedBy:    ownerUser.ID,
-		ChannelID:    channelID,
-	})
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to create release")
-		mmBot.PostMessageInThread(ctx, channelID, threadID, "Failed to create release: "+err.Error()+"\n\n_Requested by @"+userName+"_")
-		return
-	}
-
-	log.Info().Str("release_id", rel.ID).Msg("Release created, gathering repo data")
-
-	repos, err := gatherRepoData(ctx, ghClient, org, ignoredRepos, sourceBranch, destBranch)
-	if err != nil {
-		log.Error().Err(err).Str("release_id", rel.ID).Msg("Failed to gather repos")
-		mmBot.PostMessageInThread(ctx, channelID, threadID, "Failed to gather repos: "+err.Error()+"\n\n_Requested by @"+userName+"_")
-		return
-	}
-
-	log.Info().Str("release_id", rel.ID).Int("repo_count", len(repos)).Msg("Repos gathered, saving to database")
-
-	if err := dashboardSvc.AddRepos(ctx, rel.ID, repos); err != nil {
-		log.Error().Err(err).Str("release_id", rel.ID).Msg("Failed to add repos")
-		mmBot.PostMessageInThread(ctx, channelID, threadID, "Failed to add repos: "+err.Error()+"\n\n_Requested by @"+userName+"_")
-		return
-	}
-
-	releaseURL := baseURL + "/releases/" + rel.ID
-	message := "## Release: `" + sourceBranch + "` → `" + destBranch + "`\n**Repositories:** " +
-		strconv.Itoa(len(repos)) + "\n[View Dashboard](" + releaseURL + ")\n\n_Requested by @" + userName + "_"
-
-	log.Info().Str("release_id", rel.ID).Str("url", releaseURL).Msg("Posting release message")
-
-	if err := mmBot.PostMessageInThread(ctx, channelID, threadID, message); err != nil {
-		log.Error().Err(err).Msg("Failed to post message")
-	}
-
-	dashboardSvc.SetMattermostPostID(ctx, rel.ID, threadID)
-	log.Info().Str("release_id", rel.ID).Msg("Release creation complete")
-}
-
-func gatherRepoData(ctx context.Context, ghClient *github.Client, org string, ignoredRepos map[string]struct{}, sourceBranch, destBranch string) ([]dashboard.RepoData, error) {
-	repos, err := ghClient.ListRepositories(ctx, org)
-	if err != nil {
-		return nil, err
-	}
-
-	var results []dashboard.RepoData
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, 4)
-
-	for _, repo := range repos {
-		if repo.Archived {
-			continue
-		}
-		if _, ignored := ignoredRepos[repo.Name]; ignored {
-			continue
-		}
-
-		wg.Add(1)
-		go func(repo github.Repository) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			compare, err := ghClient.CompareBranches(ctx, org, repo.Name, destBranch, sourceBranch)
-			if err != nil || compare == nil || compare.TotalCommits == 0 || len(compare.Files) == 0 {
+			if compare == nil || compare.TotalCommits == 0 || len(compare.Files) == 0 {
+				pp.Scanned(false)
 				return
 			}
+			pp.Scanned(true)
 
 			var contributors []string
 			seen := make(map[string]struct{})
@@ -1332,9 +818,10 @@ func gatherRepoData(ctx context.Context, ghClient *github.Client, org string, ig
 				deletions += f.Deletions
 			}
 
-			pr, _ := ghClient.FindPullRequest(ctx, org, repo.Name, sourceBranch, destBranch)
+			pr, _ := ghClient.FindPullRequest(ctx, org, repo.Name, repo.Ref, destBranch)
 
-			summary, isBreaking := generateChangeSummary(repo.Name, compare)
+			summary, err := generateChangeSummary(ctx, sum, repo.Name, compare)
+			pp.Summarized(err == nil)
 
 			var mergeCommitSHA string
 			if pr != nil && pr.Merged && pr.MergeCommitSHA != "" {
@@ -1352,11 +839,11 @@ func gatherRepoData(ctx context.Context, ghClient *github.Client, org string, ig
 				Additions:      additions,
 				Deletions:      deletions,
 				Contributors:   contributors,
-				Summary:        summary,
-				IsBreaking:     isBreaking,
 				MergeCommitSHA: mergeCommitSHA,
 				HeadSHA:        headSHA,
+				SourceRef:      repo.Ref,
 			}
+			data.SetSummary(summary)
 			if pr != nil {
 				data.PRNumber = pr.Number
 				data.PRURL = pr.HTMLURL
@@ -1369,75 +856,26 @@ func gatherRepoData(ctx context.Context, ghClient *github.Client, org string, ig
 	}
 
 	wg.Wait()
-	return results, nil
-}
-
-func processRefreshReleaseAsync(releaseManager *release.Manager, mmBot *mattermost.Bot, channelID, threadID, userName string) {
-	ctx := context.Background()
-
-	_, err := releaseManager.RefreshRelease(ctx, channelID)
-	if err != nil {
-		mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("Failed to refresh: %v\n\n_Requested by @%s_", err, userName))
-		return
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-
-	mmBot.PostMessageInThread(ctx, channelID, threadID, fmt.Sprintf("✅ Release summary updated.\n\n_Requested by @%s_", userName))
+	return results, nil
 }
 
-func generateChangeSummary(repoName string, compare *github.CompareResult) (string, bool) {
+// generateChangeSummary summarizes the changes with sum. On error the
+// returned summary is a plain commit count that can be shown instead.
+func generateChangeSummary(ctx context.Context, sum summarizer.Summarizer, repoName string, compare *github.CompareResult) (summarizer.Result, error) {
 	log := logger.Get()
-	log.Debug().Str("repo", repoName).Int("commits", compare.TotalCommits).Msg("Generating AI summary")
-
-	var commitInfo strings.Builder
-	commitInfo.WriteString(fmt.Sprintf("Repository: %s\n", repoName))
-	commitInfo.WriteString(fmt.Sprintf("Total commits: %d\n\n", compare.TotalCommits))
+	log.Debug().Str("repo", repoName).Int("commits", compare.TotalCommits).Msg("Generating summary")
 
-	commitInfo.WriteString("Commits:\n")
-	for _, c := range compare.Commits {
-		msg := strings.Split(c.Commit.Message, "\n")[0]
-		commitInfo.WriteString(fmt.Sprintf("- %s: %s\n", c.SHA[:7], msg))
-	}
-
-	commitInfo.WriteString("\nFiles changed:\n")
-	for _, f := range compare.Files {
-		commitInfo.WriteString(fmt.Sprintf("- %s (%s, +%d/-%d)\n", f.Filename, f.Status, f.Additions, f.Deletions))
-	}
-
-	prompt := fmt.Sprintf(`Analyze these git changes and provide a brief summary (2-3 sentences max).
-Focus on: what features/fixes are included, any breaking changes or important notes.
-If there are breaking changes, database migrations, API changes, or security updates, start your response with "BREAKING:" followed by the summary.
-Otherwise just provide the summary directly.
-
-%s`, commitInfo.String())
-
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "claude", "-p", prompt)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			log.Warn().Str("repo", repoName).Msg("AI summary timed out")
-		} else {
-			log.Warn().Str("repo", repoName).Err(err).Msg("AI summary failed")
-		}
-		return fmt.Sprintf("%d commits (AI summary unavailable)", compare.TotalCommits), false
-	}
-
-	summary := strings.TrimSpace(stdout.String())
-	isBreaking := strings.HasPrefix(strings.ToUpper(summary), "BREAKING:")
-
-	if isBreaking {
-		summary = strings.TrimPrefix(summary, "BREAKING:")
-		summary = strings.TrimPrefix(summary, "breaking:")
-		summary = strings.TrimSpace(summary)
+	result, err := summarizer.Summarize(ctx, sum, summarizer.Input{Repo: repoName, Compare: compare})
+	if err != nil {
+		log.Warn().Str("repo", repoName).Err(err).Msg("Summary failed")
+		return result, err
 	}
 
-	log.Debug().Str("repo", repoName).Bool("breaking", isBreaking).Msg("AI summary complete")
-	return summary, isBreaking
+	log.Debug().Str("repo", repoName).Bool("breaking", result.IsBreaking).Str("risk", result.RiskLevel).Msg("Summary complete")
+	return result, nil
 }
 
 func respondError(w http.ResponseWriter, msg string) {
@@ -8,15 +8,59 @@ import (
 )
 
 type Config struct {
-	GitHubToken string      `yaml:"github_token"`
-	Org         string      `yaml:"org"`
-	IgnoreRepos []string    `yaml:"ignore_repos"`
-	PRs         PRsConfig   `yaml:"prs"`
-	Serve       ServeConfig `yaml:"serve"`
+	GitHubToken string           `yaml:"github_token"`
+	Org         string           `yaml:"org"`
+	IgnoreRepos []string         `yaml:"ignore_repos"`
+	PRs         PRsConfig        `yaml:"prs"`
+	Serve       ServeConfig      `yaml:"serve"`
+	Summarizer  SummarizerConfig `yaml:"summarizer"`
+}
+
+// SummarizerConfig selects how change summaries are produced.
+type SummarizerConfig struct {
+	// Type is "cli" (the default), "openai", "rules" or "none".
+	Type string `yaml:"type"`
+	// Fallback is a Type used when the primary summarizer fails.
+	Fallback string        `yaml:"fallback"`
+	Timeout  time.Duration `yaml:"timeout"`
+	// CacheTTL is how long summaries stored in the SQLite database are
+	// reused; defaults to 7 days.
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// DiffTokens budgets the patch content sent with each summary, riskiest
+	// files first; defaults to 6000, and a negative value sends none.
+	DiffTokens int                 `yaml:"diff_tokens"`
+	CLI        SummarizerCLIConfig `yaml:"cli"`
+	OpenAI     OpenAIConfig        `yaml:"openai"`
+	// PRSummaryBots are the GitHub logins whose PR comments summarize-pr
+	// shows; defaults to gemini-code-assist[bot]. PRs without such a comment
+	// are summarized by the summarizer.
+	PRSummaryBots []string `yaml:"pr_summary_bots"`
+}
+
+// DefaultPRSummaryBots is used when PRSummaryBots is not set.
+var DefaultPRSummaryBots = []string{"gemini-code-assist[bot]"}
+
+// SummarizerCLIConfig runs Command with Args and the prompt as the last
+// argument; defaults to "claude -p".
+type SummarizerCLIConfig struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+}
+
+// OpenAIConfig points at an OpenAI-compatible chat completions API.
+type OpenAIConfig struct {
+	// BaseURL includes the API version, e.g. "http://localhost:11434/v1".
+	BaseURL string `yaml:"base_url"`
+	APIKey  string `yaml:"api_key"`
+	Model   string `yaml:"model"`
 }
 
 type PRsConfig struct {
-	WebhookURL string `yaml:"webhook_url"`
+	WebhookURL  string   `yaml:"webhook_url"`
+	Channels    []string `yaml:"channels"`
+	Username    string   `yaml:"username"`
+	IconURL     string   `yaml:"icon_url"`
+	Attachments bool     `yaml:"attachments"`
 }
 
 type ServeConfig struct {
@@ -25,17 +69,97 @@ type ServeConfig struct {
 	MattermostToken    string              `yaml:"mattermost_token"`
 	AllowedTokens      []string            `yaml:"allowed_tokens"`
 	CommandPermissions map[string][]string `yaml:"command_permissions"`
+	Permissions        PermissionsConfig   `yaml:"permissions"`
+	GitHubLink         GitHubLinkConfig    `yaml:"github_link"`
+	Scheduler          SchedulerConfig     `yaml:"scheduler"`
+	JobWorkers         int                 `yaml:"job_workers"`
 	Release            ReleaseConfig       `yaml:"release"`
+	Freeze             FreezeConfig        `yaml:"freeze"`
 	Dashboard          DashboardConfig     `yaml:"dashboard"`
 }
 
+type PermissionsConfig struct {
+	// Default is "allow" (the default) or "deny" for actions without rules.
+	Default  string                    `yaml:"default"`
+	CacheTTL time.Duration             `yaml:"cache_ttl"`
+	TeamID   string                    `yaml:"team_id"`
+	Rules    map[string]PermissionRule `yaml:"rules"`
+}
+
+// PermissionRule entries are subjects: "alice" or "user:alice",
+// "group:devops", "role:system_admin", "role:team_admin",
+// "role:channel_admin", or "*" for everyone.
+type PermissionRule struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+type GitHubLinkConfig struct {
+	// Verify is "none" (the default) or "gist" to require a public gist
+	// carrying a one-time token before a login is linked.
+	Verify       string        `yaml:"verify"`
+	ChallengeTTL time.Duration `yaml:"challenge_ttl"`
+}
+
+type SchedulerConfig struct {
+	// Timezone is an IANA name such as "Europe/Kyiv"; defaults to the
+	// server's local time zone.
+	Timezone string               `yaml:"timezone"`
+	Jobs     []ScheduledJobConfig `yaml:"jobs"`
+}
+
+// ScheduledJobConfig is a built-in job run on a five-field cron schedule.
+// Type is "pr_reminder", "poke_releases" or "changes_digest".
+type ScheduledJobConfig struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Schedule string `yaml:"schedule"`
+	// Channels are webhook channels for pr_reminder; defaults to prs.channels.
+	Channels []string `yaml:"channels"`
+	// ChannelID, Source and Dest configure changes_digest.
+	ChannelID string `yaml:"channel_id"`
+	Source    string `yaml:"source"`
+	Dest      string `yaml:"dest"`
+}
+
 type ReleaseConfig struct {
 	TeamID           string   `yaml:"team_id"`
 	PlaybookID       string   `yaml:"playbook_id"`
 	DefaultReviewers []string `yaml:"default_reviewers"`
 	DefaultQA        []string `yaml:"default_qa"`
+	DedicatedChannel bool     `yaml:"dedicated_channel"`
+	PrivateChannel   bool     `yaml:"private_channel"`
+	ChannelPrefix    string   `yaml:"channel_prefix"`
+	HotfixApprovals  []string `yaml:"hotfix_approvals"`
+	// NotesTemplates is a directory whose notes.md.tmpl and notes.html.tmpl
+	// replace the built-in release notes templates.
+	NotesTemplates string `yaml:"notes_templates"`
 }
 
+// FreezeConfig defines deployment freeze windows. During a window releases
+// cannot be created or approved, and their PRs cannot be merged, unless a
+// user with the freeze-override permission overrides it.
+type FreezeConfig struct {
+	// Timezone is an IANA name for the window times; defaults to the
+	// server's local time zone.
+	Timezone string               `yaml:"timezone"`
+	Windows  []FreezeWindowConfig `yaml:"windows"`
+	// CommitStatus sets a "deployment-freeze" commit status on open release
+	// PRs so branch protection can block merges.
+	CommitStatus bool `yaml:"commit_status"`
+}
+
+// FreezeWindowConfig is a freeze from Start to End, both "2006-01-02 15:04".
+type FreezeWindowConfig struct {
+	Start  string `yaml:"start"`
+	End    string `yaml:"end"`
+	Reason string `yaml:"reason"`
+	Owner  string `yaml:"owner"`
+}
+
+// DefaultSQLitePath is used when serve.dashboard.sqlite_path is not set.
+const DefaultSQLitePath = "./releases.db"
+
 type DashboardConfig struct {
 	Enabled    bool           `yaml:"enabled"`
 	BaseURL    string         `yaml:"base_url"`
@@ -45,10 +169,13 @@ type DashboardConfig struct {
 }
 
 type ArgoCDConfig struct {
-	PollInterval time.Duration            `yaml:"poll_interval"`
-	CacheTTL     time.Duration            `yaml:"cache_ttl"`
+	PollInterval time.Duration              `yaml:"poll_interval"`
+	CacheTTL     time.Duration              `yaml:"cache_ttl"`
 	Environments map[string]ArgoCDEnvConfig `yaml:"environments"`
-	Overrides    map[string]string        `yaml:"overrides"`
+	Overrides    map[string]string          `yaml:"overrides"`
+	// TargetEnvironments maps a release's destination branch to the
+	// environment it deploys to, e.g. master: prod.
+	TargetEnvironments map[string]string `yaml:"target_environments"`
 }
 
 type ArgoCDEnvConfig struct {
@@ -2,6 +2,9 @@ package dashboard
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +19,10 @@ type deploymentCache struct {
 	fetchedAt time.Time
 }
 
+// ReleaseDeployedCallback runs after a release was completed because its
+// repos are deployed in env.
+type ReleaseDeployedCallback func(ctx context.Context, release *database.Release, env string)
+
 type ArgoCDTracker struct {
 	service   *Service
 	clients   map[string]*argocd.Client
@@ -28,9 +35,20 @@ type ArgoCDTracker struct {
 	fetchMu   sync.Mutex
 	stopCh    chan struct{}
 	wg        sync.WaitGroup
+
+	onReleaseDeployed ReleaseDeployedCallback
 }
 
 func NewArgoCDTracker(service *Service, cfg *config.ArgoCDConfig) *ArgoCDTracker {
+	clients := make(map[string]*argocd.Client)
+	for envName, envCfg := range cfg.Environments {
+		clients[envName] = argocd.NewClient(envCfg.URL, envCfg.CFClientID, envCfg.CFClientSecret)
+	}
+
+	return NewArgoCDTrackerWithClients(service, cfg, clients)
+}
+
+func NewArgoCDTrackerWithClients(service *Service, cfg *config.ArgoCDConfig, clients map[string]*argocd.Client) *ArgoCDTracker {
 	interval := cfg.PollInterval
 	if interval == 0 {
 		interval = 30 * time.Second
@@ -41,11 +59,6 @@ func NewArgoCDTracker(service *Service, cfg *config.ArgoCDConfig) *ArgoCDTracker
 		cacheTTL = 10 * time.Second
 	}
 
-	clients := make(map[string]*argocd.Client)
-	for envName, envCfg := range cfg.Environments {
-		clients[envName] = argocd.NewClient(envCfg.URL, envCfg.CFClientID, envCfg.CFClientSecret)
-	}
-
 	return &ArgoCDTracker{
 		service:   service,
 		clients:   clients,
@@ -68,6 +81,12 @@ func (t *ArgoCDTracker) Stop() {
 	t.wg.Wait()
 }
 
+// OnReleaseDeployed registers fn to run when the tracker moves a release to
+// deployed.
+func (t *ArgoCDTracker) OnReleaseDeployed(fn ReleaseDeployedCallback) {
+	t.onReleaseDeployed = fn
+}
+
 func (t *ArgoCDTracker) run() {
 	defer t.wg.Done()
 	log := logger.Get()
@@ -115,6 +134,99 @@ func (t *ArgoCDTracker) checkPendingDeployments() {
 		delete(t.cache, releaseID)
 	}
 	t.cacheMu.Unlock()
+
+	for releaseID := range updatedReleases {
+		if _, err := t.CompleteRelease(ctx, releaseID); err != nil {
+			log.Error().Err(err).Str("release_id", releaseID).Msg("Failed to complete deployed release")
+		}
+	}
+}
+
+// TargetEnvironment returns the environment a release to destBranch deploys
+// to: the one mapped in target_environments, else the environment named like
+// the branch, else the only environment. It is empty when none applies.
+func (t *ArgoCDTracker) TargetEnvironment(destBranch string) string {
+	if env, ok := t.config.TargetEnvironments[destBranch]; ok {
+		return env
+	}
+	if _, ok := t.clients[destBranch]; ok {
+		return destBranch
+	}
+	if len(t.clients) == 1 {
+		for env := range t.clients {
+			return env
+		}
+	}
+	return ""
+}
+
+// CompleteRelease moves an approved or deploying release to deployed once
+// every included repo is healthy at its expected version in the release's
+// target environment, and reports whether it did. Deployed releases are no
+// longer polled.
+func (t *ArgoCDTracker) CompleteRelease(ctx context.Context, releaseID string) (bool, error) {
+	release, err := t.service.GetRelease(ctx, releaseID)
+	if err != nil {
+		return false, err
+	}
+	if release.Status != StatusApproved && release.Status != StatusDeploying {
+		return false, nil
+	}
+	env := t.TargetEnvironment(release.DestBranch)
+	if env == "" {
+		return false, nil
+	}
+
+	repos, err := t.service.GetReposByReleaseID(ctx, releaseID)
+	if err != nil {
+		return false, err
+	}
+	statuses, err := t.service.GetDeploymentStatusesForRelease(ctx, releaseID)
+	if err != nil {
+		return false, err
+	}
+	if !allReposDeployed(repos, statuses, env) {
+		return false, nil
+	}
+
+	details := map[string]any{"environment": env, "via": "argocd"}
+	if release.Status == StatusApproved {
+		if _, err := t.service.ObserveTransition(ctx, releaseID, StatusDeploying, "system", details); err != nil {
+			return false, err
+		}
+	}
+	release, err = t.service.ObserveTransition(ctx, releaseID, StatusDeployed, "system", details)
+	if err != nil {
+		return false, err
+	}
+	t.InvalidateCache(releaseID)
+
+	logger.Info().Str("release_id", releaseID).Str("env", env).Msg("Release deployed")
+	if t.onReleaseDeployed != nil {
+		t.onReleaseDeployed(ctx, release, env)
+	}
+	return true, nil
+}
+
+func allReposDeployed(repos []database.ReleaseRepo, statuses []database.RepoDeploymentStatus, env string) bool {
+	deployed := make(map[uint]bool)
+	for _, s := range statuses {
+		if s.Environment == env && s.RolloutStatus == "deployed" && s.CurrentVersion == s.ExpectedVersion {
+			deployed[s.ReleaseRepoID] = true
+		}
+	}
+
+	var included int
+	for _, r := range repos {
+		if r.Excluded {
+			continue
+		}
+		included++
+		if !deployed[r.ID] {
+			return false
+		}
+	}
+	return included > 0
 }
 
 func (t *ArgoCDTracker) getReposWithSuccessfulCI(ctx context.Context) ([]database.ReleaseRepo, error) {
@@ -201,6 +313,86 @@ func (t *ArgoCDTracker) resolveAppName(repoName, env string) string {
 	return repoName
 }
 
+type LiveAppStatus struct {
+	Environment    string
+	AppName        string
+	Found          bool
+	SyncStatus     string
+	HealthStatus   string
+	CurrentVersion string
+	Error          string
+}
+
+// Environments returns the configured ArgoCD environment names, sorted.
+func (t *ArgoCDTracker) Environments() []string {
+	envs := make([]string, 0, len(t.clients))
+	for env := range t.clients {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+	return envs
+}
+
+// lookupEnvironment returns the configured environment key matching name,
+// preferring an exact match over a case-insensitive one.
+func (t *ArgoCDTracker) lookupEnvironment(name string) (string, bool) {
+	if _, ok := t.clients[name]; ok {
+		return name, true
+	}
+	for _, env := range t.Environments() {
+		if strings.EqualFold(env, name) {
+			return env, true
+		}
+	}
+	return "", false
+}
+
+// QueryLiveStatus asks ArgoCD directly for the repo's application in each
+// environment, bypassing the release-scoped deployment status table. With no
+// envs given every configured environment is queried. Environment names match
+// the configured keys case-insensitively.
+func (t *ArgoCDTracker) QueryLiveStatus(ctx context.Context, repoName string, envs ...string) ([]LiveAppStatus, error) {
+	if len(envs) == 0 {
+		envs = t.Environments()
+	}
+	resolved := make([]string, len(envs))
+	for i, name := range envs {
+		env, ok := t.lookupEnvironment(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown environment %q (configured: %s)", name, strings.Join(t.Environments(), ", "))
+		}
+		resolved[i] = env
+	}
+	envs = resolved
+
+	results := make([]LiveAppStatus, len(envs))
+	var wg sync.WaitGroup
+	for i, env := range envs {
+		wg.Add(1)
+		go func(i int, env string) {
+			defer wg.Done()
+
+			appName := t.resolveAppName(repoName, env)
+			result := LiveAppStatus{Environment: env, AppName: appName}
+
+			appStatus, err := t.clients[env].GetApplication(ctx, appName)
+			switch {
+			case err != nil:
+				result.Error = err.Error()
+			case appStatus != nil:
+				result.Found = true
+				result.SyncStatus = appStatus.SyncStatus
+				result.HealthStatus = appStatus.HealthStatus
+				result.CurrentVersion = appStatus.CurrentVersion
+			}
+			results[i] = result
+		}(i, env)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
 func (t *ArgoCDTracker) determineRolloutStatus(appStatus *argocd.AppStatus, expectedVersion string) string {
 	if appStatus.CurrentVersion != expectedVersion {
 		return "pending"
@@ -0,0 +1,217 @@
+package dashboard_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/pkg/argocd"
+	"github.com/user/mattermost-tools/pkg/argocd/mocks"
+)
+
+func TestArgoCDTracker_QueryLiveStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uatHTTP := mocks.NewMockHTTPDoer(ctrl)
+	uatHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "/api/v1/applications/auth-service-uat", req.URL.Path)
+			return &http.Response{
+				StatusCode: 200,
+				Body: io.NopCloser(strings.NewReader(`{"metadata":{"name":"auth-service-uat"},
+					"status":{"sync":{"status":"Synced"},"health":{"status":"Healthy"}},
+					"spec":{"source":{"targetRevision":"1.4.2"}}}`)),
+			}, nil
+		})
+
+	prodHTTP := mocks.NewMockHTTPDoer(ctrl)
+	prodHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "/api/v1/applications/auth-custom", req.URL.Path)
+			return &http.Response{
+				StatusCode: 404,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		})
+
+	cfg := &config.ArgoCDConfig{
+		Environments: map[string]config.ArgoCDEnvConfig{
+			"uat":  {AppSuffix: "-uat"},
+			"prod": {AppSuffix: "-master"},
+		},
+		Overrides: map[string]string{"auth-service-prod": "auth-custom"},
+	}
+	tracker := dashboard.NewArgoCDTrackerWithClients(nil, cfg, map[string]*argocd.Client{
+		"uat":  argocd.NewClientWithHTTP("https://argocd-uat.example.com", "", "", uatHTTP),
+		"prod": argocd.NewClientWithHTTP("https://argocd.example.com", "", "", prodHTTP),
+	})
+
+	statuses, err := tracker.QueryLiveStatus(context.Background(), "auth-service")
+
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	require.Equal(t, "prod", statuses[0].Environment)
+	require.False(t, statuses[0].Found)
+	require.Equal(t, "auth-custom", statuses[0].AppName)
+	require.Equal(t, "uat", statuses[1].Environment)
+	require.True(t, statuses[1].Found)
+	require.Equal(t, "1.4.2", statuses[1].CurrentVersion)
+	require.Equal(t, "Healthy", statuses[1].HealthStatus)
+}
+
+func TestArgoCDTracker_QueryLiveStatus_UnknownEnv(t *testing.T) {
+	tracker := dashboard.NewArgoCDTrackerWithClients(nil, &config.ArgoCDConfig{}, map[string]*argocd.Client{})
+
+	_, err := tracker.QueryLiveStatus(context.Background(), "auth-service", "staging")
+
+	require.ErrorContains(t, err, "unknown environment")
+}
+
+func TestArgoCDTracker_QueryLiveStatus_EnvironmentCase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uatHTTP := mocks.NewMockHTTPDoer(ctrl)
+	uatHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "/api/v1/applications/auth-service-uat", req.URL.Path)
+			return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+
+	cfg := &config.ArgoCDConfig{
+		Environments: map[string]config.ArgoCDEnvConfig{"UAT": {AppSuffix: "-uat"}},
+	}
+	tracker := dashboard.NewArgoCDTrackerWithClients(nil, cfg, map[string]*argocd.Client{
+		"UAT": argocd.NewClientWithHTTP("https://argocd-uat.example.com", "", "", uatHTTP),
+	})
+
+	statuses, err := tracker.QueryLiveStatus(context.Background(), "auth-service", "uat")
+
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, "UAT", statuses[0].Environment)
+	require.Equal(t, "auth-service-uat", statuses[0].AppName)
+}
+
+func TestArgoCDTracker_TargetEnvironment(t *testing.T) {
+	cfg := &config.ArgoCDConfig{TargetEnvironments: map[string]string{"master": "prod"}}
+	clients := map[string]*argocd.Client{"uat": nil, "prod": nil}
+	tracker := dashboard.NewArgoCDTrackerWithClients(nil, cfg, clients)
+
+	require.Equal(t, "prod", tracker.TargetEnvironment("master"))
+	require.Equal(t, "uat", tracker.TargetEnvironment("uat"))
+	require.Equal(t, "", tracker.TargetEnvironment("develop"))
+
+	single := dashboard.NewArgoCDTrackerWithClients(nil, &config.ArgoCDConfig{}, map[string]*argocd.Client{"prod": nil})
+	require.Equal(t, "prod", single.TargetEnvironment("master"))
+}
+
+func TestArgoCDTracker_CompleteRelease(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&database.RepoCIStatus{}, &database.RepoDeploymentStatus{}))
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release := approvedRelease(t, svc)
+	require.NoError(t, svc.AddRepos(ctx, release.ID, []dashboard.RepoData{{RepoName: "auth-service"}, {RepoName: "billing"}, {RepoName: "legacy"}}))
+	repos, err := svc.GetReposByReleaseID(ctx, release.ID)
+	require.NoError(t, err)
+	byName := make(map[string]uint)
+	for _, r := range repos {
+		byName[r.RepoName] = r.ID
+		require.NoError(t, svc.CreateOrUpdateCIStatus(ctx, &database.RepoCIStatus{ReleaseRepoID: r.ID, Status: "success", ChartVersion: "1.0.0"}))
+	}
+	excluded := true
+	require.NoError(t, svc.UpdateRepo(ctx, byName["legacy"], &excluded, nil))
+
+	deploy := func(repo, env, rollout string) {
+		require.NoError(t, svc.CreateOrUpdateDeploymentStatus(ctx, &database.RepoDeploymentStatus{
+			ReleaseRepoID:   byName[repo],
+			Environment:     env,
+			ExpectedVersion: "1.0.0",
+			CurrentVersion:  "1.0.0",
+			RolloutStatus:   rollout,
+		}))
+	}
+	deploy("auth-service", "prod", "deployed")
+	deploy("billing", "prod", "unhealthy")
+	deploy("billing", "uat", "deployed")
+
+	cfg := &config.ArgoCDConfig{TargetEnvironments: map[string]string{"master": "prod"}}
+	tracker := dashboard.NewArgoCDTrackerWithClients(svc, cfg, map[string]*argocd.Client{"uat": nil, "prod": nil})
+	var notified []string
+	tracker.OnReleaseDeployed(func(_ context.Context, rel *database.Release, env string) {
+		notified = append(notified, rel.Status+"@"+env)
+	})
+
+	done, err := tracker.CompleteRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.False(t, done)
+
+	tracked, err := svc.GetReposWithSuccessfulCI(ctx)
+	require.NoError(t, err)
+	require.Len(t, tracked, 2)
+
+	deploy("billing", "prod", "deployed")
+	done, err = tracker.CompleteRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.True(t, done)
+	require.Equal(t, []string{"deployed@prod"}, notified)
+
+	updated, err := svc.GetRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.Equal(t, dashboard.StatusDeployed, updated.Status)
+	require.NotZero(t, updated.DeployedAt)
+
+	tracked, err = svc.GetReposWithSuccessfulCI(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tracked)
+
+	done, err = tracker.CompleteRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.False(t, done)
+}
+
+func TestArgoCDTracker_CompleteRelease_DuringFreeze(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&database.RepoCIStatus{}, &database.RepoDeploymentStatus{}))
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release := approvedRelease(t, svc)
+	require.NoError(t, svc.AddRepos(ctx, release.ID, []dashboard.RepoData{{RepoName: "auth-service"}}))
+	repos, err := svc.GetReposByReleaseID(ctx, release.ID)
+	require.NoError(t, err)
+	require.NoError(t, svc.CreateOrUpdateDeploymentStatus(ctx, &database.RepoDeploymentStatus{
+		ReleaseRepoID:   repos[0].ID,
+		Environment:     "prod",
+		ExpectedVersion: "1.0.0",
+		CurrentVersion:  "1.0.0",
+		RolloutStatus:   "deployed",
+	}))
+	activeFreeze(t, svc)
+
+	_, err = svc.TransitionRelease(ctx, release.ID, dashboard.StatusDeploying, "deployer", nil)
+	require.ErrorIs(t, err, dashboard.ErrFrozen)
+
+	tracker := dashboard.NewArgoCDTrackerWithClients(svc, &config.ArgoCDConfig{}, map[string]*argocd.Client{"prod": nil})
+	done, err := tracker.CompleteRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.True(t, done)
+
+	updated, err := svc.GetRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.Equal(t, dashboard.StatusDeployed, updated.Status)
+}
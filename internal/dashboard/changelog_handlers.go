@@ -0,0 +1,90 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/user/mattermost-tools/internal/changelog"
+	"github.com/user/mattermost-tools/internal/logger"
+)
+
+// GetChangelog returns the conventional-commit changelog of every repo in
+// the release, as JSON or, with format=markdown, as Markdown.
+func (h *Handlers) GetChangelog(w http.ResponseWriter, r *http.Request) {
+	releaseID := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/releases/"), "/")[0]
+
+	release, err := h.service.GetReleaseWithRepos(r.Context(), releaseID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	repos, err := h.releaseChangelog(r.Context(), release)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "markdown" {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		_, _ = w.Write([]byte(changelog.Markdown(repos)))
+		return
+	}
+	respondJSON(w, map[string]any{
+		"release_id": release.ID,
+		"repos":      repos,
+	})
+}
+
+// releaseChangelog compares each included repo of the release from its
+// source ref and builds its changelog, in the release's repo order. Repos
+// that fail to compare are logged and left out.
+func (h *Handlers) releaseChangelog(ctx context.Context, release *ReleaseWithRepos) ([]changelog.Repo, error) {
+	if h.ghClient == nil {
+		return nil, errors.New("GitHub client not configured")
+	}
+
+	results := make([]changelog.Repo, len(release.Repos))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4)
+
+	for i, repo := range release.Repos {
+		if repo.Excluded {
+			continue
+		}
+		ref := repo.SourceRef
+		if ref == "" {
+			ref = release.SourceBranch
+		}
+
+		wg.Add(1)
+		go func(i int, name, ref string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			compare, err := h.ghClient.CompareBranches(ctx, h.org, name, release.DestBranch, ref)
+			if err != nil {
+				logger.Warn().Err(err).Str("repo", name).Msg("Changelog compare failed")
+				return
+			}
+			results[i] = changelog.Build(name, compare.Commits)
+		}(i, repo.RepoName, ref)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repos := make([]changelog.Repo, 0, len(results))
+	for _, r := range results {
+		if r.Repo != "" && !r.IsEmpty() {
+			repos = append(repos, r)
+		}
+	}
+	return repos, nil
+}
@@ -0,0 +1,299 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/database"
+)
+
+// FreezeTimeLayout is the format of freeze window times in the config file
+// and in chat.
+const FreezeTimeLayout = "2006-01-02 15:04"
+
+const (
+	// FreezePermission is the permissions rule key for adding and removing
+	// freeze windows. Adding one, or removing someone else's, must be granted
+	// by an allow rule; the default policy only lets owners remove their own.
+	FreezePermission = "freeze"
+	// FreezeOverridePermission is the rule key for overriding a freeze. It
+	// must be granted by an allow rule; the default policy does not apply.
+	FreezeOverridePermission = "freeze-override"
+)
+
+const (
+	FreezeSourceConfig = "config"
+	FreezeSourceAPI    = "api"
+
+	FreezeActionApprove = "approve"
+	FreezeActionCreate  = "create"
+	FreezeActionMerge   = "merge"
+)
+
+var (
+	ErrFrozen          = errors.New("deployment freeze in effect")
+	ErrNotFrozen       = errors.New("no deployment freeze in effect")
+	ErrInvalidFreeze   = errors.New("freeze window needs a reason and an end after its start")
+	ErrFreezeNotFound  = errors.New("freeze window not found")
+	ErrConfigFreeze    = errors.New("freeze window is defined in the config file")
+	ErrAlreadyOverride = errors.New("freeze already overridden for this release")
+)
+
+// FreezeError is returned when an action is refused because of a freeze
+// window. It matches ErrFrozen with errors.Is.
+type FreezeError struct {
+	Window database.FreezeWindow
+}
+
+func (e *FreezeError) Error() string {
+	msg := fmt.Sprintf("deployment freeze until %s: %s", time.Unix(e.Window.EndsAt, 0).Format("2006-01-02 15:04 MST"), e.Window.Reason)
+	if e.Window.Owner != "" {
+		msg += " (owner @" + e.Window.Owner + ")"
+	}
+	return msg
+}
+
+func (e *FreezeError) Is(target error) bool {
+	return target == ErrFrozen
+}
+
+// ActiveFreeze returns the freeze window covering at, preferring the one that
+// ends last, or nil when there is none.
+func (s *Service) ActiveFreeze(ctx context.Context, at time.Time) (*database.FreezeWindow, error) {
+	var window database.FreezeWindow
+	err := s.db.WithContext(ctx).
+		Where("starts_at <= ? AND ends_at > ?", at.Unix(), at.Unix()).
+		Order("ends_at DESC").
+		First(&window).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding active freeze: %w", err)
+	}
+	return &window, nil
+}
+
+// ListFreezes returns the windows that have not ended by since, soonest
+// first.
+func (s *Service) ListFreezes(ctx context.Context, since time.Time) ([]database.FreezeWindow, error) {
+	var windows []database.FreezeWindow
+	err := s.db.WithContext(ctx).
+		Where("ends_at > ?", since.Unix()).
+		Order("starts_at ASC").
+		Find(&windows).Error
+	if err != nil {
+		return nil, fmt.Errorf("listing freezes: %w", err)
+	}
+	return windows, nil
+}
+
+func (s *Service) CreateFreeze(ctx context.Context, window database.FreezeWindow) (*database.FreezeWindow, error) {
+	if window.Reason == "" || window.EndsAt <= window.StartsAt {
+		return nil, ErrInvalidFreeze
+	}
+	window.ID = 0
+	window.Source = FreezeSourceAPI
+	window.CreatedAt = time.Now().Unix()
+
+	if err := s.db.WithContext(ctx).Create(&window).Error; err != nil {
+		return nil, fmt.Errorf("creating freeze: %w", err)
+	}
+	return &window, nil
+}
+
+func (s *Service) GetFreeze(ctx context.Context, id uint) (*database.FreezeWindow, error) {
+	var window database.FreezeWindow
+	err := s.db.WithContext(ctx).First(&window, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, ErrFreezeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting freeze: %w", err)
+	}
+	return &window, nil
+}
+
+func (s *Service) DeleteFreeze(ctx context.Context, id uint) error {
+	window, err := s.GetFreeze(ctx, id)
+	if err != nil {
+		return err
+	}
+	if window.Source == FreezeSourceConfig {
+		return ErrConfigFreeze
+	}
+
+	if err := s.db.WithContext(ctx).Where("freeze_id = ?", id).Delete(&database.FreezeOverride{}).Error; err != nil {
+		return fmt.Errorf("deleting freeze overrides: %w", err)
+	}
+	if err := s.db.WithContext(ctx).Delete(window).Error; err != nil {
+		return fmt.Errorf("deleting freeze: %w", err)
+	}
+	return nil
+}
+
+// SyncConfigFreezes makes the config-file windows in the database match
+// windows. Unchanged windows keep their IDs so merge overrides survive a
+// restart.
+func (s *Service) SyncConfigFreezes(ctx context.Context, windows []database.FreezeWindow) error {
+	var existing []database.FreezeWindow
+	if err := s.db.WithContext(ctx).Where("source = ?", FreezeSourceConfig).Find(&existing).Error; err != nil {
+		return fmt.Errorf("loading config freezes: %w", err)
+	}
+
+	type span struct{ start, end int64 }
+	bySpan := make(map[span]database.FreezeWindow, len(existing))
+	for _, w := range existing {
+		bySpan[span{w.StartsAt, w.EndsAt}] = w
+	}
+
+	now := time.Now().Unix()
+	for _, w := range windows {
+		if w.Reason == "" || w.EndsAt <= w.StartsAt {
+			return fmt.Errorf("%w: %q", ErrInvalidFreeze, w.Reason)
+		}
+		key := span{w.StartsAt, w.EndsAt}
+		if old, ok := bySpan[key]; ok {
+			delete(bySpan, key)
+			if err := s.db.WithContext(ctx).Model(&old).Updates(map[string]any{"reason": w.Reason, "owner": w.Owner}).Error; err != nil {
+				return fmt.Errorf("updating config freeze: %w", err)
+			}
+			continue
+		}
+		w.ID = 0
+		w.Source = FreezeSourceConfig
+		w.CreatedAt = now
+		if err := s.db.WithContext(ctx).Create(&w).Error; err != nil {
+			return fmt.Errorf("creating config freeze: %w", err)
+		}
+	}
+
+	for _, stale := range bySpan {
+		if err := s.db.WithContext(ctx).Where("freeze_id = ?", stale.ID).Delete(&database.FreezeOverride{}).Error; err != nil {
+			return fmt.Errorf("deleting freeze overrides: %w", err)
+		}
+		if err := s.db.WithContext(ctx).Delete(&stale).Error; err != nil {
+			return fmt.Errorf("deleting config freeze: %w", err)
+		}
+	}
+	return nil
+}
+
+// FreezeLocation is the time zone freeze window times are given in.
+func FreezeLocation(cfg config.FreezeConfig) (*time.Location, error) {
+	if cfg.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("loading freeze timezone: %w", err)
+	}
+	return loc, nil
+}
+
+// ConfigFreezes parses the windows of the freeze config for
+// SyncConfigFreezes.
+func ConfigFreezes(cfg config.FreezeConfig) ([]database.FreezeWindow, error) {
+	loc, err := FreezeLocation(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := make([]database.FreezeWindow, 0, len(cfg.Windows))
+	for _, w := range cfg.Windows {
+		start, err := time.ParseInLocation(FreezeTimeLayout, w.Start, loc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing freeze start %q: %w", w.Start, err)
+		}
+		end, err := time.ParseInLocation(FreezeTimeLayout, w.End, loc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing freeze end %q: %w", w.End, err)
+		}
+		windows = append(windows, database.FreezeWindow{
+			Reason:   w.Reason,
+			Owner:    w.Owner,
+			StartsAt: start.Unix(),
+			EndsAt:   end.Unix(),
+		})
+	}
+	return windows, nil
+}
+
+// checkFreeze returns a *FreezeError when a freeze is in effect, unless
+// override is set. It returns the window that was overridden, if any, so the
+// caller can record the override once the release ID is known.
+func (s *Service) checkFreeze(ctx context.Context, override bool) (*database.FreezeWindow, error) {
+	window, err := s.ActiveFreeze(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if window == nil {
+		return nil, nil
+	}
+	if !override {
+		return nil, &FreezeError{Window: *window}
+	}
+	return window, nil
+}
+
+func (s *Service) recordFreezeOverride(ctx context.Context, releaseID, action, actor string, window *database.FreezeWindow) {
+	s.RecordHistory(ctx, releaseID, "freeze_overridden", actor, map[string]any{
+		"action":    action,
+		"freeze_id": window.ID,
+		"reason":    window.Reason,
+	})
+}
+
+// OverrideFreezeForMerge allows the release's PRs to be merged during the
+// active freeze window.
+func (s *Service) OverrideFreezeForMerge(ctx context.Context, releaseID, actor string) (*database.FreezeWindow, error) {
+	window, err := s.ActiveFreeze(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if window == nil {
+		return nil, ErrNotFrozen
+	}
+
+	override := database.FreezeOverride{
+		FreezeID:  window.ID,
+		ReleaseID: releaseID,
+		Actor:     actor,
+		CreatedAt: time.Now().Unix(),
+	}
+	result := s.db.WithContext(ctx).
+		Where(database.FreezeOverride{FreezeID: window.ID, ReleaseID: releaseID}).
+		FirstOrCreate(&override)
+	if result.Error != nil {
+		return nil, fmt.Errorf("creating freeze override: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return window, ErrAlreadyOverride
+	}
+
+	s.recordFreezeOverride(ctx, releaseID, FreezeActionMerge, actor, window)
+	return window, nil
+}
+
+// MergeAllowed reports whether the release's PRs may be merged now, i.e. no
+// freeze is in effect or the active one was overridden for the release.
+func (s *Service) MergeAllowed(ctx context.Context, releaseID string) (bool, *database.FreezeWindow, error) {
+	window, err := s.ActiveFreeze(ctx, time.Now())
+	if err != nil || window == nil {
+		return err == nil, nil, err
+	}
+
+	var count int64
+	err = s.db.WithContext(ctx).Model(&database.FreezeOverride{}).
+		Where("freeze_id = ? AND release_id = ?", window.ID, releaseID).
+		Count(&count).Error
+	if err != nil {
+		return false, window, fmt.Errorf("checking freeze override: %w", err)
+	}
+	return count > 0, window, nil
+}
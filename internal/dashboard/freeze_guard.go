@@ -0,0 +1,158 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/pkg/github"
+)
+
+// FreezeStatusContext is the commit status context set on release PRs. Make
+// it a required check in branch protection to block merges during a freeze.
+const FreezeStatusContext = "deployment-freeze"
+
+// FreezeGuard keeps the deployment-freeze commit status of open release PRs
+// in line with the freeze windows and merge overrides.
+type FreezeGuard struct {
+	service  *Service
+	ghClient *github.Client
+	org      string
+	baseURL  string
+	interval time.Duration
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+
+	// posted maps "repo@sha" to the last state set, so each change is
+	// posted once.
+	mu     sync.Mutex
+	posted map[string]string
+}
+
+func NewFreezeGuard(service *Service, ghClient *github.Client, org, baseURL string, interval time.Duration) *FreezeGuard {
+	if interval == 0 {
+		interval = time.Minute
+	}
+	return &FreezeGuard{
+		service:  service,
+		ghClient: ghClient,
+		org:      org,
+		baseURL:  baseURL,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		posted:   make(map[string]string),
+	}
+}
+
+func (g *FreezeGuard) Start() {
+	g.wg.Add(1)
+	go g.run()
+}
+
+func (g *FreezeGuard) Stop() {
+	close(g.stopCh)
+	g.wg.Wait()
+}
+
+func (g *FreezeGuard) run() {
+	defer g.wg.Done()
+	log := logger.Get()
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", g.interval).Msg("Freeze guard started")
+	g.Sync(context.Background())
+
+	for {
+		select {
+		case <-g.stopCh:
+			log.Info().Msg("Freeze guard stopped")
+			return
+		case <-ticker.C:
+			g.Sync(context.Background())
+		}
+	}
+}
+
+// Sync sets the freeze status on the open PRs of every active release:
+// failure while a freeze blocks the release, success otherwise.
+func (g *FreezeGuard) Sync(ctx context.Context) {
+	log := logger.Get()
+
+	releases, err := g.service.ListReleases(ctx, "")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list releases for freeze guard")
+		return
+	}
+
+	for _, release := range releases {
+		if IsFinalStatus(release.Status) {
+			continue
+		}
+
+		allowed, window, err := g.service.MergeAllowed(ctx, release.ID)
+		if err != nil {
+			log.Error().Err(err).Str("release_id", release.ID).Msg("Failed to check freeze")
+			continue
+		}
+
+		status := github.CommitStatus{
+			State:       "success",
+			Context:     FreezeStatusContext,
+			Description: "No deployment freeze",
+			TargetURL:   fmt.Sprintf("%s/releases/%s", g.baseURL, release.ID),
+		}
+		switch {
+		case window != nil && allowed:
+			status.Description = "Deployment freeze overridden"
+		case window != nil:
+			status.State = "failure"
+			status.Description = truncateStatus((&FreezeError{Window: *window}).Error())
+		}
+
+		repos, err := g.service.GetReposByReleaseID(ctx, release.ID)
+		if err != nil {
+			log.Error().Err(err).Str("release_id", release.ID).Msg("Failed to get release repos")
+			continue
+		}
+		for _, repo := range repos {
+			if repo.PRNumber == 0 || repo.PRMerged || repo.Excluded || repo.HeadSHA == "" {
+				continue
+			}
+			g.post(ctx, repo.RepoName, repo.HeadSHA, status)
+		}
+	}
+}
+
+func (g *FreezeGuard) post(ctx context.Context, repo, sha string, status github.CommitStatus) {
+	key := repo + "@" + sha
+	g.mu.Lock()
+	unchanged := g.posted[key] == status.State+status.Description
+	g.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	if err := g.ghClient.SetCommitStatus(ctx, g.org, repo, sha, status); err != nil {
+		logger.Warn().Err(err).Str("repo", repo).Str("sha", sha).Msg("Failed to set freeze status")
+		return
+	}
+
+	g.mu.Lock()
+	g.posted[key] = status.State + status.Description
+	g.mu.Unlock()
+}
+
+// truncateStatus shortens s to the 140 characters GitHub accepts for a
+// status description.
+func truncateStatus(s string) string {
+	const max = 140
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
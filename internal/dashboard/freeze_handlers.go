@@ -0,0 +1,160 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/permissions"
+)
+
+// respondServiceError maps service errors to HTTP statuses, so a freeze
+// reaches the dashboard as 423 with its reason.
+func respondServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrFrozen):
+		http.Error(w, err.Error(), http.StatusLocked)
+	case errors.Is(err, ErrInvalidHotfix), errors.Is(err, ErrInvalidFreeze), errors.Is(err, ErrNotFrozen):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrFreezeNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrConfigFreeze), errors.Is(err, ErrAlreadyOverride),
+		errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrNotApproved),
+		errors.Is(err, ErrNotApprovable), errors.Is(err, ErrStatusChanged):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handlers) ListFreezes(w http.ResponseWriter, r *http.Request) {
+	windows, err := h.service.ListFreezes(r.Context(), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	active, err := h.service.ActiveFreeze(r.Context(), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, map[string]any{
+		"active":  active,
+		"windows": windows,
+	})
+}
+
+func (h *Handlers) CreateFreeze(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason   string `json:"reason"`
+		Owner    string `json:"owner"`
+		StartsAt int64  `json:"starts_at"`
+		EndsAt   int64  `json:"ends_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor := "system"
+	if h.auth != nil {
+		user := h.auth.GetUser(r)
+		if user == nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		// Only granted users may freeze releases, so only they can name
+		// another owner.
+		if !h.permissions.Granted(r.Context(), FreezePermission, permissions.Subject{UserName: user.Username}) {
+			http.Error(w, "forbidden: not allowed to add deployment freezes", http.StatusForbidden)
+			return
+		}
+		actor = user.Username
+	}
+	if req.Owner == "" {
+		req.Owner = actor
+	}
+	if req.StartsAt == 0 {
+		req.StartsAt = time.Now().Unix()
+	}
+
+	window, err := h.service.CreateFreeze(r.Context(), database.FreezeWindow{
+		Reason:   req.Reason,
+		Owner:    req.Owner,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	})
+	if err != nil {
+		respondServiceError(w, err)
+		return
+	}
+	respondJSON(w, window)
+}
+
+func (h *Handlers) DeleteFreeze(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/api/freezes/"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid freeze ID", http.StatusBadRequest)
+		return
+	}
+
+	if h.auth != nil {
+		user := h.auth.GetUser(r)
+		if user == nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		subject := permissions.Subject{UserName: user.Username}
+		if !h.permissions.Allowed(r.Context(), FreezePermission, subject) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		window, err := h.service.GetFreeze(r.Context(), uint(id))
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		if window.Source != FreezeSourceConfig && window.Owner != user.Username && !h.permissions.Granted(r.Context(), FreezePermission, subject) {
+			http.Error(w, "only the freeze owner can remove it", http.StatusForbidden)
+			return
+		}
+	}
+
+	if err := h.service.DeleteFreeze(r.Context(), uint(id)); err != nil {
+		respondServiceError(w, err)
+		return
+	}
+	respondJSON(w, map[string]string{"status": "ok"})
+}
+
+// OverrideFreeze lets the release's PRs be merged during the active freeze.
+func (h *Handlers) OverrideFreeze(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(r.URL.Path, "/")
+	releaseID := parts[len(parts)-2]
+
+	actor := "system"
+	if h.auth != nil {
+		user := h.auth.GetUser(r)
+		if user == nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !h.permissions.Granted(r.Context(), FreezeOverridePermission, permissions.Subject{UserName: user.Username}) {
+			http.Error(w, "forbidden: not allowed to override deployment freezes", http.StatusForbidden)
+			return
+		}
+		actor = user.Email
+	}
+
+	if _, err := h.service.OverrideFreezeForMerge(r.Context(), releaseID, actor); err != nil {
+		respondServiceError(w, err)
+		return
+	}
+	respondJSON(w, map[string]string{"status": "ok"})
+}
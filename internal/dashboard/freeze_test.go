@@ -0,0 +1,295 @@
+package dashboard_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/pkg/github"
+	"github.com/user/mattermost-tools/pkg/github/mocks"
+)
+
+func activeFreeze(t *testing.T, svc *dashboard.Service) *database.FreezeWindow {
+	now := time.Now()
+	window, err := svc.CreateFreeze(context.Background(), database.FreezeWindow{
+		Reason:   "Holidays",
+		Owner:    "lead",
+		StartsAt: now.Add(-time.Hour).Unix(),
+		EndsAt:   now.Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	return window
+}
+
+func TestService_Freeze_BlocksApproveAndCreate(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{
+		SourceBranch: "uat",
+		DestBranch:   "master",
+		CreatedBy:    "user123",
+	})
+	require.NoError(t, err)
+
+	window := activeFreeze(t, svc)
+
+	err = svc.ApproveRelease(ctx, release.ID, "dev", "alice")
+	require.ErrorIs(t, err, dashboard.ErrFrozen)
+	var freezeErr *dashboard.FreezeError
+	require.True(t, errors.As(err, &freezeErr))
+	require.Equal(t, window.ID, freezeErr.Window.ID)
+	require.Contains(t, err.Error(), "Holidays")
+	require.Contains(t, err.Error(), "@lead")
+
+	_, err = svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master", CreatedBy: "user123"})
+	require.ErrorIs(t, err, dashboard.ErrFrozen)
+
+	require.NoError(t, svc.ApproveReleaseOverridingFreeze(ctx, release.ID, "dev", "boss"))
+	updated, err := svc.GetRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.Equal(t, "boss", updated.DevApprovedBy)
+
+	created, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master", CreatedBy: "boss", OverrideFreeze: true})
+	require.NoError(t, err)
+
+	for _, c := range []struct {
+		releaseID string
+		action    string
+		actor     string
+	}{
+		{release.ID, dashboard.FreezeActionApprove, "boss"},
+		{created.ID, dashboard.FreezeActionCreate, "boss"},
+	} {
+		history, err := svc.GetHistory(ctx, c.releaseID)
+		require.NoError(t, err)
+		var found bool
+		for _, h := range history {
+			if h.Action == "freeze_overridden" {
+				found = true
+				require.Equal(t, c.actor, h.Actor)
+				require.Contains(t, h.Details, `"action":"`+c.action+`"`)
+			}
+		}
+		require.True(t, found, "override of %s not recorded", c.action)
+	}
+}
+
+func TestService_Freeze_NotActive(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := svc.CreateFreeze(ctx, database.FreezeWindow{
+		Reason:   "Next week",
+		StartsAt: now.Add(24 * time.Hour).Unix(),
+		EndsAt:   now.Add(48 * time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master"})
+	require.NoError(t, err)
+	require.NoError(t, svc.ApproveRelease(ctx, release.ID, "dev", "alice"))
+
+	active, err := svc.ActiveFreeze(ctx, now)
+	require.NoError(t, err)
+	require.Nil(t, active)
+
+	_, err = svc.OverrideFreezeForMerge(ctx, release.ID, "boss")
+	require.ErrorIs(t, err, dashboard.ErrNotFrozen)
+}
+
+func TestService_MergeAllowed(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	allowed, window, err := svc.MergeAllowed(ctx, "rel-1")
+	require.NoError(t, err)
+	require.True(t, allowed)
+	require.Nil(t, window)
+
+	activeFreeze(t, svc)
+
+	allowed, window, err = svc.MergeAllowed(ctx, "rel-1")
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.NotNil(t, window)
+
+	_, err = svc.OverrideFreezeForMerge(ctx, "rel-1", "boss")
+	require.NoError(t, err)
+	_, err = svc.OverrideFreezeForMerge(ctx, "rel-1", "boss")
+	require.ErrorIs(t, err, dashboard.ErrAlreadyOverride)
+
+	allowed, _, err = svc.MergeAllowed(ctx, "rel-1")
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	allowed, _, err = svc.MergeAllowed(ctx, "rel-2")
+	require.NoError(t, err)
+	require.False(t, allowed)
+}
+
+func TestHandlers_OverrideFreeze_WithoutAuth(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master"})
+	require.NoError(t, err)
+	activeFreeze(t, svc)
+
+	h := dashboard.NewHandlers(svc, nil, nil, "", nil, nil, "")
+	rec := httptest.NewRecorder()
+	h.OverrideFreeze(rec, httptest.NewRequest(http.MethodPost, "/api/releases/"+release.ID+"/override-freeze", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	allowed, _, err := svc.MergeAllowed(ctx, release.ID)
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+func TestService_CreateFreeze_Invalid(t *testing.T) {
+	type tc struct {
+		name   string
+		window database.FreezeWindow
+	}
+
+	cases := []tc{
+		{name: "missing reason", window: database.FreezeWindow{StartsAt: 100, EndsAt: 200}},
+		{name: "ends before start", window: database.FreezeWindow{Reason: "x", StartsAt: 200, EndsAt: 100}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc := dashboard.NewService(setupTestDB(t))
+			_, err := svc.CreateFreeze(context.Background(), c.window)
+			require.ErrorIs(t, err, dashboard.ErrInvalidFreeze)
+		})
+	}
+}
+
+func TestService_GetAndDeleteFreeze(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	window := activeFreeze(t, svc)
+	got, err := svc.GetFreeze(ctx, window.ID)
+	require.NoError(t, err)
+	require.Equal(t, "lead", got.Owner)
+
+	require.NoError(t, svc.DeleteFreeze(ctx, window.ID))
+	_, err = svc.GetFreeze(ctx, window.ID)
+	require.ErrorIs(t, err, dashboard.ErrFreezeNotFound)
+	require.ErrorIs(t, svc.DeleteFreeze(ctx, window.ID), dashboard.ErrFreezeNotFound)
+}
+
+func TestService_SyncConfigFreezes(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	holidays := database.FreezeWindow{Reason: "Holidays", StartsAt: now.Unix(), EndsAt: now.Add(time.Hour).Unix()}
+	audit := database.FreezeWindow{Reason: "Audit", StartsAt: now.Add(2 * time.Hour).Unix(), EndsAt: now.Add(3 * time.Hour).Unix()}
+	require.NoError(t, svc.SyncConfigFreezes(ctx, []database.FreezeWindow{holidays, audit}))
+
+	windows, err := svc.ListFreezes(ctx, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, windows, 2)
+	holidaysID := windows[0].ID
+	require.Equal(t, dashboard.FreezeSourceConfig, windows[0].Source)
+	require.ErrorIs(t, svc.DeleteFreeze(ctx, holidaysID), dashboard.ErrConfigFreeze)
+
+	holidays.Reason = "Winter holidays"
+	require.NoError(t, svc.SyncConfigFreezes(ctx, []database.FreezeWindow{holidays}))
+
+	windows, err = svc.ListFreezes(ctx, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	require.Equal(t, holidaysID, windows[0].ID)
+	require.Equal(t, "Winter holidays", windows[0].Reason)
+}
+
+func TestConfigFreezes(t *testing.T) {
+	windows, err := dashboard.ConfigFreezes(config.FreezeConfig{
+		Timezone: "Europe/Kyiv",
+		Windows: []config.FreezeWindowConfig{
+			{Start: "2026-12-24 18:00", End: "2027-01-03 09:00", Reason: "Holidays", Owner: "lead"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+
+	loc, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 12, 24, 18, 0, 0, 0, loc).Unix(), windows[0].StartsAt)
+	require.Equal(t, "lead", windows[0].Owner)
+
+	_, err = dashboard.ConfigFreezes(config.FreezeConfig{
+		Windows: []config.FreezeWindowConfig{{Start: "tomorrow", End: "2027-01-03 09:00", Reason: "x"}},
+	})
+	require.Error(t, err)
+}
+
+func TestFreezeGuard_Sync(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master"})
+	require.NoError(t, err)
+	require.NoError(t, svc.AddRepos(ctx, release.ID, []dashboard.RepoData{
+		{RepoName: "auth-service", PRNumber: 12, HeadSHA: "abc123"},
+		{RepoName: "billing", PRNumber: 13, HeadSHA: "def456", PRMerged: true},
+		{RepoName: "no-pr", HeadSHA: "0123"},
+	}))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var states []string
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "/repos/org/auth-service/statuses/abc123", req.URL.Path)
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.Contains(t, string(body), `"context":"`+dashboard.FreezeStatusContext+`"`)
+			switch {
+			case strings.Contains(string(body), `"state":"failure"`):
+				states = append(states, "failure")
+			case strings.Contains(string(body), `"state":"success"`):
+				states = append(states, "success")
+			}
+			return &http.Response{StatusCode: 201, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		}).
+		Times(3)
+
+	guard := dashboard.NewFreezeGuard(svc, github.NewClientWithHTTP("token", mockHTTP), "org", "https://dash", time.Minute)
+
+	guard.Sync(ctx)
+	activeFreeze(t, svc)
+	guard.Sync(ctx)
+	guard.Sync(ctx)
+	_, err = svc.OverrideFreezeForMerge(ctx, release.ID, "boss")
+	require.NoError(t, err)
+	guard.Sync(ctx)
+
+	require.Equal(t, []string{"success", "failure", "success"}, states)
+}
@@ -1,33 +1,43 @@
 package dashboard
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/jobs"
 	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/internal/permissions"
+	"github.com/user/mattermost-tools/internal/releasenotes"
+	"github.com/user/mattermost-tools/internal/summarizer"
 	"github.com/user/mattermost-tools/pkg/github"
 	"github.com/user/mattermost-tools/pkg/mattermost"
 )
 
+const jobTypeReleaseSync = "release-sync"
+
 type Handlers struct {
-	service       *Service
-	auth          *Auth
-	ghClient      *github.Client
-	org           string
-	ignoredRepos  map[string]struct{}
-	mmBot         *mattermost.Bot
-	baseURL       string
-	ciTracker     *CITracker
-	argocdTracker *ArgoCDTracker
+	service         *Service
+	auth            *Auth
+	ghClient        *github.Client
+	org             string
+	ignoredRepos    map[string]struct{}
+	mmBot           *mattermost.Bot
+	baseURL         string
+	ciTracker       *CITracker
+	argocdTracker   *ArgoCDTracker
+	releaseChannels *ReleaseChannels
+	permissions     *permissions.Checker
+	jobs            *jobs.Manager
+	summarizer      summarizer.Summarizer
+	summaryCache    *summarizer.Cache
+	notesRenderer   *releasenotes.Renderer
 }
 
 func NewHandlers(service *Service, auth *Auth, ghClient *github.Client, org string, ignoredRepos map[string]struct{}, mmBot *mattermost.Bot, baseURL string) *Handlers {
@@ -39,9 +49,22 @@ func NewHandlers(service *Service, auth *Auth, ghClient *github.Client, org stri
 		ignoredRepos: ignoredRepos,
 		mmBot:        mmBot,
 		baseURL:      baseURL,
+		summarizer:   summarizer.NewCLI("", nil, 0),
 	}
 }
 
+func (h *Handlers) SetSummarizer(s summarizer.Summarizer) {
+	h.summarizer = s
+}
+
+func (h *Handlers) SetSummaryCache(cache *summarizer.Cache) {
+	h.summaryCache = cache
+}
+
+func (h *Handlers) SetNotesRenderer(r *releasenotes.Renderer) {
+	h.notesRenderer = r
+}
+
 func (h *Handlers) SetCITracker(ciTracker *CITracker) {
 	h.ciTracker = ciTracker
 }
@@ -50,9 +73,36 @@ func (h *Handlers) SetArgoCDTracker(tracker *ArgoCDTracker) {
 	h.argocdTracker = tracker
 }
 
+func (h *Handlers) SetReleaseChannels(channels *ReleaseChannels) {
+	h.releaseChannels = channels
+}
+
+func (h *Handlers) SetPermissions(checker *permissions.Checker) {
+	h.permissions = checker
+}
+
+// SetJobs runs release repo syncs on the job queue instead of bare
+// goroutines.
+func (h *Handlers) SetJobs(m *jobs.Manager) {
+	h.jobs = m
+	m.Register(jobTypeReleaseSync, 3, h.runReleaseSyncJob)
+}
+
+// authorize checks the permission rules for a dashboard action on a release,
+// using the release channel for channel-role rules.
+func (h *Handlers) authorize(ctx context.Context, action, releaseID string, user *UserInfo) bool {
+	subject := permissions.Subject{UserName: user.Username}
+	if release, err := h.service.GetRelease(ctx, releaseID); err == nil {
+		subject.ChannelID = release.ChannelID
+	}
+	return h.permissions.Allowed(ctx, action, subject)
+}
+
 func (h *Handlers) ListReleases(w http.ResponseWriter, r *http.Request) {
-	status := r.URL.Query().Get("status")
-	releases, err := h.service.ListReleases(r.Context(), status)
+	releases, err := h.service.ListReleasesFiltered(r.Context(), ListReleasesOptions{
+		Status: r.URL.Query().Get("status"),
+		Type:   r.URL.Query().Get("type"),
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -62,67 +112,137 @@ func (h *Handlers) ListReleases(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) CreateRelease(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		SourceBranch string `json:"source_branch"`
-		DestBranch   string `json:"dest_branch"`
+		SourceBranch string                `json:"source_branch"`
+		DestBranch   string                `json:"dest_branch"`
+		HotfixRepos  []database.HotfixRepo `json:"hotfix_repos"`
+		// OverrideFreeze needs the freeze-override permission.
+		OverrideFreeze bool `json:"override_freeze"`
+		// Draft creates the release as a draft; see CreateReleaseRequest.
+		Draft bool `json:"draft"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if req.SourceBranch == "" || req.DestBranch == "" {
-		http.Error(w, "source_branch and dest_branch are required", http.StatusBadRequest)
+	if req.DestBranch == "" || (req.SourceBranch == "" && req.HotfixRepos == nil) {
+		http.Error(w, "dest_branch and either source_branch or hotfix_repos are required", http.StatusBadRequest)
 		return
 	}
 
 	actor := "system"
+	var requesterUsername string
 	if h.auth != nil {
 		if user := h.auth.GetUser(r); user != nil {
 			actor = user.Email
+			requesterUsername = user.Username
+			if req.OverrideFreeze && !h.permissions.Granted(r.Context(), FreezeOverridePermission, permissions.Subject{UserName: user.Username}) {
+				http.Error(w, "forbidden: not allowed to override deployment freezes", http.StatusForbidden)
+				return
+			}
 		}
 	}
 
 	release, err := h.service.CreateRelease(r.Context(), CreateReleaseRequest{
-		SourceBranch: req.SourceBranch,
-		DestBranch:   req.DestBranch,
-		CreatedBy:    actor,
+		SourceBranch:   req.SourceBranch,
+		DestBranch:     req.DestBranch,
+		CreatedBy:      actor,
+		HotfixRepos:    req.HotfixRepos,
+		OverrideFreeze: req.OverrideFreeze,
+		Draft:          req.Draft,
 	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondServiceError(w, err)
 		return
 	}
 
-	h.service.RecordHistory(r.Context(), release.ID, "release_created", actor, map[string]any{
-		"source_branch": req.SourceBranch,
-		"dest_branch":   req.DestBranch,
-	})
+	details := map[string]any{
+		"source_branch": release.SourceBranch,
+		"dest_branch":   release.DestBranch,
+	}
+	if release.IsHotfix {
+		details["hotfix_repos"] = req.HotfixRepos
+	}
+	h.service.RecordHistory(r.Context(), release.ID, "release_created", actor, details)
 
 	if h.ghClient != nil {
-		go func() {
-			ctx := context.Background()
-			repos, err := h.gatherRepoData(ctx, req.SourceBranch, req.DestBranch)
-			if err == nil && len(repos) > 0 {
-				h.service.RefreshRepos(ctx, release.ID, repos)
-				h.service.RecordHistory(ctx, release.ID, "repos_synced", "system", map[string]any{
-					"count": len(repos),
-				})
-				if h.ciTracker != nil {
-					h.ciTracker.InitCITracking(ctx, release.ID)
-				}
+		args := releaseSyncArgs{
+			ReleaseID: release.ID,
+			Source:    release.SourceBranch,
+			Dest:      release.DestBranch,
+			Requester: requesterUsername,
+		}
+		if h.jobs != nil {
+			if _, err := h.jobs.Enqueue(r.Context(), jobTypeReleaseSync, jobs.Target{RequestedBy: requesterUsername}, args); err != nil {
+				logger.Error().Err(err).Str("release_id", release.ID).Msg("Failed to queue release sync")
 			}
-		}()
+		} else {
+			go func() {
+				if err := h.syncRelease(context.Background(), args, nil); err != nil {
+					logger.Error().Err(err).Str("release_id", release.ID).Msg("Failed to sync release repos")
+				}
+			}()
+		}
 	}
 
 	respondJSON(w, map[string]any{
 		"ID":           release.ID,
 		"SourceBranch": release.SourceBranch,
 		"DestBranch":   release.DestBranch,
+		"IsHotfix":     release.IsHotfix,
 		"Status":       release.Status,
 		"CreatedAt":    release.CreatedAt,
 		"syncing":      h.ghClient != nil,
 	})
 }
 
+type releaseSyncArgs struct {
+	ReleaseID string `json:"release_id"`
+	Source    string `json:"source"`
+	Dest      string `json:"dest"`
+	Requester string `json:"requester"`
+}
+
+func (h *Handlers) runReleaseSyncJob(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+	var args releaseSyncArgs
+	if err := job.GetArgs(&args); err != nil {
+		return err
+	}
+	return h.syncRelease(ctx, args, p)
+}
+
+// syncRelease fills a newly created release with the repos that changed
+// between its branches, or with its hotfix repos.
+func (h *Handlers) syncRelease(ctx context.Context, args releaseSyncArgs, p *jobs.Progress) error {
+	release, err := h.service.GetRelease(ctx, args.ReleaseID)
+	if err != nil {
+		return err
+	}
+	repos, err := h.gatherReleaseRepos(ctx, release, p)
+	if err != nil {
+		return fmt.Errorf("gathering repos: %w", err)
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+
+	if err := h.service.RefreshRepos(ctx, args.ReleaseID, repos); err != nil {
+		return err
+	}
+	h.service.RecordHistory(ctx, args.ReleaseID, "repos_synced", "system", map[string]any{
+		"count": len(repos),
+	})
+	if h.ciTracker != nil {
+		h.ciTracker.InitCITracking(ctx, args.ReleaseID)
+	}
+	if h.releaseChannels != nil {
+		if _, err := h.releaseChannels.Create(ctx, args.ReleaseID, args.Requester); err != nil {
+			logger.Error().Err(err).Str("release_id", args.ReleaseID).Msg("Failed to create release channel")
+		}
+	}
+	return nil
+}
+
 func (h *Handlers) GetRelease(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimPrefix(r.URL.Path, "/api/releases/")
 	id = strings.Split(id, "/")[0]
@@ -333,8 +453,22 @@ func (h *Handlers) ApproveRelease(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.ApproveRelease(r.Context(), releaseID, approvalType, user.Username); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if !h.authorize(r.Context(), "approve", releaseID, user) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	approve := h.service.ApproveRelease
+	if r.URL.Query().Get("override_freeze") == "true" {
+		if !h.permissions.Granted(r.Context(), FreezeOverridePermission, permissions.Subject{UserName: user.Username}) {
+			http.Error(w, "forbidden: not allowed to override deployment freezes", http.StatusForbidden)
+			return
+		}
+		approve = h.service.ApproveReleaseOverridingFreeze
+	}
+
+	if err := approve(r.Context(), releaseID, approvalType, user.Username); err != nil {
+		respondServiceError(w, err)
 		return
 	}
 
@@ -350,18 +484,22 @@ func (h *Handlers) RevokeApproval(w http.ResponseWriter, r *http.Request) {
 	approvalType := parts[len(parts)-1]
 	releaseID := parts[len(parts)-3]
 
-	if err := h.service.RevokeApproval(r.Context(), releaseID, approvalType); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
 	actor := "system"
 	if h.auth != nil {
 		if user := h.auth.GetUser(r); user != nil {
+			if !h.authorize(r.Context(), "approve", releaseID, user) {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
 			actor = user.Email
 		}
 	}
 
+	if err := h.service.RevokeApproval(r.Context(), releaseID, approvalType, actor); err != nil {
+		respondServiceError(w, err)
+		return
+	}
+
 	h.service.RecordHistory(r.Context(), releaseID, "approval_revoked", actor, map[string]any{
 		"type": approvalType,
 	})
@@ -386,16 +524,7 @@ func (h *Handlers) RefreshRelease(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	existingRepos := make(map[string]existingRepoData)
-	for _, repo := range releaseWithRepos.Repos {
-		existingRepos[repo.RepoName] = existingRepoData{
-			HeadSHA:    repo.HeadSHA,
-			Summary:    repo.Summary,
-			IsBreaking: repo.IsBreaking,
-		}
-	}
-
-	repos, err := h.gatherRepoDataWithExisting(ctx, releaseWithRepos.SourceBranch, releaseWithRepos.DestBranch, existingRepos)
+	repos, err := h.gatherReleaseRepos(ctx, &releaseWithRepos.Release, nil)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -433,37 +562,51 @@ func (h *Handlers) DeclineRelease(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeclineRelease(r.Context(), releaseID, user.Username); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if !h.authorize(r.Context(), "decline", releaseID, user) {
+		http.Error(w, "forbidden", http.StatusForbidden)
 		return
 	}
 
-	h.service.RecordHistory(r.Context(), releaseID, "release_declined", user.Email, nil)
+	if err := h.service.DeclineRelease(r.Context(), releaseID, user.Username, nil); err != nil {
+		respondServiceError(w, err)
+		return
+	}
 
 	respondJSON(w, map[string]string{"status": "ok"})
 }
 
-type existingRepoData struct {
-	HeadSHA    string
-	Summary    string
-	IsBreaking bool
+// repoTarget is a repo to compare against the release's destination branch
+// and the ref to compare from.
+type repoTarget struct {
+	Name string
+	Ref  string
 }
 
-func (h *Handlers) gatherRepoData(ctx context.Context, sourceBranch, destBranch string) ([]RepoData, error) {
-	return h.gatherRepoDataWithExisting(ctx, sourceBranch, destBranch, nil)
+// gatherReleaseRepos compares the release's hotfix repos from their refs, or
+// for regular releases every active repo in the org from the source branch.
+func (h *Handlers) gatherReleaseRepos(ctx context.Context, release *database.Release, p *jobs.Progress) ([]RepoData, error) {
+	if !release.IsHotfix {
+		return h.gatherRepoData(ctx, release.SourceBranch, release.DestBranch, p)
+	}
+
+	hotfixRepos, err := release.GetHotfixRepos()
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]repoTarget, 0, len(hotfixRepos))
+	for _, r := range hotfixRepos {
+		targets = append(targets, repoTarget{Name: r.Repo, Ref: r.Ref})
+	}
+	return h.gatherTargets(ctx, targets, release.DestBranch, p)
 }
 
-func (h *Handlers) gatherRepoDataWithExisting(ctx context.Context, sourceBranch, destBranch string, existing map[string]existingRepoData) ([]RepoData, error) {
+func (h *Handlers) gatherRepoData(ctx context.Context, sourceBranch, destBranch string, p *jobs.Progress) ([]RepoData, error) {
 	repos, err := h.ghClient.ListRepositories(ctx, h.org)
 	if err != nil {
 		return nil, err
 	}
 
-	var results []RepoData
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, 4)
-
+	var targets []repoTarget
 	for _, repo := range repos {
 		if repo.Archived {
 			continue
@@ -471,14 +614,34 @@ func (h *Handlers) gatherRepoDataWithExisting(ctx context.Context, sourceBranch,
 		if _, ignored := h.ignoredRepos[repo.Name]; ignored {
 			continue
 		}
+		targets = append(targets, repoTarget{Name: repo.Name, Ref: sourceBranch})
+	}
+	return h.gatherTargets(ctx, targets, destBranch, p)
+}
+
+// gatherTargets compares each target with destBranch. Summaries of
+// comparisons seen before come from the shared summary cache.
+func (h *Handlers) gatherTargets(ctx context.Context, targets []repoTarget, destBranch string, p *jobs.Progress) ([]RepoData, error) {
+	p.SetTotal(len(targets))
+
+	var results []RepoData
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4)
 
+	for _, repo := range targets {
 		wg.Add(1)
-		go func(repo github.Repository) {
+		go func(repo repoTarget) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
+			defer p.Advance()
+
+			if ctx.Err() != nil {
+				return
+			}
 
-			compare, err := h.ghClient.CompareBranches(ctx, h.org, repo.Name, destBranch, sourceBranch)
+			compare, err := h.ghClient.CompareBranches(ctx, h.org, repo.Name, destBranch, repo.Ref)
 			if err != nil || compare == nil || compare.TotalCommits == 0 || len(compare.Files) == 0 {
 				return
 			}
@@ -502,23 +665,11 @@ func (h *Handlers) gatherRepoDataWithExisting(ctx context.Context, sourceBranch,
 
 			infraChanges := detectInfraChanges(compare.Files)
 
-			pr, _ := h.ghClient.FindPullRequest(ctx, h.org, repo.Name, sourceBranch, destBranch)
-
-			var headSHA string
-			if len(compare.Commits) > 0 {
-				headSHA = compare.Commits[len(compare.Commits)-1].SHA
-			}
+			pr, _ := h.ghClient.FindPullRequest(ctx, h.org, repo.Name, repo.Ref, destBranch)
 
-			var summary string
-			var isBreaking bool
-			if existing != nil {
-				if ex, ok := existing[repo.Name]; ok && ex.HeadSHA == headSHA && ex.Summary != "" {
-					summary = ex.Summary
-					isBreaking = ex.IsBreaking
-				}
-			}
-			if summary == "" {
-				summary, isBreaking = generateChangeSummary(repo.Name, compare)
+			result, err := summarizer.Summarize(ctx, h.summarizer, summarizer.Input{Repo: repo.Name, Compare: compare})
+			if err != nil {
+				logger.Warn().Err(err).Str("repo", repo.Name).Msg("Summary failed")
 			}
 
 			var mergeCommitSHA string
@@ -550,13 +701,13 @@ func (h *Handlers) gatherRepoDataWithExisting(ctx context.Context, sourceBranch,
 				Additions:      additions,
 				Deletions:      deletions,
 				Contributors:   contributors,
-				Summary:        summary,
-				IsBreaking:     isBreaking,
 				InfraChanges:   infraChanges,
 				MergeCommitSHA: mergeCommitSHA,
-				HeadSHA:        headSHA,
+				HeadSHA:        compare.HeadSHA(),
 				PRMerged:       prMerged,
+				SourceRef:      repo.Ref,
 			}
+			data.SetSummary(result)
 			if pr != nil {
 				data.PRNumber = pr.Number
 				data.PRURL = pr.HTMLURL
@@ -569,6 +720,9 @@ func (h *Handlers) gatherRepoDataWithExisting(ctx context.Context, sourceBranch,
 	}
 
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
@@ -734,8 +888,20 @@ func (h *Handlers) PokeParticipants(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pendingActions := h.service.GetPendingActions(ctx, releaseWithRepos)
-	if len(pendingActions) == 0 {
+	actor := "system"
+	if h.auth != nil {
+		if user := h.auth.GetUser(r); user != nil {
+			actor = user.Email
+		}
+	}
+
+	poked, err := h.poke(ctx, releaseWithRepos, actor)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to send message: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if poked == 0 {
 		respondJSON(w, map[string]interface{}{
 			"status":  "ok",
 			"message": "No pending actions",
@@ -744,30 +910,34 @@ func (h *Handlers) PokeParticipants(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	respondJSON(w, map[string]interface{}{
+		"status":  "ok",
+		"message": "Poked participants",
+		"poked":   poked,
+	})
+}
+
+// poke posts a reminder with the release's pending actions to its channel and
+// returns how many actions were pending.
+func (h *Handlers) poke(ctx context.Context, releaseWithRepos *ReleaseWithRepos, actor string) (int, error) {
+	pendingActions := h.service.GetPendingActions(ctx, releaseWithRepos)
+	if len(pendingActions) == 0 {
+		return 0, nil
+	}
+
+	releaseID := releaseWithRepos.Release.ID
 	releaseURL := fmt.Sprintf("%s/releases/%s", h.baseURL, releaseID)
 	message := h.buildPokeMessage(releaseWithRepos.Release, pendingActions, releaseURL)
 
 	if err := h.mmBot.PostMessage(ctx, releaseWithRepos.Release.ChannelID, message); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to send message: %v", err), http.StatusInternalServerError)
-		return
-	}
-
-	actor := "system"
-	if h.auth != nil {
-		if user := h.auth.GetUser(r); user != nil {
-			actor = user.Email
-		}
+		return 0, err
 	}
 
 	h.service.RecordHistory(ctx, releaseID, "participants_poked", actor, map[string]any{
 		"count": len(pendingActions),
 	})
 
-	respondJSON(w, map[string]interface{}{
-		"status":  "ok",
-		"message": "Poked participants",
-		"poked":   len(pendingActions),
-	})
+	return len(pendingActions), nil
 }
 
 func (h *Handlers) buildPokeMessage(release database.Release, pendingActions []PendingAction, releaseURL string) string {
@@ -816,50 +986,6 @@ func (h *Handlers) buildPokeMessage(release database.Release, pendingActions []P
 	return sb.String()
 }
 
-func generateChangeSummary(repoName string, compare *github.CompareResult) (string, bool) {
-	var commitInfo strings.Builder
-	commitInfo.WriteString(fmt.Sprintf("Repository: %s\n", repoName))
-	commitInfo.WriteString(fmt.Sprintf("Total commits: %d\n\n", compare.TotalCommits))
-
-	commitInfo.WriteString("Commits:\n")
-	for _, c := range compare.Commits {
-		msg := strings.Split(c.Commit.Message, "\n")[0]
-		commitInfo.WriteString(fmt.Sprintf("- %s: %s\n", c.SHA[:7], msg))
-	}
-
-	commitInfo.WriteString("\nFiles changed:\n")
-	for _, f := range compare.Files {
-		commitInfo.WriteString(fmt.Sprintf("- %s (%s, +%d/-%d)\n", f.Filename, f.Status, f.Additions, f.Deletions))
-	}
-
-	prompt := fmt.Sprintf(`Analyze these git changes and provide a brief summary (2-3 sentences max).
-Focus on: what features/fixes are included, any breaking changes or important notes.
-If there are breaking changes, database migrations, API changes, or security updates, start your response with "BREAKING:" followed by the summary.
-Otherwise just provide the summary directly.
-
-%s`, commitInfo.String())
-
-	cmd := exec.Command("claude", "-p", prompt)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Sprintf("%d commits (AI summary unavailable)", compare.TotalCommits), false
-	}
-
-	summary := strings.TrimSpace(stdout.String())
-	isBreaking := strings.HasPrefix(strings.ToUpper(summary), "BREAKING:")
-
-	if isBreaking {
-		summary = strings.TrimPrefix(summary, "BREAKING:")
-		summary = strings.TrimPrefix(summary, "breaking:")
-		summary = strings.TrimSpace(summary)
-	}
-
-	return summary, isBreaking
-}
-
 func (h *Handlers) ConfirmRepo(w http.ResponseWriter, r *http.Request) {
 	parts := strings.Split(r.URL.Path, "/")
 	if len(parts) < 4 {
@@ -1187,3 +1313,85 @@ func (h *Handlers) GetDeploymentStatus(w http.ResponseWriter, r *http.Request) {
 		"any_pending": anyPending,
 	})
 }
+
+func (h *Handlers) ListJobs(w http.ResponseWriter, r *http.Request) {
+	if h.jobs == nil {
+		http.Error(w, "Job queue not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	opts := jobs.ListOptions{
+		Active:      r.URL.Query().Get("active") == "true",
+		RequestedBy: r.URL.Query().Get("requested_by"),
+		Limit:       50,
+	}
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit > 0 {
+		opts.Limit = limit
+	}
+
+	list, err := h.jobs.List(r.Context(), opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, list)
+}
+
+func (h *Handlers) GetJob(w http.ResponseWriter, r *http.Request) {
+	if h.jobs == nil {
+		http.Error(w, "Job queue not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	id := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/jobs/"), "/")[0]
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, jobs.ErrJobNotFound) || errors.Is(err, jobs.ErrAmbiguousID) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	respondJSON(w, job)
+}
+
+func (h *Handlers) CancelJob(w http.ResponseWriter, r *http.Request) {
+	if h.jobs == nil {
+		http.Error(w, "Job queue not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := r.Context()
+	id := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/jobs/"), "/")[0]
+	job, err := h.jobs.Get(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	actor := "system"
+	if h.auth != nil {
+		user := h.auth.GetUser(r)
+		if user == nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		actor = user.Username
+		if job.RequestedBy != user.Username && !h.permissions.Granted(ctx, jobs.CancelAnyPermission, permissions.Subject{UserName: user.Username}) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+	}
+
+	if err := h.jobs.Cancel(ctx, job.ID, actor); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, jobs.ErrJobFinished) {
+			status = http.StatusConflict
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	respondJSON(w, map[string]string{"status": "cancelled"})
+}
@@ -14,7 +14,7 @@ import (
 func TestIntegration_FullReleaseWorkflow(t *testing.T) {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
-	require.NoError(t, db.AutoMigrate(&database.Release{}, &database.ReleaseRepo{}))
+	require.NoError(t, db.AutoMigrate(&database.Release{}, &database.ReleaseRepo{}, &database.ReleaseHistory{}, &database.FreezeWindow{}, &database.FreezeOverride{}))
 
 	svc := dashboard.NewService(db)
 	ctx := context.Background()
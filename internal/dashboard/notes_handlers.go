@@ -0,0 +1,69 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/releasenotes"
+)
+
+// ReleaseNotes assembles the release notes document of a release, with its
+// deploy waves and approval trail.
+func (s *Service) ReleaseNotes(ctx context.Context, releaseID string) (*releasenotes.Document, error) {
+	release, err := s.GetReleaseWithRepos(ctx, releaseID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.GetHistory(ctx, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A dependency cycle leaves the waves out rather than failing the notes.
+	var included []database.ReleaseRepo
+	for _, r := range release.Repos {
+		if !r.Excluded {
+			included = append(included, r)
+		}
+	}
+	deployOrder, _ := CalculateDeployOrder(included)
+
+	return releasenotes.Build(release.Release, release.Repos, deployOrder, history), nil
+}
+
+// GetReleaseNotes renders the release notes in the format given by the
+// format query parameter: md (the default), html or json.
+func (h *Handlers) GetReleaseNotes(w http.ResponseWriter, r *http.Request) {
+	releaseID := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/releases/"), "/")[0]
+
+	format, err := releasenotes.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	doc, err := h.service.ReleaseNotes(r.Context(), releaseID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	renderer := h.notesRenderer
+	if renderer == nil {
+		if renderer, err = releasenotes.NewRenderer(""); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, doc, format); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", releasenotes.ContentType(format))
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,199 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/internal/mappings"
+	"github.com/user/mattermost-tools/pkg/mattermost"
+)
+
+const maxChannelNameLen = 64
+
+var channelNameInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+type ReleaseChannelConfig struct {
+	TeamID           string
+	Private          bool
+	Prefix           string
+	DefaultReviewers []string
+	DefaultQA        []string
+	BaseURL          string
+}
+
+// ReleaseChannels provisions a dedicated Mattermost channel per release and
+// archives it once the release is closed.
+type ReleaseChannels struct {
+	service *Service
+	mmBot   *mattermost.Bot
+	cfg     ReleaseChannelConfig
+}
+
+func NewReleaseChannels(service *Service, mmBot *mattermost.Bot, cfg ReleaseChannelConfig) *ReleaseChannels {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "release"
+	}
+	return &ReleaseChannels{
+		service: service,
+		mmBot:   mmBot,
+		cfg:     cfg,
+	}
+}
+
+// ReleaseChannelName builds a Mattermost-safe channel name such as
+// "release-uat-master-2025-01-14".
+func ReleaseChannelName(prefix, sourceBranch, destBranch string, date time.Time) string {
+	name := strings.Join([]string{prefix, sourceBranch, destBranch, date.Format("2006-01-02")}, "-")
+	name = channelNameInvalidChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > maxChannelNameLen {
+		name = strings.TrimRight(name[:maxChannelNameLen], "-")
+	}
+	return name
+}
+
+func (c *ReleaseChannels) Create(ctx context.Context, releaseID, requestedBy string) (*mattermost.Channel, error) {
+	log := logger.Get()
+
+	release, err := c.service.GetReleaseWithRepos(ctx, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := c.uniqueChannelName(ctx, ReleaseChannelName(c.cfg.Prefix, release.SourceBranch, release.DestBranch, time.Unix(release.CreatedAt, 0)))
+	if err != nil {
+		return nil, err
+	}
+
+	releaseURL := fmt.Sprintf("%s/releases/%s", c.cfg.BaseURL, release.ID)
+	channelType := mattermost.ChannelTypeOpen
+	if c.cfg.Private {
+		channelType = mattermost.ChannelTypePrivate
+	}
+
+	channel, err := c.mmBot.CreateChannel(ctx, mattermost.CreateChannelRequest{
+		TeamID:      c.cfg.TeamID,
+		Name:        name,
+		DisplayName: fmt.Sprintf("Release %s → %s (%s)", release.SourceBranch, release.DestBranch, time.Unix(release.CreatedAt, 0).Format("2006-01-02")),
+		Type:        channelType,
+		Purpose:     fmt.Sprintf("Release coordination for %s → %s", release.SourceBranch, release.DestBranch),
+		Header:      fmt.Sprintf("[Release Dashboard](%s)", releaseURL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.service.SetReleaseChannel(ctx, release.ID, channel.ID, true); err != nil {
+		return nil, err
+	}
+
+	invited := c.inviteParticipants(ctx, channel.ID, release, requestedBy)
+
+	postID, err := c.mmBot.PostMessageWithID(ctx, channel.ID, fmt.Sprintf("📌 **Release `%s` → `%s`**\n[View Dashboard](%s)", release.SourceBranch, release.DestBranch, releaseURL))
+	if err != nil {
+		log.Warn().Err(err).Str("channel", channel.Name).Msg("Failed to post dashboard link")
+	} else if err := c.mmBot.PinPost(ctx, postID); err != nil {
+		log.Warn().Err(err).Str("channel", channel.Name).Msg("Failed to pin dashboard link")
+	}
+
+	c.service.RecordHistory(ctx, release.ID, "channel_created", requestedBy, map[string]any{
+		"channel": channel.Name,
+		"invited": invited,
+	})
+
+	log.Info().Str("release_id", release.ID).Str("channel", channel.Name).Int("invited", invited).Msg("Release channel created")
+	return channel, nil
+}
+
+func (c *ReleaseChannels) Archive(ctx context.Context, release *database.Release) error {
+	if !release.DedicatedChannel || release.ChannelID == "" {
+		return nil
+	}
+
+	message := fmt.Sprintf("🔒 Release `%s` → `%s` is now **%s**. Archiving this channel.", release.SourceBranch, release.DestBranch, release.Status)
+	if err := c.mmBot.PostMessage(ctx, release.ChannelID, message); err != nil {
+		logger.Warn().Err(err).Str("release_id", release.ID).Msg("Failed to post archive notice")
+	}
+
+	if err := c.mmBot.ArchiveChannel(ctx, release.ChannelID); err != nil {
+		return err
+	}
+
+	c.service.RecordHistory(ctx, release.ID, "channel_archived", "system", map[string]any{
+		"status": release.Status,
+	})
+	return nil
+}
+
+func (c *ReleaseChannels) uniqueChannelName(ctx context.Context, base string) (string, error) {
+	name := base
+	for i := 2; i <= 20; i++ {
+		existing, err := c.mmBot.GetChannelByName(ctx, c.cfg.TeamID, name)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return name, nil
+		}
+		suffix := fmt.Sprintf("-%d", i)
+		if len(base)+len(suffix) > maxChannelNameLen {
+			name = base[:maxChannelNameLen-len(suffix)] + suffix
+		} else {
+			name = base + suffix
+		}
+	}
+	return "", fmt.Errorf("no free channel name for %s", base)
+}
+
+func (c *ReleaseChannels) inviteParticipants(ctx context.Context, channelID string, release *ReleaseWithRepos, requestedBy string) int {
+	log := logger.Get()
+
+	usernames := make(map[string]struct{})
+	if requestedBy != "" {
+		usernames[requestedBy] = struct{}{}
+	}
+	for _, u := range c.cfg.DefaultReviewers {
+		usernames[u] = struct{}{}
+	}
+	for _, u := range c.cfg.DefaultQA {
+		usernames[u] = struct{}{}
+	}
+
+	githubToMattermost := c.service.mattermostUsersByGitHub(ctx)
+	for _, repo := range release.Repos {
+		if repo.Excluded {
+			continue
+		}
+		contributors, err := repo.GetContributors()
+		if err != nil {
+			continue
+		}
+		for _, gh := range contributors {
+			if mm, ok := mappings.MattermostFromGitHub(ctx, gh); ok {
+				usernames[mm] = struct{}{}
+			} else if mm := githubToMattermost[gh]; mm != "" {
+				usernames[mm] = struct{}{}
+			}
+		}
+	}
+
+	invited := 0
+	for username := range usernames {
+		user, err := c.mmBot.GetUserByUsername(ctx, username)
+		if err != nil || user == nil {
+			log.Warn().Err(err).Str("user", username).Msg("Cannot invite unknown user to release channel")
+			continue
+		}
+		if err := c.mmBot.AddChannelMember(ctx, channelID, user.ID); err != nil {
+			log.Warn().Err(err).Str("user", username).Msg("Failed to invite user to release channel")
+			continue
+		}
+		invited++
+	}
+	return invited
+}
@@ -0,0 +1,291 @@
+package dashboard_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/pkg/mattermost"
+	"github.com/user/mattermost-tools/pkg/mattermost/mocks"
+)
+
+func TestReleaseChannelName_Success(t *testing.T) {
+	type tc struct {
+		name     string
+		prefix   string
+		source   string
+		dest     string
+		expected string
+	}
+
+	date := time.Date(2025, 1, 14, 10, 0, 0, 0, time.UTC)
+
+	cases := []tc{
+		{
+			name:     "simple branches",
+			prefix:   "release",
+			source:   "uat",
+			dest:     "master",
+			expected: "release-uat-master-2025-01-14",
+		},
+		{
+			name:     "branches with slashes and uppercase",
+			prefix:   "rel",
+			source:   "Release/1.2",
+			dest:     "main",
+			expected: "rel-release-1-2-main-2025-01-14",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, dashboard.ReleaseChannelName(c.prefix, c.source, c.dest, date))
+		})
+	}
+}
+
+func TestReleaseChannelName_Truncated(t *testing.T) {
+	date := time.Date(2025, 1, 14, 10, 0, 0, 0, time.UTC)
+
+	name := dashboard.ReleaseChannelName("release", strings.Repeat("feature-", 10), "master", date)
+
+	require.LessOrEqual(t, len(name), 64)
+	require.False(t, strings.HasSuffix(name, "-"))
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestReleaseChannels_Create_InvitesParticipants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&database.User{}))
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master", CreatedBy: "user123"})
+	require.NoError(t, err)
+	require.NoError(t, svc.AddRepos(ctx, release.ID, []dashboard.RepoData{
+		{RepoName: "auth-service", Contributors: []string{"Damanox", "frank-gh"}},
+		{RepoName: "billing", Contributors: []string{"ghost-gh"}},
+	}))
+	_, err = svc.CreateOrUpdateUser(ctx, "frank@example.com", "frank-gh", "frank")
+	require.NoError(t, err)
+
+	channelName := dashboard.ReleaseChannelName("release", "uat", "master", time.Unix(release.CreatedAt, 0))
+	var invited []string
+	var created mattermost.CreateChannelRequest
+	pinned := false
+
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			path := req.URL.Path
+			switch {
+			case req.Method == http.MethodGet && path == "/api/v4/teams/team1/channels/name/"+channelName:
+				return jsonResponse(404, `{"message":"not found"}`), nil
+			case req.Method == http.MethodPost && path == "/api/v4/channels":
+				require.NoError(t, json.NewDecoder(req.Body).Decode(&created))
+				return jsonResponse(201, `{"id":"ch1","name":"`+channelName+`","team_id":"team1"}`), nil
+			case req.Method == http.MethodGet && strings.HasPrefix(path, "/api/v4/users/username/"):
+				username := strings.TrimPrefix(path, "/api/v4/users/username/")
+				if username == "ghost" {
+					return jsonResponse(404, `{}`), nil
+				}
+				return jsonResponse(200, `{"id":"id-`+username+`","username":"`+username+`"}`), nil
+			case req.Method == http.MethodPost && path == "/api/v4/channels/ch1/members":
+				var payload map[string]string
+				require.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+				invited = append(invited, payload["user_id"])
+				return jsonResponse(201, `{}`), nil
+			case req.Method == http.MethodPost && path == "/api/v4/posts":
+				return jsonResponse(201, `{"id":"post1"}`), nil
+			case req.Method == http.MethodPost && path == "/api/v4/posts/post1/pin":
+				pinned = true
+				return jsonResponse(200, `{}`), nil
+			}
+			t.Fatalf("unexpected request %s %s", req.Method, path)
+			return nil, nil
+		}).
+		AnyTimes()
+
+	channels := dashboard.NewReleaseChannels(svc, mattermost.NewBotWithHTTP("https://mm.example.com", "bot-token", mockHTTP), dashboard.ReleaseChannelConfig{
+		TeamID:           "team1",
+		Private:          true,
+		DefaultReviewers: []string{"reviewer1"},
+		DefaultQA:        []string{"qa1", "ghost"},
+		BaseURL:          "https://dash.example.com",
+	})
+
+	channel, err := channels.Create(ctx, release.ID, "alice")
+	require.NoError(t, err)
+	require.Equal(t, "ch1", channel.ID)
+	require.Equal(t, channelName, created.Name)
+	require.Equal(t, mattermost.ChannelTypePrivate, created.Type)
+	require.Contains(t, created.Header, "https://dash.example.com/releases/"+release.ID)
+	require.True(t, pinned)
+
+	sort.Strings(invited)
+	require.Equal(t, []string{"id-alice", "id-damanox", "id-frank", "id-qa1", "id-reviewer1"}, invited)
+
+	updated, err := svc.GetRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.Equal(t, "ch1", updated.ChannelID)
+	require.True(t, updated.DedicatedChannel)
+
+	history, err := svc.GetHistory(ctx, release.ID)
+	require.NoError(t, err)
+	var entry *database.ReleaseHistory
+	for i := range history {
+		if history[i].Action == "channel_created" {
+			entry = &history[i]
+		}
+	}
+	require.NotNil(t, entry)
+	require.JSONEq(t, `{"channel":"`+channelName+`","invited":5}`, entry.Details)
+}
+
+func TestReleaseChannels_Archive(t *testing.T) {
+	type tc struct {
+		name       string
+		dedicated  bool
+		channelID  string
+		archiveErr bool
+		archived   bool
+		wantErr    bool
+	}
+
+	cases := []tc{
+		{name: "dedicated channel", dedicated: true, channelID: "ch1", archived: true},
+		{name: "shared channel is kept", dedicated: false, channelID: "town-square"},
+		{name: "no channel", dedicated: true},
+		{name: "archive fails", dedicated: true, channelID: "ch1", archiveErr: true, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			db := setupTestDB(t)
+			svc := dashboard.NewService(db)
+			ctx := context.Background()
+
+			release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master", CreatedBy: "user123"})
+			require.NoError(t, err)
+			release.DedicatedChannel = c.dedicated
+			release.ChannelID = c.channelID
+			release.Status = dashboard.StatusDeclined
+
+			var notice string
+			deleted := false
+			mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+			mockHTTP.EXPECT().
+				Do(gomock.Any()).
+				DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					switch {
+					case req.Method == http.MethodPost && req.URL.Path == "/api/v4/posts":
+						var payload map[string]string
+						require.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+						require.Equal(t, "ch1", payload["channel_id"])
+						notice = payload["message"]
+						return jsonResponse(201, `{"id":"post1"}`), nil
+					case req.Method == http.MethodDelete && req.URL.Path == "/api/v4/channels/ch1":
+						if c.archiveErr {
+							return jsonResponse(403, `{"message":"forbidden"}`), nil
+						}
+						deleted = true
+						return jsonResponse(200, `{"status":"OK"}`), nil
+					}
+					t.Fatalf("unexpected request %s %s", req.Method, req.URL.Path)
+					return nil, nil
+				}).
+				AnyTimes()
+
+			channels := dashboard.NewReleaseChannels(svc, mattermost.NewBotWithHTTP("https://mm.example.com", "bot-token", mockHTTP), dashboard.ReleaseChannelConfig{TeamID: "team1"})
+			err = channels.Archive(ctx, release)
+			if c.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, c.archived, deleted)
+
+			history, err := svc.GetHistory(ctx, release.ID)
+			require.NoError(t, err)
+			recorded := false
+			for _, h := range history {
+				if h.Action == "channel_archived" {
+					recorded = true
+					require.JSONEq(t, `{"status":"declined"}`, h.Details)
+				}
+			}
+			require.Equal(t, c.archived, recorded)
+			if c.archived {
+				require.Contains(t, notice, "**declined**")
+			}
+		})
+	}
+}
+
+func TestReleaseChannels_ArchivedWhenReleaseCloses(t *testing.T) {
+	type tc struct {
+		name   string
+		status string
+	}
+
+	cases := []tc{
+		{name: "declined", status: dashboard.StatusDeclined},
+		{name: "cancelled", status: dashboard.StatusCancelled},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			db := setupTestDB(t)
+			svc := dashboard.NewService(db)
+			ctx := context.Background()
+
+			release := approvedRelease(t, svc)
+			require.NoError(t, svc.SetReleaseChannel(ctx, release.ID, "ch1", true))
+
+			deleted := false
+			mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+			mockHTTP.EXPECT().
+				Do(gomock.Any()).
+				DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					if req.Method == http.MethodDelete {
+						require.Equal(t, "/api/v4/channels/ch1", req.URL.Path)
+						deleted = true
+					}
+					return jsonResponse(200, `{"id":"post1"}`), nil
+				}).
+				AnyTimes()
+
+			channels := dashboard.NewReleaseChannels(svc, mattermost.NewBotWithHTTP("https://mm.example.com", "bot-token", mockHTTP), dashboard.ReleaseChannelConfig{TeamID: "team1"})
+			svc.SetReleaseClosedCallback(func(rel *database.Release) {
+				require.NoError(t, channels.Archive(ctx, rel))
+			})
+
+			_, err := svc.TransitionRelease(ctx, release.ID, c.status, "lead", nil)
+			require.NoError(t, err)
+			require.True(t, deleted)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/database"
+)
+
+// Release statuses. A release moves draft → pending → approved → deploying →
+// deployed → closed; it can be declined or cancelled before it deploys and
+// rolled back while or after it deploys.
+const (
+	StatusDraft      = "draft"
+	StatusPending    = "pending"
+	StatusApproved   = "approved"
+	StatusDeploying  = "deploying"
+	StatusDeployed   = "deployed"
+	StatusClosed     = "closed"
+	StatusDeclined   = "declined"
+	StatusCancelled  = "cancelled"
+	StatusRolledBack = "rolled_back"
+)
+
+// TransitionPermission is the permissions rule key for changing a release's
+// status through the API. Declining uses the "decline" rule instead.
+const TransitionPermission = "transition"
+
+// releaseTransitions lists the statuses each status can move to. Statuses
+// without an entry are final.
+var releaseTransitions = map[string][]string{
+	StatusDraft:      {StatusPending, StatusDeclined, StatusCancelled},
+	StatusPending:    {StatusApproved, StatusDeclined, StatusCancelled},
+	StatusApproved:   {StatusPending, StatusDeploying, StatusDeclined, StatusCancelled},
+	StatusDeploying:  {StatusDeployed, StatusRolledBack},
+	StatusDeployed:   {StatusClosed, StatusRolledBack},
+	StatusRolledBack: {StatusPending, StatusClosed},
+}
+
+var (
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrNotApproved       = errors.New("release is missing required approvals")
+	ErrNotApprovable     = errors.New("approvals can only change while a release is draft, pending or approved")
+	ErrStatusChanged     = errors.New("release status changed concurrently")
+)
+
+// TransitionError is returned for a transition the state machine does not
+// allow. It matches ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("release cannot move from %s to %s", e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// TransitionGuard can refuse a transition of release to the given status by
+// returning an error.
+type TransitionGuard func(ctx context.Context, release *database.Release, to string) error
+
+// TransitionHook runs after release moved from the given status to its
+// current one.
+type TransitionHook func(release *database.Release, from string)
+
+// NextStatuses returns the statuses a release in status can move to.
+func NextStatuses(status string) []string {
+	return slices.Clone(releaseTransitions[status])
+}
+
+// CanTransition reports whether the state machine allows from → to.
+func CanTransition(from, to string) bool {
+	return slices.Contains(releaseTransitions[from], to)
+}
+
+// IsFinalStatus reports whether a release in status is no longer active.
+func IsFinalStatus(status string) bool {
+	_, ok := releaseTransitions[status]
+	return !ok
+}
+
+// FinalStatuses lists the statuses of releases that are no longer active.
+func FinalStatuses() []string {
+	return []string{StatusClosed, StatusDeclined, StatusCancelled}
+}
+
+// AcceptsApprovals reports whether approvals of a release in status can be
+// added or revoked.
+func AcceptsApprovals(status string) bool {
+	return status == StatusDraft || status == StatusPending || status == StatusApproved
+}
+
+// AddTransitionGuard registers a guard checked before every transition,
+// after the built-in ones.
+func (s *Service) AddTransitionGuard(guard TransitionGuard) {
+	s.transitionGuards = append(s.transitionGuards, guard)
+}
+
+// OnTransition registers a hook run after every transition, after the full
+// approval and release closed callbacks.
+func (s *Service) OnTransition(hook TransitionHook) {
+	s.transitionHooks = append(s.transitionHooks, hook)
+}
+
+// TransitionRelease moves a release to status to, checking the state machine
+// and guards, and records a status_changed history entry with details.
+// Moving back to pending clears the approvals, declining records who
+// declined and deployed records when. A draft that collected every approval
+// goes on from pending to approved.
+func (s *Service) TransitionRelease(ctx context.Context, id, to, actor string, details map[string]any) (*database.Release, error) {
+	return s.transitionRelease(ctx, id, to, actor, details, false)
+}
+
+// ObserveTransition is TransitionRelease for a change that already happened
+// outside mmtools, such as a deploy seen in ArgoCD. The freeze guard is
+// skipped: refusing it would only leave the release out of date.
+func (s *Service) ObserveTransition(ctx context.Context, id, to, actor string, details map[string]any) (*database.Release, error) {
+	return s.transitionRelease(ctx, id, to, actor, details, true)
+}
+
+func (s *Service) transitionRelease(ctx context.Context, id, to, actor string, details map[string]any, observed bool) (*database.Release, error) {
+	release, err := s.GetRelease(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	from := release.Status
+
+	updates := make(map[string]interface{})
+	switch to {
+	case StatusPending:
+		if release.Status != StatusDraft {
+			clearApprovals(updates)
+		}
+	case StatusDeclined:
+		clearApprovals(updates)
+		updates["declined_by"] = actor
+		updates["declined_at"] = time.Now().Unix()
+	case StatusDeployed:
+		updates["deployed_at"] = time.Now().Unix()
+	}
+
+	if err := s.transition(ctx, release, to, actor, updates, details, observed); err != nil {
+		return nil, err
+	}
+
+	if from == StatusDraft && to == StatusPending && s.fullyApproved(release) {
+		if err := s.transition(ctx, release, StatusApproved, actor, map[string]interface{}{}, map[string]any{"reason": "approved while draft"}, false); err != nil {
+			return nil, fmt.Errorf("updating status to approved: %w", err)
+		}
+	}
+	return release, nil
+}
+
+// transition applies updates and the status change to release in one
+// statement, provided its status has not changed since it was loaded, then
+// records the history entry and runs the hooks. release is updated in place.
+// observed skips the freeze guard, see ObserveTransition.
+func (s *Service) transition(ctx context.Context, release *database.Release, to, actor string, updates map[string]interface{}, details map[string]any, observed bool) error {
+	from := release.Status
+	if !CanTransition(from, to) {
+		return &TransitionError{From: from, To: to}
+	}
+	if err := s.checkTransition(ctx, release, to, updates, observed); err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	updates["status"] = to
+	updates["status_changed_by"] = actor
+	updates["status_changed_at"] = now
+
+	result := s.db.WithContext(ctx).Model(&database.Release{}).
+		Where("id = ? AND status = ?", release.ID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("changing release status to %s: %w", to, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatusChanged
+	}
+
+	updated, err := s.GetRelease(ctx, release.ID)
+	if err != nil {
+		return fmt.Errorf("loading release after status change: %w", err)
+	}
+	*release = *updated
+
+	entry := map[string]any{"from": from, "to": to}
+	for k, v := range details {
+		entry[k] = v
+	}
+	s.RecordHistory(ctx, release.ID, "status_changed", actor, entry)
+
+	if to == StatusApproved && s.onFullApprovalNotify != nil {
+		s.onFullApprovalNotify(release)
+	}
+	if IsFinalStatus(to) && s.onReleaseClosed != nil {
+		s.onReleaseClosed(release)
+	}
+	for _, hook := range s.transitionHooks {
+		hook(release, from)
+	}
+	return nil
+}
+
+// checkTransition runs the built-in guards, then the registered ones.
+// Approving needs every required approval, counting those in updates, and
+// deploying is refused during a freeze the release has not overridden,
+// unless the deploy was observed rather than requested.
+func (s *Service) checkTransition(ctx context.Context, release *database.Release, to string, updates map[string]interface{}, observed bool) error {
+	switch to {
+	case StatusApproved:
+		pending := *release
+		if by, ok := updates["dev_approved_by"].(string); ok {
+			pending.DevApprovedBy = by
+		}
+		if by, ok := updates["qa_approved_by"].(string); ok {
+			pending.QAApprovedBy = by
+		}
+		if !s.fullyApproved(&pending) {
+			return ErrNotApproved
+		}
+	case StatusDeploying:
+		if observed {
+			break
+		}
+		allowed, window, err := s.MergeAllowed(ctx, release.ID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return &FreezeError{Window: *window}
+		}
+	}
+
+	for _, guard := range s.transitionGuards {
+		if err := guard(ctx, release, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func clearApprovals(updates map[string]interface{}) {
+	updates["dev_approved_by"] = ""
+	updates["dev_approved_at"] = 0
+	updates["qa_approved_by"] = ""
+	updates["qa_approved_at"] = 0
+}
@@ -0,0 +1,69 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// GetTransitions returns the release status and the statuses it can move to.
+func (h *Handlers) GetTransitions(w http.ResponseWriter, r *http.Request) {
+	releaseID := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/releases/"), "/")[0]
+
+	release, err := h.service.GetRelease(r.Context(), releaseID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, map[string]interface{}{
+		"status": release.Status,
+		"next":   NextStatuses(release.Status),
+	})
+}
+
+// TransitionRelease moves a release to the status in the request body. It
+// needs the "transition" permission, or "decline" to decline.
+func (h *Handlers) TransitionRelease(w http.ResponseWriter, r *http.Request) {
+	releaseID := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/releases/"), "/")[0]
+
+	var req struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Status == "" {
+		http.Error(w, "status is required", http.StatusBadRequest)
+		return
+	}
+
+	actor := "system"
+	if h.auth != nil {
+		if user := h.auth.GetUser(r); user != nil {
+			action := TransitionPermission
+			if req.Status == StatusDeclined {
+				action = "decline"
+			}
+			if !h.authorize(r.Context(), action, releaseID, user) {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			actor = user.Username
+		}
+	}
+
+	var details map[string]any
+	if req.Reason != "" {
+		details = map[string]any{"reason": req.Reason}
+	}
+	release, err := h.service.TransitionRelease(r.Context(), releaseID, req.Status, actor, details)
+	if err != nil {
+		respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, release)
+}
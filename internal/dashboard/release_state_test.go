@@ -0,0 +1,192 @@
+package dashboard_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/dashboard"
+	"github.com/user/mattermost-tools/internal/database"
+)
+
+func approvedRelease(t *testing.T, svc *dashboard.Service) *database.Release {
+	ctx := context.Background()
+	release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master", CreatedBy: "user123"})
+	require.NoError(t, err)
+	require.NoError(t, svc.ApproveRelease(ctx, release.ID, "dev", "devlead"))
+	require.NoError(t, svc.ApproveRelease(ctx, release.ID, "qa", "qalead"))
+	return release
+}
+
+func TestCanTransition(t *testing.T) {
+	type tc struct {
+		from     string
+		to       string
+		expected bool
+	}
+
+	cases := []tc{
+		{from: dashboard.StatusDraft, to: dashboard.StatusPending, expected: true},
+		{from: dashboard.StatusPending, to: dashboard.StatusApproved, expected: true},
+		{from: dashboard.StatusApproved, to: dashboard.StatusDeploying, expected: true},
+		{from: dashboard.StatusDeploying, to: dashboard.StatusDeployed, expected: true},
+		{from: dashboard.StatusDeployed, to: dashboard.StatusClosed, expected: true},
+		{from: dashboard.StatusDeployed, to: dashboard.StatusRolledBack, expected: true},
+		{from: dashboard.StatusRolledBack, to: dashboard.StatusPending, expected: true},
+		{from: dashboard.StatusPending, to: dashboard.StatusDeploying, expected: false},
+		{from: dashboard.StatusDeploying, to: dashboard.StatusDeclined, expected: false},
+		{from: dashboard.StatusDeclined, to: dashboard.StatusPending, expected: false},
+		{from: dashboard.StatusClosed, to: dashboard.StatusRolledBack, expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.from+"->"+c.to, func(t *testing.T) {
+			require.Equal(t, c.expected, dashboard.CanTransition(c.from, c.to))
+		})
+	}
+
+	require.True(t, dashboard.IsFinalStatus(dashboard.StatusCancelled))
+	require.False(t, dashboard.IsFinalStatus(dashboard.StatusRolledBack))
+}
+
+func TestService_TransitionRelease_Lifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	var hooked []string
+	svc.OnTransition(func(release *database.Release, from string) {
+		hooked = append(hooked, from+"->"+release.Status)
+	})
+	var closed int
+	svc.SetReleaseClosedCallback(func(*database.Release) { closed++ })
+
+	release := approvedRelease(t, svc)
+
+	for _, to := range []string{dashboard.StatusDeploying, dashboard.StatusDeployed, dashboard.StatusClosed} {
+		updated, err := svc.TransitionRelease(ctx, release.ID, to, "deployer", map[string]any{"reason": "ship it"})
+		require.NoError(t, err)
+		require.Equal(t, to, updated.Status)
+		require.Equal(t, "deployer", updated.StatusChangedBy)
+		require.NotZero(t, updated.StatusChangedAt)
+	}
+
+	require.Equal(t, []string{"pending->approved", "approved->deploying", "deploying->deployed", "deployed->closed"}, hooked)
+	require.Equal(t, 1, closed)
+
+	history, err := svc.GetHistory(ctx, release.ID)
+	require.NoError(t, err)
+	var changes []string
+	for _, h := range history {
+		if h.Action == "status_changed" {
+			changes = append(changes, h.Details)
+		}
+	}
+	require.Len(t, changes, 4)
+	require.Contains(t, changes, `{"from":"deployed","reason":"ship it","to":"closed"}`)
+
+	_, err = svc.TransitionRelease(ctx, release.ID, dashboard.StatusPending, "deployer", nil)
+	require.ErrorIs(t, err, dashboard.ErrInvalidTransition)
+	var transitionErr *dashboard.TransitionError
+	require.True(t, errors.As(err, &transitionErr))
+	require.Equal(t, dashboard.StatusClosed, transitionErr.From)
+}
+
+func TestService_TransitionRelease_Guards(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master", CreatedBy: "user123", Draft: true})
+	require.NoError(t, err)
+	require.Equal(t, dashboard.StatusDraft, release.Status)
+
+	_, err = svc.TransitionRelease(ctx, release.ID, dashboard.StatusPending, "user123", nil)
+	require.NoError(t, err)
+	_, err = svc.TransitionRelease(ctx, release.ID, dashboard.StatusApproved, "user123", nil)
+	require.ErrorIs(t, err, dashboard.ErrNotApproved)
+
+	release = approvedRelease(t, svc)
+	activeFreeze(t, svc)
+	_, err = svc.TransitionRelease(ctx, release.ID, dashboard.StatusDeploying, "deployer", nil)
+	require.ErrorIs(t, err, dashboard.ErrFrozen)
+	_, err = svc.OverrideFreezeForMerge(ctx, release.ID, "boss")
+	require.NoError(t, err)
+
+	svc.AddTransitionGuard(func(_ context.Context, _ *database.Release, to string) error {
+		if to == dashboard.StatusDeploying {
+			return errors.New("CI is red")
+		}
+		return nil
+	})
+	_, err = svc.TransitionRelease(ctx, release.ID, dashboard.StatusDeploying, "deployer", nil)
+	require.ErrorContains(t, err, "CI is red")
+
+	updated, err := svc.GetRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.Equal(t, dashboard.StatusApproved, updated.Status)
+}
+
+func TestService_TransitionRelease_ApprovedDraft(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{SourceBranch: "uat", DestBranch: "master", CreatedBy: "user123", Draft: true})
+	require.NoError(t, err)
+	require.NoError(t, svc.ApproveRelease(ctx, release.ID, "dev", "dev1"))
+	require.NoError(t, svc.ApproveRelease(ctx, release.ID, "qa", "qa1"))
+
+	updated, err := svc.GetRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.Equal(t, dashboard.StatusDraft, updated.Status)
+
+	updated, err = svc.TransitionRelease(ctx, release.ID, dashboard.StatusPending, "user123", nil)
+	require.NoError(t, err)
+	require.Equal(t, dashboard.StatusApproved, updated.Status)
+	require.Equal(t, "dev1", updated.DevApprovedBy)
+	require.Equal(t, "qa1", updated.QAApprovedBy)
+}
+
+func TestService_ApprovalsFollowStatus(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release := approvedRelease(t, svc)
+	_, err := svc.TransitionRelease(ctx, release.ID, dashboard.StatusDeploying, "deployer", nil)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, svc.RevokeApproval(ctx, release.ID, "dev", "devlead"), dashboard.ErrNotApprovable)
+	require.ErrorIs(t, svc.ApproveRelease(ctx, release.ID, "dev", "devlead"), dashboard.ErrNotApprovable)
+
+	_, err = svc.TransitionRelease(ctx, release.ID, dashboard.StatusRolledBack, "deployer", nil)
+	require.NoError(t, err)
+	updated, err := svc.TransitionRelease(ctx, release.ID, dashboard.StatusPending, "deployer", nil)
+	require.NoError(t, err)
+	require.Empty(t, updated.DevApprovedBy)
+	require.Empty(t, updated.QAApprovedBy)
+
+	release = approvedRelease(t, svc)
+	require.NoError(t, svc.DeclineRelease(ctx, release.ID, "lead", map[string]any{"reason": "QA found a regression"}))
+	updated, err = svc.GetRelease(ctx, release.ID)
+	require.NoError(t, err)
+	require.Equal(t, dashboard.StatusDeclined, updated.Status)
+	require.Equal(t, "lead", updated.DeclinedBy)
+	require.Empty(t, updated.DevApprovedBy)
+	require.ErrorIs(t, svc.DeclineRelease(ctx, release.ID, "lead", nil), dashboard.ErrInvalidTransition)
+
+	history, err := svc.GetHistory(ctx, release.ID)
+	require.NoError(t, err)
+	var declines []string
+	for _, h := range history {
+		if h.Action == "status_changed" && strings.Contains(h.Details, `"to":"declined"`) {
+			declines = append(declines, h.Details)
+		}
+		require.NotEqual(t, "release_declined", h.Action)
+	}
+	require.Equal(t, []string{`{"from":"approved","reason":"QA found a regression","to":"declined"}`}, declines)
+}
@@ -3,12 +3,19 @@ package dashboard
 import (
 	"context"
 	"embed"
+	"errors"
+	"fmt"
 	"io/fs"
 	"net/http"
 	"strings"
 
 	"gorm.io/gorm"
 
+	"github.com/user/mattermost-tools/internal/changelog"
+	"github.com/user/mattermost-tools/internal/jobs"
+	"github.com/user/mattermost-tools/internal/permissions"
+	"github.com/user/mattermost-tools/internal/releasenotes"
+	"github.com/user/mattermost-tools/internal/summarizer"
 	"github.com/user/mattermost-tools/pkg/github"
 	"github.com/user/mattermost-tools/pkg/mattermost"
 )
@@ -68,6 +75,11 @@ func (s *Server) setupRoutes() {
 
 	s.mux.HandleFunc("/api/releases", s.handleReleases)
 	s.mux.HandleFunc("/api/releases/", s.handleRelease)
+	s.mux.HandleFunc("/api/jobs", s.handleJobs)
+	s.mux.HandleFunc("/api/jobs/", s.handleJob)
+	s.mux.HandleFunc("/api/freezes", s.handleFreezes)
+	s.mux.HandleFunc("/api/freezes/", s.handleFreeze)
+	s.mux.HandleFunc("/api/summaries", s.handleSummaries)
 	s.mux.HandleFunc("/api/users/me/github", s.handleMyGitHub)
 	s.mux.HandleFunc("/api/users/me/profile", s.handleMyProfile)
 }
@@ -129,6 +141,94 @@ func (s *Server) handleReleases(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *Server) handleFreezes(w http.ResponseWriter, r *http.Request) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.handlers.ListFreezes(w, r)
+		case http.MethodPost:
+			s.handlers.CreateFreeze(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+
+	if s.auth != nil {
+		s.auth.RequireAuth(handler)(w, r)
+	} else {
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleFreeze(w http.ResponseWriter, r *http.Request) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handlers.DeleteFreeze(w, r)
+	}
+
+	if s.auth != nil {
+		s.auth.RequireAuth(handler)(w, r)
+	} else {
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleSummaries(w http.ResponseWriter, r *http.Request) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handlers.InvalidateSummaries(w, r)
+	}
+
+	if s.auth != nil {
+		s.auth.RequireAuth(handler)(w, r)
+	} else {
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handlers.ListJobs(w, r)
+	}
+
+	if s.auth != nil {
+		s.auth.RequireAuth(handler)(w, r)
+	} else {
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/jobs/"), "/")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && len(parts) == 1:
+			s.handlers.GetJob(w, r)
+		case r.Method == http.MethodPost && len(parts) > 1 && parts[1] == "cancel":
+			s.handlers.CancelJob(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+
+	if s.auth != nil {
+		s.auth.RequireAuth(handler)(w, r)
+	} else {
+		handler(w, r)
+	}
+}
+
 func (s *Server) handleRelease(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/releases/")
 	parts := strings.Split(path, "/")
@@ -142,6 +242,12 @@ func (s *Server) handleRelease(w http.ResponseWriter, r *http.Request) {
 				s.handlers.GetCIStatus(w, r)
 			} else if len(parts) > 1 && parts[1] == "deployment-status" {
 				s.handlers.GetDeploymentStatus(w, r)
+			} else if len(parts) > 1 && parts[1] == "changelog" {
+				s.handlers.GetChangelog(w, r)
+			} else if len(parts) > 1 && parts[1] == "notes" {
+				s.handlers.GetReleaseNotes(w, r)
+			} else if len(parts) > 1 && parts[1] == "transitions" {
+				s.handlers.GetTransitions(w, r)
 			} else {
 				s.handlers.GetRelease(w, r)
 			}
@@ -158,8 +264,12 @@ func (s *Server) handleRelease(w http.ResponseWriter, r *http.Request) {
 				s.handlers.RefreshRelease(w, r)
 			} else if len(parts) > 1 && parts[1] == "decline" {
 				s.handlers.DeclineRelease(w, r)
+			} else if len(parts) > 1 && parts[1] == "transition" {
+				s.handlers.TransitionRelease(w, r)
 			} else if len(parts) > 1 && parts[1] == "poke" {
 				s.handlers.PokeParticipants(w, r)
+			} else if len(parts) > 1 && parts[1] == "freeze-override" {
+				s.handlers.OverrideFreeze(w, r)
 			} else if len(parts) > 3 && parts[1] == "repos" && parts[3] == "confirm" {
 				s.handlers.ConfirmRepo(w, r)
 			} else if len(parts) > 3 && parts[1] == "repos" && parts[3] == "refresh-chart-version" {
@@ -195,6 +305,78 @@ func (s *Server) SetArgoCDTracker(tracker *ArgoCDTracker) {
 	s.handlers.SetArgoCDTracker(tracker)
 }
 
+func (s *Server) SetReleaseChannels(channels *ReleaseChannels) {
+	s.handlers.SetReleaseChannels(channels)
+}
+
+func (s *Server) SetPermissions(checker *permissions.Checker) {
+	s.handlers.SetPermissions(checker)
+}
+
+func (s *Server) SetJobs(m *jobs.Manager) {
+	s.handlers.SetJobs(m)
+}
+
+func (s *Server) SetSummarizer(sum summarizer.Summarizer) {
+	s.handlers.SetSummarizer(sum)
+}
+
+func (s *Server) SetSummaryCache(cache *summarizer.Cache) {
+	s.handlers.SetSummaryCache(cache)
+}
+
+func (s *Server) SetNotesRenderer(r *releasenotes.Renderer) {
+	s.handlers.SetNotesRenderer(r)
+}
+
+// Changelog builds the conventional-commit changelog of every repo in the
+// release.
+func (s *Server) Changelog(ctx context.Context, releaseID string) ([]changelog.Repo, error) {
+	release, err := s.service.GetReleaseWithRepos(ctx, releaseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.handlers.releaseChangelog(ctx, release)
+}
+
+// PokeOpenReleases reminds participants of every release that is still
+// active and returns how many releases had pending actions.
+func (s *Server) PokeOpenReleases(ctx context.Context, actor string) (int, error) {
+	if s.handlers.mmBot == nil {
+		return 0, fmt.Errorf("mattermost bot not configured")
+	}
+
+	releases, err := s.service.ListReleases(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	var poked int
+	var errs []error
+	for _, rel := range releases {
+		if IsFinalStatus(rel.Status) || rel.ChannelID == "" {
+			continue
+		}
+
+		releaseWithRepos, err := s.service.GetReleaseWithRepos(ctx, rel.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("loading release %s: %w", rel.ID, err))
+			continue
+		}
+
+		count, err := s.handlers.poke(ctx, releaseWithRepos, actor)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("poking release %s: %w", rel.ID, err))
+			continue
+		}
+		if count > 0 {
+			poked++
+		}
+	}
+
+	return poked, errors.Join(errs...)
+}
+
 func (s *Server) Handler() http.Handler {
 	return s.mux
 }
@@ -11,6 +11,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/summarizer"
 )
 
 var (
@@ -18,26 +19,92 @@ var (
 	ErrNotContributor      = errors.New("not a contributor")
 	ErrAlreadyConfirmed    = errors.New("already confirmed")
 	ErrRepoNotFound        = errors.New("repo not found")
+	ErrReleaseNotFound     = errors.New("release not found")
+	ErrGitHubAlreadyLinked = errors.New("GitHub account already linked to another user")
+	ErrInvalidHotfix       = errors.New("hotfix needs at least one repo, each with a ref")
+)
+
+const (
+	ApprovalDev = "dev"
+	ApprovalQA  = "qa"
+
+	// hotfixSourceBranch is shown as the source of hotfix releases, whose
+	// repos each come from their own ref.
+	hotfixSourceBranch = "hotfix"
 )
 
 type Service struct {
 	db                   *gorm.DB
+	hotfixApprovals      []string
 	onFullApprovalNotify func(release *database.Release)
+	onReleaseClosed      func(release *database.Release)
+	transitionGuards     []TransitionGuard
+	transitionHooks      []TransitionHook
 }
 
 func NewService(db *gorm.DB) *Service {
-	return &Service{db: db}
+	return &Service{db: db, hotfixApprovals: []string{ApprovalDev}}
+}
+
+// SetHotfixApprovals sets the approvals a hotfix release needs; regular
+// releases always need dev and QA. Unknown types are ignored and an empty
+// list keeps the default of dev only.
+func (s *Service) SetHotfixApprovals(approvals []string) {
+	var valid []string
+	for _, a := range approvals {
+		if a == ApprovalDev || a == ApprovalQA {
+			valid = append(valid, a)
+		}
+	}
+	if len(valid) > 0 {
+		s.hotfixApprovals = valid
+	}
+}
+
+// RequiredApprovals returns the approval types the release needs before it
+// counts as approved.
+func (s *Service) RequiredApprovals(release *database.Release) []string {
+	if release.IsHotfix {
+		return s.hotfixApprovals
+	}
+	return []string{ApprovalDev, ApprovalQA}
+}
+
+func (s *Service) fullyApproved(release *database.Release) bool {
+	for _, a := range s.RequiredApprovals(release) {
+		if a == ApprovalDev && release.DevApprovedBy == "" {
+			return false
+		}
+		if a == ApprovalQA && release.QAApprovedBy == "" {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *Service) SetFullApprovalCallback(fn func(release *database.Release)) {
 	s.onFullApprovalNotify = fn
 }
 
+// SetReleaseClosedCallback registers fn to run once a release is no longer active.
+func (s *Service) SetReleaseClosedCallback(fn func(release *database.Release)) {
+	s.onReleaseClosed = fn
+}
+
 type CreateReleaseRequest struct {
 	SourceBranch string
 	DestBranch   string
 	CreatedBy    string
 	ChannelID    string
+	// HotfixRepos makes a hotfix release limited to these repos, each
+	// compared from its own ref instead of SourceBranch.
+	HotfixRepos []database.HotfixRepo
+	// OverrideFreeze creates the release during a freeze window; the
+	// override is recorded in the release history against CreatedBy.
+	OverrideFreeze bool
+	// Draft creates the release as a draft, which does not count as open
+	// until it is moved to pending.
+	Draft bool
 }
 
 type ReleaseWithRepos struct {
@@ -56,26 +123,76 @@ type RepoData struct {
 	PRMerged       bool
 	Summary        string
 	IsBreaking     bool
+	RiskLevel      string
+	HasMigration   bool
+	APIChanges     []string
+	ConfigChanges  []string
+	RolloutNotes   string
+	RiskReasons    []string
 	InfraChanges   []string
 	MergeCommitSHA string
 	HeadSHA        string
+	SourceRef      string
+}
+
+// SetSummary copies a summarizer result into d.
+func (d *RepoData) SetSummary(r summarizer.Result) {
+	d.Summary = r.Summary
+	d.IsBreaking = r.IsBreaking
+	d.RiskLevel = r.RiskLevel
+	d.HasMigration = r.HasMigration
+	d.APIChanges = r.APIChanges
+	d.ConfigChanges = r.ConfigChanges
+	d.RolloutNotes = r.RolloutNotes
+	d.RiskReasons = r.Reasons
 }
 
+// CreateRelease fails with a *FreezeError during a freeze window unless
+// req.OverrideFreeze is set.
 func (s *Service) CreateRelease(ctx context.Context, req CreateReleaseRequest) (*database.Release, error) {
+	overridden, err := s.checkFreeze(ctx, req.OverrideFreeze)
+	if err != nil {
+		return nil, err
+	}
+
 	release := &database.Release{
 		ID:           uuid.New().String(),
 		SourceBranch: req.SourceBranch,
 		DestBranch:   req.DestBranch,
-		Status:       "pending",
+		Status:       StatusPending,
 		CreatedBy:    req.CreatedBy,
 		ChannelID:    req.ChannelID,
 		CreatedAt:    time.Now().Unix(),
 	}
+	if req.Draft {
+		release.Status = StatusDraft
+	}
+
+	if req.HotfixRepos != nil {
+		if len(req.HotfixRepos) == 0 {
+			return nil, ErrInvalidHotfix
+		}
+		for _, r := range req.HotfixRepos {
+			if r.Repo == "" || r.Ref == "" {
+				return nil, ErrInvalidHotfix
+			}
+		}
+		release.IsHotfix = true
+		if release.SourceBranch == "" {
+			release.SourceBranch = hotfixSourceBranch
+		}
+		if err := release.SetHotfixRepos(req.HotfixRepos); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.db.WithContext(ctx).Create(release).Error; err != nil {
 		return nil, fmt.Errorf("creating release: %w", err)
 	}
 
+	if overridden != nil {
+		s.recordFreezeOverride(ctx, release.ID, FreezeActionCreate, req.CreatedBy, overridden)
+	}
 	return release, nil
 }
 
@@ -88,10 +205,26 @@ func (s *Service) GetRelease(ctx context.Context, id string) (*database.Release,
 }
 
 func (s *Service) ListReleases(ctx context.Context, status string) ([]database.Release, error) {
+	return s.ListReleasesFiltered(ctx, ListReleasesOptions{Status: status})
+}
+
+type ListReleasesOptions struct {
+	Status string
+	// Type is "hotfix" or "regular"; empty lists both.
+	Type string
+}
+
+func (s *Service) ListReleasesFiltered(ctx context.Context, opts ListReleasesOptions) ([]database.Release, error) {
 	var releases []database.Release
 	query := s.db.WithContext(ctx).Order("created_at DESC")
-	if status != "" {
-		query = query.Where("status = ?", status)
+	if opts.Status != "" {
+		query = query.Where("status = ?", opts.Status)
+	}
+	switch opts.Type {
+	case "hotfix":
+		query = query.Where("is_hotfix = ?", true)
+	case "regular":
+		query = query.Where("is_hotfix = ?", false)
 	}
 	if err := query.Find(&releases).Error; err != nil {
 		return nil, fmt.Errorf("listing releases: %w", err)
@@ -99,6 +232,35 @@ func (s *Service) ListReleases(ctx context.Context, status string) ([]database.R
 	return releases, nil
 }
 
+// FindReleaseForChannel resolves the release a chat message refers to: the
+// release whose announcement thread it was posted in, otherwise the newest
+// release bound to the channel that is not in a final status.
+func (s *Service) FindReleaseForChannel(ctx context.Context, channelID, threadID string) (*database.Release, error) {
+	var release database.Release
+
+	if threadID != "" {
+		err := s.db.WithContext(ctx).Where("mattermost_post_id = ?", threadID).First(&release).Error
+		if err == nil {
+			return &release, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("finding release by thread: %w", err)
+		}
+	}
+
+	err := s.db.WithContext(ctx).
+		Where("channel_id = ? AND status NOT IN ?", channelID, FinalStatuses()).
+		Order("created_at DESC").
+		First(&release).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReleaseNotFound
+		}
+		return nil, fmt.Errorf("finding release by channel: %w", err)
+	}
+	return &release, nil
+}
+
 func (s *Service) AddRepos(ctx context.Context, releaseID string, repos []RepoData) error {
 	for _, r := range repos {
 		repo := database.ReleaseRepo{
@@ -112,8 +274,12 @@ func (s *Service) AddRepos(ctx context.Context, releaseID string, repos []RepoDa
 			PRMerged:       r.PRMerged,
 			Summary:        r.Summary,
 			IsBreaking:     r.IsBreaking,
+			RiskLevel:      r.RiskLevel,
+			HasMigration:   r.HasMigration,
+			RolloutNotes:   r.RolloutNotes,
 			MergeCommitSHA: r.MergeCommitSHA,
 			HeadSHA:        r.HeadSHA,
+			SourceRef:      r.SourceRef,
 		}
 		if err := repo.SetContributors(r.Contributors); err != nil {
 			return fmt.Errorf("setting contributors: %w", err)
@@ -121,6 +287,15 @@ func (s *Service) AddRepos(ctx context.Context, releaseID string, repos []RepoDa
 		if err := repo.SetInfraChanges(r.InfraChanges); err != nil {
 			return fmt.Errorf("setting infra changes: %w", err)
 		}
+		if err := repo.SetAPIChanges(r.APIChanges); err != nil {
+			return fmt.Errorf("setting API changes: %w", err)
+		}
+		if err := repo.SetConfigChanges(r.ConfigChanges); err != nil {
+			return fmt.Errorf("setting config changes: %w", err)
+		}
+		if err := repo.SetRiskReasons(r.RiskReasons); err != nil {
+			return fmt.Errorf("setting risk reasons: %w", err)
+		}
 		if err := s.db.WithContext(ctx).Create(&repo).Error; err != nil {
 			return fmt.Errorf("adding repo %s: %w", r.RepoName, err)
 		}
@@ -188,58 +363,100 @@ func (s *Service) UpdateRepo(ctx context.Context, repoID uint, excluded *bool, d
 	return nil
 }
 
+// ApproveRelease fails with a *FreezeError during a freeze window and with
+// ErrNotApprovable once the release is past approval.
 func (s *Service) ApproveRelease(ctx context.Context, id, approvalType, userID string) error {
+	return s.approveRelease(ctx, id, approvalType, userID, false)
+}
+
+// ApproveReleaseOverridingFreeze approves even during a freeze window and
+// records the override in the release history. Callers check that userID is
+// allowed to override.
+func (s *Service) ApproveReleaseOverridingFreeze(ctx context.Context, id, approvalType, userID string) error {
+	return s.approveRelease(ctx, id, approvalType, userID, true)
+}
+
+// approveRelease records the approval and moves a pending release to
+// approved once it has every required approval.
+func (s *Service) approveRelease(ctx context.Context, id, approvalType, userID string, overrideFreeze bool) error {
 	now := time.Now().Unix()
 	updates := make(map[string]interface{})
 
 	switch approvalType {
-	case "dev":
+	case ApprovalDev:
 		updates["dev_approved_by"] = userID
 		updates["dev_approved_at"] = now
-	case "qa":
+	case ApprovalQA:
 		updates["qa_approved_by"] = userID
 		updates["qa_approved_at"] = now
 	default:
 		return fmt.Errorf("invalid approval type: %s", approvalType)
 	}
 
+	release, err := s.GetRelease(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !AcceptsApprovals(release.Status) {
+		return ErrNotApprovable
+	}
+
+	overridden, err := s.checkFreeze(ctx, overrideFreeze)
+	if err != nil {
+		return err
+	}
+
 	if err := s.db.WithContext(ctx).Model(&database.Release{}).Where("id = ?", id).Updates(updates).Error; err != nil {
 		return fmt.Errorf("approving release: %w", err)
 	}
+	if overridden != nil {
+		s.recordFreezeOverride(ctx, id, FreezeActionApprove, userID, overridden)
+	}
 
-	release, err := s.GetRelease(ctx, id)
+	release, err = s.GetRelease(ctx, id)
 	if err != nil {
 		return fmt.Errorf("checking approval status: %w", err)
 	}
 
-	if release.DevApprovedBy != "" && release.QAApprovedBy != "" {
-		if err := s.db.WithContext(ctx).Model(&database.Release{}).Where("id = ?", id).Update("status", "approved").Error; err != nil {
+	if release.Status == StatusPending && s.fullyApproved(release) {
+		if err := s.transition(ctx, release, StatusApproved, userID, map[string]interface{}{}, map[string]any{"approval": approvalType}, false); err != nil {
 			return fmt.Errorf("updating status to approved: %w", err)
 		}
-		release.Status = "approved"
-		if s.onFullApprovalNotify != nil {
-			s.onFullApprovalNotify(release)
-		}
 	}
 
 	return nil
 }
 
-func (s *Service) RevokeApproval(ctx context.Context, id, approvalType string) error {
+// RevokeApproval clears an approval, moving an approved release back to
+// pending. It fails with ErrNotApprovable once the release is past approval.
+func (s *Service) RevokeApproval(ctx context.Context, id, approvalType, actor string) error {
 	updates := make(map[string]interface{})
 
 	switch approvalType {
-	case "dev":
+	case ApprovalDev:
 		updates["dev_approved_by"] = ""
 		updates["dev_approved_at"] = 0
-	case "qa":
+	case ApprovalQA:
 		updates["qa_approved_by"] = ""
 		updates["qa_approved_at"] = 0
 	default:
 		return fmt.Errorf("invalid approval type: %s", approvalType)
 	}
 
-	updates["status"] = "pending"
+	release, err := s.GetRelease(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !AcceptsApprovals(release.Status) {
+		return ErrNotApprovable
+	}
+
+	if release.Status == StatusApproved {
+		if err := s.transition(ctx, release, StatusPending, actor, updates, map[string]any{"revoked": approvalType}, false); err != nil {
+			return fmt.Errorf("revoking approval: %w", err)
+		}
+		return nil
+	}
 
 	if err := s.db.WithContext(ctx).Model(&database.Release{}).Where("id = ?", id).Updates(updates).Error; err != nil {
 		return fmt.Errorf("revoking approval: %w", err)
@@ -259,6 +476,17 @@ func (s *Service) SetMattermostPostID(ctx context.Context, id, postID string) er
 	return s.db.WithContext(ctx).Model(&database.Release{}).Where("id = ?", id).Update("mattermost_post_id", postID).Error
 }
 
+func (s *Service) SetReleaseChannel(ctx context.Context, id, channelID string, dedicated bool) error {
+	updates := map[string]interface{}{
+		"channel_id":        channelID,
+		"dedicated_channel": dedicated,
+	}
+	if err := s.db.WithContext(ctx).Model(&database.Release{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("setting release channel: %w", err)
+	}
+	return nil
+}
+
 func (s *Service) RefreshRepos(ctx context.Context, releaseID string, repos []RepoData) error {
 	var existingRepos []database.ReleaseRepo
 	if err := s.db.WithContext(ctx).Where("release_id = ?", releaseID).Find(&existingRepos).Error; err != nil {
@@ -276,15 +504,11 @@ func (s *Service) RefreshRepos(ctx context.Context, releaseID string, repos []Re
 
 		existing := existingByName[r.RepoName]
 
-		summary := r.Summary
-		isBreaking := r.IsBreaking
-		if existing != nil && existing.HeadSHA != "" && existing.HeadSHA == r.HeadSHA && existing.Summary != "" {
-			summary = existing.Summary
-			isBreaking = existing.IsBreaking
-		}
-
 		contributorsJSON, _ := json.Marshal(r.Contributors)
 		infraChangesJSON, _ := json.Marshal(r.InfraChanges)
+		apiChangesJSON, _ := json.Marshal(r.APIChanges)
+		configChangesJSON, _ := json.Marshal(r.ConfigChanges)
+		riskReasonsJSON, _ := json.Marshal(r.RiskReasons)
 
 		if existing != nil {
 			updates := map[string]interface{}{
@@ -294,10 +518,17 @@ func (s *Service) RefreshRepos(ctx context.Context, releaseID string, repos []Re
 				"pr_number":        r.PRNumber,
 				"pr_url":           r.PRURL,
 				"pr_merged":        r.PRMerged,
-				"summary":          summary,
-				"is_breaking":      isBreaking,
+				"summary":          r.Summary,
+				"is_breaking":      r.IsBreaking,
+				"risk_level":       r.RiskLevel,
+				"has_migration":    r.HasMigration,
+				"api_changes":      string(apiChangesJSON),
+				"config_changes":   string(configChangesJSON),
+				"rollout_notes":    r.RolloutNotes,
+				"risk_reasons":     string(riskReasonsJSON),
 				"merge_commit_sha": r.MergeCommitSHA,
 				"head_sha":         r.HeadSHA,
+				"source_ref":       r.SourceRef,
 				"contributors":     string(contributorsJSON),
 				"infra_changes":    string(infraChangesJSON),
 			}
@@ -314,10 +545,17 @@ func (s *Service) RefreshRepos(ctx context.Context, releaseID string, repos []Re
 				PRNumber:       r.PRNumber,
 				PRURL:          r.PRURL,
 				PRMerged:       r.PRMerged,
-				Summary:        summary,
-				IsBreaking:     isBreaking,
+				Summary:        r.Summary,
+				IsBreaking:     r.IsBreaking,
+				RiskLevel:      r.RiskLevel,
+				HasMigration:   r.HasMigration,
+				APIChanges:     string(apiChangesJSON),
+				ConfigChanges:  string(configChangesJSON),
+				RolloutNotes:   r.RolloutNotes,
+				RiskReasons:    string(riskReasonsJSON),
 				MergeCommitSHA: r.MergeCommitSHA,
 				HeadSHA:        r.HeadSHA,
+				SourceRef:      r.SourceRef,
 				Contributors:   string(contributorsJSON),
 				InfraChanges:   string(infraChangesJSON),
 			}
@@ -346,21 +584,11 @@ func (s *Service) RefreshRepos(ctx context.Context, releaseID string, repos []Re
 	return nil
 }
 
-func (s *Service) DeclineRelease(ctx context.Context, id, userID string) error {
-	updates := map[string]interface{}{
-		"status":          "declined",
-		"declined_by":     userID,
-		"declined_at":     time.Now().Unix(),
-		"dev_approved_by": "",
-		"dev_approved_at": 0,
-		"qa_approved_by":  "",
-		"qa_approved_at":  0,
-	}
-
-	if err := s.db.WithContext(ctx).Model(&database.Release{}).Where("id = ?", id).Updates(updates).Error; err != nil {
-		return fmt.Errorf("declining release: %w", err)
-	}
-	return nil
+// DeclineRelease moves the release to declined, clearing its approvals.
+// details, such as the reason, go into the status_changed history entry.
+func (s *Service) DeclineRelease(ctx context.Context, id, userID string, details map[string]any) error {
+	_, err := s.TransitionRelease(ctx, id, StatusDeclined, userID, details)
+	return err
 }
 
 func (s *Service) GetUserByEmail(ctx context.Context, email string) (*database.User, error) {
@@ -422,6 +650,80 @@ func (s *Service) CreateOrUpdateUser(ctx context.Context, email, githubUser, mat
 	return &user, nil
 }
 
+func (s *Service) GetUserByMattermost(ctx context.Context, mattermostUser string) (*database.User, error) {
+	var user database.User
+	err := s.db.WithContext(ctx).
+		Where("mattermost_user = ?", mattermostUser).
+		Order("updated_at DESC").
+		First(&user).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting user by mattermost username: %w", err)
+	}
+	return &user, nil
+}
+
+// LinkGitHubAccount stores the GitHub login for a Mattermost user. The user
+// row is found by Mattermost username, then by email; when Mattermost does not
+// expose the email a placeholder keyed by username is used, since emails are
+// unique.
+func (s *Service) LinkGitHubAccount(ctx context.Context, mattermostUser, email, githubUser string) (*database.User, error) {
+	var owner database.User
+	err := s.db.WithContext(ctx).
+		Where("LOWER(git_hub_user) = LOWER(?) AND mattermost_user != ? AND mattermost_user != ''", githubUser, mattermostUser).
+		First(&owner).Error
+	if err == nil {
+		return nil, ErrGitHubAlreadyLinked
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("checking existing link: %w", err)
+	}
+
+	existing, err := s.GetUserByMattermost(ctx, mattermostUser)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return s.CreateOrUpdateUser(ctx, existing.Email, githubUser, mattermostUser)
+	}
+
+	if email == "" {
+		email = "mattermost:" + mattermostUser
+	}
+	return s.CreateOrUpdateUser(ctx, email, githubUser, mattermostUser)
+}
+
+// GitHubForMattermost and MattermostForGitHub implement mappings.Store.
+func (s *Service) GitHubForMattermost(ctx context.Context, mmUsername string) (string, bool, error) {
+	var user database.User
+	err := s.db.WithContext(ctx).Where("mattermost_user = ? AND git_hub_user != ''", mmUsername).
+		Order("updated_at DESC").
+		First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("looking up GitHub account: %w", err)
+	}
+	return user.GitHubUser, true, nil
+}
+
+func (s *Service) MattermostForGitHub(ctx context.Context, ghUsername string) (string, bool, error) {
+	var user database.User
+	err := s.db.WithContext(ctx).Where("LOWER(git_hub_user) = LOWER(?) AND mattermost_user != ''", ghUsername).
+		Order("updated_at DESC").
+		First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("looking up Mattermost account: %w", err)
+	}
+	return user.MattermostUser, true, nil
+}
+
 func (s *Service) GetRepo(ctx context.Context, repoID uint) (*database.ReleaseRepo, error) {
 	var repo database.ReleaseRepo
 	if err := s.db.WithContext(ctx).First(&repo, "id = ?", repoID).Error; err != nil {
@@ -543,14 +845,7 @@ type PendingAction struct {
 func (s *Service) GetPendingActions(ctx context.Context, releaseWithRepos *ReleaseWithRepos) []PendingAction {
 	var actions []PendingAction
 
-	githubToMattermost := make(map[string]string)
-	var users []database.User
-	s.db.WithContext(ctx).Find(&users)
-	for _, u := range users {
-		if u.GitHubUser != "" {
-			githubToMattermost[u.GitHubUser] = u.MattermostUser
-		}
-	}
+	githubToMattermost := s.mattermostUsersByGitHub(ctx)
 
 	for _, repo := range releaseWithRepos.Repos {
 		if repo.Excluded {
@@ -589,6 +884,18 @@ func (s *Service) GetPendingActions(ctx context.Context, releaseWithRepos *Relea
 	return actions
 }
 
+func (s *Service) mattermostUsersByGitHub(ctx context.Context) map[string]string {
+	githubToMattermost := make(map[string]string)
+	var users []database.User
+	s.db.WithContext(ctx).Find(&users)
+	for _, u := range users {
+		if u.GitHubUser != "" {
+			githubToMattermost[u.GitHubUser] = u.MattermostUser
+		}
+	}
+	return githubToMattermost
+}
+
 func (s *Service) RecordHistory(ctx context.Context, releaseID, action, actor string, details map[string]any) error {
 	var detailsJSON string
 	if details != nil {
@@ -762,10 +1069,14 @@ func (s *Service) GetDeploymentStatusByRepoIDAndEnv(ctx context.Context, release
 	return &status, nil
 }
 
+// GetReposWithSuccessfulCI returns the included repos with a chart version
+// built, of releases that are not deployed yet or finished.
 func (s *Service) GetReposWithSuccessfulCI(ctx context.Context) ([]database.ReleaseRepo, error) {
 	var repos []database.ReleaseRepo
 	err := s.db.WithContext(ctx).
 		Joins("INNER JOIN repo_ci_statuses ON repo_ci_statuses.release_repo_id = release_repos.id").
+		Joins("INNER JOIN releases ON releases.id = release_repos.release_id").
+		Where("releases.status NOT IN ?", append(FinalStatuses(), StatusDeployed)).
 		Where("repo_ci_statuses.status = ?", "success").
 		Where("repo_ci_statuses.chart_version != ''").
 		Where("release_repos.excluded = ?", false).
@@ -10,12 +10,13 @@ import (
 
 	"github.com/user/mattermost-tools/internal/dashboard"
 	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/summarizer"
 )
 
 func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
-	require.NoError(t, db.AutoMigrate(&database.Release{}, &database.ReleaseRepo{}))
+	require.NoError(t, db.AutoMigrate(&database.Release{}, &database.ReleaseRepo{}, &database.ReleaseHistory{}, &database.FreezeWindow{}, &database.FreezeOverride{}))
 	return db
 }
 
@@ -205,6 +206,59 @@ func TestService_GetReleaseWithRepos_Success(t *testing.T) {
 	require.Equal(t, "https://github.com/org/auth-service/pull/123", releaseWithRepos.Repos[0].PRURL)
 }
 
+func TestService_RefreshRepos_StructuredSummary(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	release, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{
+		SourceBranch: "uat",
+		DestBranch:   "master",
+		CreatedBy:    "user123",
+		ChannelID:    "channel456",
+	})
+	require.NoError(t, err)
+
+	var repo dashboard.RepoData
+	repo.RepoName = "auth-service"
+	repo.SetSummary(summarizer.Result{
+		Summary:       "Adds SSO.",
+		RiskLevel:     summarizer.RiskMedium,
+		HasMigration:  true,
+		ConfigChanges: []string{"SSO_ISSUER"},
+		RolloutNotes:  "Set SSO_ISSUER first.",
+	})
+	require.NoError(t, svc.AddRepos(ctx, release.ID, []dashboard.RepoData{repo}))
+
+	repo.SetSummary(summarizer.Result{
+		Summary:    "Adds SSO and drops v1 login.",
+		IsBreaking: true,
+		RiskLevel:  summarizer.RiskHigh,
+		APIChanges: []string{"DELETE /v1/login"},
+		Reasons:    []string{"commit abc1234 is marked breaking: feat!: drop v1 login"},
+	})
+	require.NoError(t, svc.RefreshRepos(ctx, release.ID, []dashboard.RepoData{repo}))
+
+	releaseWithRepos, err := svc.GetReleaseWithRepos(ctx, release.ID)
+	require.NoError(t, err)
+	require.Len(t, releaseWithRepos.Repos, 1)
+	stored := releaseWithRepos.Repos[0]
+	require.Equal(t, "Adds SSO and drops v1 login.", stored.Summary)
+	require.True(t, stored.IsBreaking)
+	require.Equal(t, summarizer.RiskHigh, stored.RiskLevel)
+	require.False(t, stored.HasMigration)
+	require.Empty(t, stored.RolloutNotes)
+	apiChanges, err := stored.GetAPIChanges()
+	require.NoError(t, err)
+	require.Equal(t, []string{"DELETE /v1/login"}, apiChanges)
+	configChanges, err := stored.GetConfigChanges()
+	require.NoError(t, err)
+	require.Empty(t, configChanges)
+	reasons, err := stored.GetRiskReasons()
+	require.NoError(t, err)
+	require.Equal(t, []string{"commit abc1234 is marked breaking: feat!: drop v1 login"}, reasons)
+}
+
 func TestService_CreateRelease_Success(t *testing.T) {
 	type tc struct {
 		name         string
@@ -511,7 +565,7 @@ func TestService_RevokeApproval_Dev_Success(t *testing.T) {
 	err = svc.ApproveRelease(context.Background(), release.ID, "qa", "qalead")
 	require.NoError(t, err)
 
-	err = svc.RevokeApproval(context.Background(), release.ID, "dev")
+	err = svc.RevokeApproval(context.Background(), release.ID, "dev", "devlead")
 
 	require.NoError(t, err)
 
@@ -539,7 +593,7 @@ func TestService_RevokeApproval_QA_Success(t *testing.T) {
 	err = svc.ApproveRelease(context.Background(), release.ID, "qa", "qalead")
 	require.NoError(t, err)
 
-	err = svc.RevokeApproval(context.Background(), release.ID, "qa")
+	err = svc.RevokeApproval(context.Background(), release.ID, "qa", "devlead")
 
 	require.NoError(t, err)
 
@@ -562,8 +616,227 @@ func TestService_RevokeApproval_InvalidType_Failure(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	err = svc.RevokeApproval(context.Background(), release.ID, "invalid")
+	err = svc.RevokeApproval(context.Background(), release.ID, "invalid", "devlead")
 
 	require.Error(t, err)
 	require.ErrorContains(t, err, "invalid approval type")
 }
+
+func TestService_FindReleaseForChannel(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	older, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{
+		SourceBranch: "uat",
+		DestBranch:   "master",
+		CreatedBy:    "user123",
+		ChannelID:    "channel456",
+	})
+	require.NoError(t, err)
+	require.NoError(t, svc.SetMattermostPostID(ctx, older.ID, "thread1"))
+	require.NoError(t, db.Model(&database.Release{}).Where("id = ?", older.ID).Update("created_at", 100).Error)
+
+	newer, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{
+		SourceBranch: "develop",
+		DestBranch:   "uat",
+		CreatedBy:    "user123",
+		ChannelID:    "channel456",
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&database.Release{}).Where("id = ?", newer.ID).Update("created_at", 200).Error)
+
+	byThread, err := svc.FindReleaseForChannel(ctx, "channel456", "thread1")
+	require.NoError(t, err)
+	require.Equal(t, older.ID, byThread.ID)
+
+	byChannel, err := svc.FindReleaseForChannel(ctx, "channel456", "other-thread")
+	require.NoError(t, err)
+	require.Equal(t, newer.ID, byChannel.ID)
+
+	require.NoError(t, svc.DeclineRelease(ctx, newer.ID, "qa-lead", nil))
+	afterDecline, err := svc.FindReleaseForChannel(ctx, "channel456", "")
+	require.NoError(t, err)
+	require.Equal(t, older.ID, afterDecline.ID)
+
+	_, err = svc.FindReleaseForChannel(ctx, "unknown-channel", "")
+	require.ErrorIs(t, err, dashboard.ErrReleaseNotFound)
+}
+
+func TestService_LinkGitHubAccount(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&database.User{}))
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	_, err := svc.CreateOrUpdateUser(ctx, "alice@example.com", "", "alice")
+	require.NoError(t, err)
+
+	linked, err := svc.LinkGitHubAccount(ctx, "alice", "", "Alice-GH")
+	require.NoError(t, err)
+	require.Equal(t, "alice@example.com", linked.Email)
+	require.Equal(t, "Alice-GH", linked.GitHubUser)
+
+	noEmail, err := svc.LinkGitHubAccount(ctx, "bob", "", "bob-gh")
+	require.NoError(t, err)
+	require.Equal(t, "mattermost:bob", noEmail.Email)
+
+	_, err = svc.LinkGitHubAccount(ctx, "mallory", "mallory@example.com", "alice-gh")
+	require.ErrorIs(t, err, dashboard.ErrGitHubAlreadyLinked)
+
+	gh, ok, err := svc.GitHubForMattermost(ctx, "alice")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "Alice-GH", gh)
+
+	mm, ok, err := svc.MattermostForGitHub(ctx, "alice-gh")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "alice", mm)
+
+	_, ok, err = svc.GitHubForMattermost(ctx, "mallory")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestService_CreateRelease_Hotfix(t *testing.T) {
+	type tc struct {
+		name        string
+		hotfixRepos []database.HotfixRepo
+		expectedErr error
+	}
+
+	cases := []tc{
+		{
+			name:        "repos with refs",
+			hotfixRepos: []database.HotfixRepo{{Repo: "auth-service", Ref: "fix/token"}, {Repo: "billing", Ref: "v1.4.2"}},
+		},
+		{
+			name:        "empty repo list",
+			hotfixRepos: []database.HotfixRepo{},
+			expectedErr: dashboard.ErrInvalidHotfix,
+		},
+		{
+			name:        "missing ref",
+			hotfixRepos: []database.HotfixRepo{{Repo: "auth-service"}},
+			expectedErr: dashboard.ErrInvalidHotfix,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			svc := dashboard.NewService(db)
+
+			release, err := svc.CreateRelease(context.Background(), dashboard.CreateReleaseRequest{
+				DestBranch:  "master",
+				CreatedBy:   "user123",
+				ChannelID:   "channel456",
+				HotfixRepos: c.hotfixRepos,
+			})
+
+			if c.expectedErr != nil {
+				require.ErrorIs(t, err, c.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, release.IsHotfix)
+			require.Equal(t, "hotfix", release.SourceBranch)
+
+			stored, err := svc.GetRelease(context.Background(), release.ID)
+			require.NoError(t, err)
+			repos, err := stored.GetHotfixRepos()
+			require.NoError(t, err)
+			require.Equal(t, c.hotfixRepos, repos)
+		})
+	}
+}
+
+func TestService_ApproveRelease_HotfixPolicy(t *testing.T) {
+	type tc struct {
+		name            string
+		hotfixApprovals []string
+		approve         []string
+		expectedStatus  string
+	}
+
+	cases := []tc{
+		{
+			name:           "default needs dev only",
+			approve:        []string{"dev"},
+			expectedStatus: "approved",
+		},
+		{
+			name:           "qa alone is not enough by default",
+			approve:        []string{"qa"},
+			expectedStatus: "pending",
+		},
+		{
+			name:            "configured to need qa",
+			hotfixApprovals: []string{"qa"},
+			approve:         []string{"qa"},
+			expectedStatus:  "approved",
+		},
+		{
+			name:            "configured to need both",
+			hotfixApprovals: []string{"dev", "qa"},
+			approve:         []string{"dev"},
+			expectedStatus:  "pending",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			svc := dashboard.NewService(db)
+			svc.SetHotfixApprovals(c.hotfixApprovals)
+
+			release, err := svc.CreateRelease(context.Background(), dashboard.CreateReleaseRequest{
+				DestBranch:  "master",
+				CreatedBy:   "user123",
+				ChannelID:   "channel456",
+				HotfixRepos: []database.HotfixRepo{{Repo: "auth-service", Ref: "fix/token"}},
+			})
+			require.NoError(t, err)
+
+			for _, approvalType := range c.approve {
+				require.NoError(t, svc.ApproveRelease(context.Background(), release.ID, approvalType, "lead"))
+			}
+
+			updated, err := svc.GetRelease(context.Background(), release.ID)
+			require.NoError(t, err)
+			require.Equal(t, c.expectedStatus, updated.Status)
+		})
+	}
+}
+
+func TestService_ListReleasesFiltered_Type(t *testing.T) {
+	db := setupTestDB(t)
+	svc := dashboard.NewService(db)
+	ctx := context.Background()
+
+	_, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{
+		SourceBranch: "uat",
+		DestBranch:   "master",
+		CreatedBy:    "user123",
+		ChannelID:    "channel456",
+	})
+	require.NoError(t, err)
+	hotfix, err := svc.CreateRelease(ctx, dashboard.CreateReleaseRequest{
+		DestBranch:  "master",
+		CreatedBy:   "user123",
+		ChannelID:   "channel456",
+		HotfixRepos: []database.HotfixRepo{{Repo: "auth-service", Ref: "abc1234"}},
+	})
+	require.NoError(t, err)
+
+	hotfixes, err := svc.ListReleasesFiltered(ctx, dashboard.ListReleasesOptions{Type: "hotfix"})
+	require.NoError(t, err)
+	require.Len(t, hotfixes, 1)
+	require.Equal(t, hotfix.ID, hotfixes[0].ID)
+
+	regular, err := svc.ListReleasesFiltered(ctx, dashboard.ListReleasesOptions{Type: "regular"})
+	require.NoError(t, err)
+	require.Len(t, regular, 1)
+	require.False(t, regular[0].IsHotfix)
+}
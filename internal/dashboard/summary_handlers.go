@@ -0,0 +1,47 @@
+package dashboard
+
+import (
+	"net/http"
+
+	"github.com/user/mattermost-tools/internal/permissions"
+)
+
+// SummariesPermission is the permission rule for invalidating cached
+// change summaries.
+const SummariesPermission = "summaries"
+
+// InvalidateSummaries deletes cached summaries so they are regenerated on
+// the next refresh. The optional repo and head_sha query parameters narrow
+// what is deleted. Clearing the whole cache needs an explicit allow rule;
+// the default policy only covers a single repo.
+func (h *Handlers) InvalidateSummaries(w http.ResponseWriter, r *http.Request) {
+	if h.summaryCache == nil {
+		http.Error(w, "summary cache not configured", http.StatusInternalServerError)
+		return
+	}
+
+	repo := r.URL.Query().Get("repo")
+	if h.auth != nil {
+		user := h.auth.GetUser(r)
+		if user == nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		subject := permissions.Subject{UserName: user.Username}
+		allowed := h.permissions.Allowed(r.Context(), SummariesPermission, subject)
+		if repo == "" {
+			allowed = h.permissions.Granted(r.Context(), SummariesPermission, subject)
+		}
+		if !allowed {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+	}
+
+	deleted, err := h.summaryCache.Invalidate(r.Context(), repo, r.URL.Query().Get("head_sha"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, map[string]any{"status": "ok", "deleted": deleted})
+}
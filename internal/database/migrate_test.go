@@ -0,0 +1,109 @@
+package database_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/database"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "releases.db"))
+	require.NoError(t, err)
+	return db
+}
+
+func schema(t *testing.T, db *gorm.DB) map[string]string {
+	t.Helper()
+	var rows []struct{ Name, SQL string }
+	require.NoError(t, db.Raw("SELECT name, sql FROM sqlite_master WHERE sql IS NOT NULL AND name NOT LIKE '%schema_migrations%'").Scan(&rows).Error)
+	result := make(map[string]string, len(rows))
+	for _, r := range rows {
+		result[r.Name] = r.SQL
+	}
+	return result
+}
+
+// Every model change needs a migration: the migrated schema must match what
+// the models describe.
+func TestMigrate_MatchesModels(t *testing.T) {
+	migrated := openTestDB(t)
+	_, err := database.Migrate(migrated)
+	require.NoError(t, err)
+
+	models := openTestDB(t)
+	require.NoError(t, models.AutoMigrate(&database.Release{}, &database.ReleaseRepo{}, &database.User{}, &database.ReleaseHistory{},
+		&database.RepoCIStatus{}, &database.RepoDeploymentStatus{}, &database.ScheduledJobRun{}, &database.Job{},
+		&database.FreezeWindow{}, &database.FreezeOverride{}, &database.Summary{}))
+
+	require.Equal(t, schema(t, models), schema(t, migrated))
+}
+
+func TestMigrate_AdoptsExistingDatabase(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.Exec("CREATE TABLE `releases` (`id` text,`source_branch` text NOT NULL,`dest_branch` text NOT NULL,`status` text DEFAULT \"pending\",`created_by` text NOT NULL,`channel_id` text NOT NULL,`created_at` integer NOT NULL,PRIMARY KEY (`id`))").Error)
+	require.NoError(t, db.Exec("INSERT INTO releases (id, source_branch, dest_branch, created_by, channel_id, created_at) VALUES ('rel-1', 'uat', 'master', 'alice', 'chan', 1)").Error)
+
+	applied, err := database.Migrate(db)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+
+	var release database.Release
+	require.NoError(t, db.First(&release, "id = ?", "rel-1").Error)
+	require.Equal(t, "pending", release.Status)
+	require.True(t, db.Migrator().HasColumn(&database.Release{}, "deployed_at"))
+
+	version, err := database.SchemaVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, database.LatestVersion(), version)
+
+	applied, err = database.Migrate(db)
+	require.NoError(t, err)
+	require.Empty(t, applied)
+}
+
+func TestRollback_RefusesBaseline(t *testing.T) {
+	db := openTestDB(t)
+	_, err := database.Migrate(db)
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&database.Release{ID: "rel-1", SourceBranch: "uat", DestBranch: "master", CreatedBy: "alice", ChannelID: "chan"}).Error)
+
+	reverted, err := database.Rollback(db, 1)
+	require.ErrorIs(t, err, database.ErrIrreversible)
+	require.Empty(t, reverted)
+
+	version, err := database.SchemaVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+
+	var count int64
+	require.NoError(t, db.Model(&database.Release{}).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+
+	statuses, err := database.Status(db)
+	require.NoError(t, err)
+	require.Len(t, statuses, len(database.Migrations()))
+	require.NotZero(t, statuses[0].AppliedAt)
+}
+
+func TestCheckSchema_TooNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "releases.db")
+	db, err := database.NewSQLiteDB(path)
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&database.SchemaMigration{Version: database.LatestVersion() + 1, Name: "from_the_future", AppliedAt: 1}).Error)
+
+	_, err = database.NewSQLiteDB(path)
+	require.ErrorIs(t, err, database.ErrSchemaTooNew)
+	_, err = database.Rollback(db, 1)
+	require.ErrorIs(t, err, database.ErrSchemaTooNew)
+
+	statuses, err := database.Status(db)
+	require.NoError(t, err)
+	last := statuses[len(statuses)-1]
+	require.True(t, last.Unknown)
+	require.Equal(t, "from_the_future", last.Name)
+}
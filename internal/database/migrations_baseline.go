@@ -0,0 +1,208 @@
+package database
+
+import "gorm.io/gorm"
+
+// baselineMigration creates the schema as GORM AutoMigrate last left it. On a
+// database created before versioned migrations it only adds what is missing,
+// so existing databases are adopted at version 1. It has no Down: reverting it
+// would drop every table, including data that predates migrations.
+var baselineMigration = Migration{
+	Version: 1,
+	Name:    "baseline",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(baselineModels()...)
+	},
+}
+
+func baselineModels() []any {
+	return []any{
+		&baselineRelease{}, &baselineReleaseRepo{}, &baselineUser{}, &baselineReleaseHistory{},
+		&baselineRepoCIStatus{}, &baselineRepoDeploymentStatus{}, &baselineScheduledJobRun{},
+		&baselineJob{}, &baselineFreezeWindow{}, &baselineFreezeOverride{}, &baselineSummary{},
+	}
+}
+
+// The baseline types are a frozen copy of the models at version 1; later
+// schema changes are new migrations, not edits here.
+
+type baselineRelease struct {
+	ID               string `gorm:"primaryKey"`
+	SourceBranch     string `gorm:"not null"`
+	DestBranch       string `gorm:"not null"`
+	Status           string `gorm:"default:pending"`
+	Notes            string
+	BreakingChanges  string
+	CreatedBy        string `gorm:"not null"`
+	ChannelID        string `gorm:"not null"`
+	DedicatedChannel bool   `gorm:"default:false"`
+	IsHotfix         bool   `gorm:"default:false"`
+	HotfixRepos      string
+	MattermostPostID string
+	DevApprovedBy    string
+	DevApprovedAt    int64
+	QAApprovedBy     string
+	QAApprovedAt     int64
+	DeclinedBy       string
+	DeclinedAt       int64
+	StatusChangedBy  string
+	StatusChangedAt  int64
+	DeployedAt       int64
+	LastRefreshedAt  int64
+	CreatedAt        int64 `gorm:"not null"`
+}
+
+func (baselineRelease) TableName() string { return "releases" }
+
+type baselineReleaseRepo struct {
+	ID             uint   `gorm:"primaryKey;autoIncrement"`
+	ReleaseID      string `gorm:"not null;index"`
+	RepoName       string `gorm:"not null"`
+	CommitCount    int    `gorm:"default:0"`
+	Additions      int    `gorm:"default:0"`
+	Deletions      int    `gorm:"default:0"`
+	Contributors   string
+	PRNumber       int
+	PRURL          string
+	PRMerged       bool `gorm:"default:false"`
+	Excluded       bool `gorm:"default:false"`
+	DependsOn      string
+	Summary        string
+	IsBreaking     bool `gorm:"default:false"`
+	ConfirmedBy    string
+	ConfirmedAt    int64
+	InfraChanges   string
+	MergeCommitSHA string
+	HeadSHA        string
+	SourceRef      string
+	RiskLevel      string
+	HasMigration   bool `gorm:"default:false"`
+	APIChanges     string
+	ConfigChanges  string
+	RolloutNotes   string
+	RiskReasons    string
+}
+
+func (baselineReleaseRepo) TableName() string { return "release_repos" }
+
+type baselineUser struct {
+	ID             uint   `gorm:"primaryKey;autoIncrement"`
+	Email          string `gorm:"uniqueIndex"`
+	MattermostUser string `gorm:"index"`
+	GitHubUser     string `gorm:"index"`
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineReleaseHistory struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	ReleaseID string `gorm:"not null;index"`
+	Action    string `gorm:"not null"`
+	Actor     string
+	Details   string
+	CreatedAt int64 `gorm:"not null;index"`
+}
+
+func (baselineReleaseHistory) TableName() string { return "release_histories" }
+
+type baselineRepoCIStatus struct {
+	ID             uint `gorm:"primaryKey;autoIncrement"`
+	ReleaseRepoID  uint `gorm:"uniqueIndex;not null"`
+	WorkflowRunID  int64
+	WorkflowRunNum int
+	WorkflowURL    string
+	Status         string `gorm:"default:pending"`
+	ChartName      string
+	ChartVersion   string
+	MergeCommitSHA string
+	StartedAt      int64
+	CompletedAt    int64
+	LastCheckedAt  int64
+}
+
+func (baselineRepoCIStatus) TableName() string { return "repo_ci_statuses" }
+
+type baselineRepoDeploymentStatus struct {
+	ID              uint   `gorm:"primaryKey;autoIncrement"`
+	ReleaseRepoID   uint   `gorm:"uniqueIndex:idx_repo_env;not null"`
+	Environment     string `gorm:"uniqueIndex:idx_repo_env;not null"`
+	AppName         string
+	CurrentVersion  string
+	ExpectedVersion string
+	SyncStatus      string
+	HealthStatus    string
+	RolloutStatus   string
+	LastCheckedAt   int64
+}
+
+func (baselineRepoDeploymentStatus) TableName() string { return "repo_deployment_statuses" }
+
+type baselineScheduledJobRun struct {
+	JobName   string `gorm:"primaryKey"`
+	LastRunAt int64
+}
+
+func (baselineScheduledJobRun) TableName() string { return "scheduled_job_runs" }
+
+type baselineJob struct {
+	ID            string `gorm:"primaryKey"`
+	Type          string `gorm:"not null;index"`
+	Status        string `gorm:"not null;index"`
+	Args          string
+	RequestedBy   string `gorm:"index"`
+	ChannelID     string
+	ThreadID      string
+	ProgressDone  int
+	ProgressTotal int
+	Error         string
+	Attempts      int
+	MaxAttempts   int
+	RunAfter      int64
+	CancelledBy   string
+	CreatedAt     int64
+	StartedAt     int64
+	FinishedAt    int64
+}
+
+func (baselineJob) TableName() string { return "jobs" }
+
+type baselineFreezeWindow struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	Reason    string `gorm:"not null"`
+	Owner     string
+	Source    string `gorm:"not null;default:api;index"`
+	StartsAt  int64  `gorm:"not null;index"`
+	EndsAt    int64  `gorm:"not null;index"`
+	CreatedAt int64
+}
+
+func (baselineFreezeWindow) TableName() string { return "freeze_windows" }
+
+type baselineFreezeOverride struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	FreezeID  uint   `gorm:"not null;uniqueIndex:idx_freeze_release"`
+	ReleaseID string `gorm:"not null;uniqueIndex:idx_freeze_release"`
+	Actor     string
+	CreatedAt int64
+}
+
+func (baselineFreezeOverride) TableName() string { return "freeze_overrides" }
+
+type baselineSummary struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement"`
+	Repo          string `gorm:"not null;uniqueIndex:idx_summary_key"`
+	BaseSHA       string `gorm:"not null;uniqueIndex:idx_summary_key"`
+	HeadSHA       string `gorm:"not null;uniqueIndex:idx_summary_key;index"`
+	Version       string `gorm:"not null;uniqueIndex:idx_summary_key"`
+	Summary       string
+	IsBreaking    bool
+	RiskLevel     string
+	HasMigration  bool
+	APIChanges    string
+	ConfigChanges string
+	RolloutNotes  string
+	CreatedAt     int64 `gorm:"not null;index"`
+}
+
+func (baselineSummary) TableName() string { return "summaries" }
@@ -14,6 +14,9 @@ type Release struct {
 	BreakingChanges  string
 	CreatedBy        string `gorm:"not null"`
 	ChannelID        string `gorm:"not null"`
+	DedicatedChannel bool   `gorm:"default:false"`
+	IsHotfix         bool   `gorm:"default:false"`
+	HotfixRepos      string
 	MattermostPostID string
 	DevApprovedBy    string
 	DevApprovedAt    int64
@@ -21,6 +24,9 @@ type Release struct {
 	QAApprovedAt     int64
 	DeclinedBy       string
 	DeclinedAt       int64
+	StatusChangedBy  string
+	StatusChangedAt  int64
+	DeployedAt       int64
 	LastRefreshedAt  int64
 	CreatedAt        int64 `gorm:"not null"`
 }
@@ -45,6 +51,40 @@ type ReleaseRepo struct {
 	InfraChanges   string
 	MergeCommitSHA string
 	HeadSHA        string
+	SourceRef      string
+	RiskLevel      string
+	HasMigration   bool `gorm:"default:false"`
+	APIChanges     string
+	ConfigChanges  string
+	RolloutNotes   string
+	RiskReasons    string
+}
+
+// HotfixRepo is a repo picked for a hotfix release and the branch, tag or SHA
+// to release it from.
+type HotfixRepo struct {
+	Repo string `json:"repo"`
+	Ref  string `json:"ref"`
+}
+
+func (r *Release) GetHotfixRepos() ([]HotfixRepo, error) {
+	if r.HotfixRepos == "" {
+		return nil, nil
+	}
+	var repos []HotfixRepo
+	if err := json.Unmarshal([]byte(r.HotfixRepos), &repos); err != nil {
+		return nil, fmt.Errorf("unmarshaling hotfix_repos: %w", err)
+	}
+	return repos, nil
+}
+
+func (r *Release) SetHotfixRepos(repos []HotfixRepo) error {
+	data, err := json.Marshal(repos)
+	if err != nil {
+		return fmt.Errorf("marshaling hotfix_repos: %w", err)
+	}
+	r.HotfixRepos = string(data)
+	return nil
 }
 
 func (r *ReleaseRepo) GetContributors() ([]string, error) {
@@ -127,6 +167,66 @@ func (r *ReleaseRepo) SetInfraChanges(infraChanges []string) error {
 	return nil
 }
 
+func (r *ReleaseRepo) GetAPIChanges() ([]string, error) {
+	if r.APIChanges == "" {
+		return nil, nil
+	}
+	var apiChanges []string
+	if err := json.Unmarshal([]byte(r.APIChanges), &apiChanges); err != nil {
+		return nil, fmt.Errorf("unmarshaling api_changes: %w", err)
+	}
+	return apiChanges, nil
+}
+
+func (r *ReleaseRepo) SetAPIChanges(apiChanges []string) error {
+	data, err := json.Marshal(apiChanges)
+	if err != nil {
+		return fmt.Errorf("marshaling api_changes: %w", err)
+	}
+	r.APIChanges = string(data)
+	return nil
+}
+
+func (r *ReleaseRepo) GetConfigChanges() ([]string, error) {
+	if r.ConfigChanges == "" {
+		return nil, nil
+	}
+	var configChanges []string
+	if err := json.Unmarshal([]byte(r.ConfigChanges), &configChanges); err != nil {
+		return nil, fmt.Errorf("unmarshaling config_changes: %w", err)
+	}
+	return configChanges, nil
+}
+
+func (r *ReleaseRepo) SetConfigChanges(configChanges []string) error {
+	data, err := json.Marshal(configChanges)
+	if err != nil {
+		return fmt.Errorf("marshaling config_changes: %w", err)
+	}
+	r.ConfigChanges = string(data)
+	return nil
+}
+
+func (r *ReleaseRepo) GetRiskReasons() ([]string, error) {
+	if r.RiskReasons == "" {
+		return nil, nil
+	}
+	var reasons []string
+	if err := json.Unmarshal([]byte(r.RiskReasons), &reasons); err != nil {
+		return nil, fmt.Errorf("unmarshaling risk_reasons: %w", err)
+	}
+	return reasons, nil
+}
+
+func (r *ReleaseRepo) SetRiskReasons(reasons []string) error {
+	data, err := json.Marshal(reasons)
+	if err != nil {
+		return fmt.Errorf("marshaling risk_reasons: %w", err)
+	}
+	r.RiskReasons = string(data)
+	return nil
+}
+
 type User struct {
 	ID             uint   `gorm:"primaryKey;autoIncrement"`
 	Email          string `gorm:"uniqueIndex"`
@@ -176,3 +276,88 @@ type RepoDeploymentStatus struct {
 func (RepoDeploymentStatus) TableName() string {
 	return "repo_deployment_statuses"
 }
+
+type ScheduledJobRun struct {
+	JobName   string `gorm:"primaryKey"`
+	LastRunAt int64
+}
+
+type Job struct {
+	ID            string `gorm:"primaryKey"`
+	Type          string `gorm:"not null;index"`
+	Status        string `gorm:"not null;index"`
+	Args          string
+	RequestedBy   string `gorm:"index"`
+	ChannelID     string
+	ThreadID      string
+	ProgressDone  int
+	ProgressTotal int
+	Error         string
+	Attempts      int
+	MaxAttempts   int
+	RunAfter      int64
+	CancelledBy   string
+	CreatedAt     int64
+	StartedAt     int64
+	FinishedAt    int64
+}
+
+func (j *Job) GetArgs(v any) error {
+	if j.Args == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(j.Args), v); err != nil {
+		return fmt.Errorf("unmarshaling job args: %w", err)
+	}
+	return nil
+}
+
+func (j *Job) SetArgs(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshaling job args: %w", err)
+	}
+	j.Args = string(data)
+	return nil
+}
+
+type FreezeWindow struct {
+	ID     uint   `gorm:"primaryKey;autoIncrement"`
+	Reason string `gorm:"not null"`
+	Owner  string
+	// Source is "config" for windows from the YAML file, which are replaced
+	// on every start, or "api" for ones added from the dashboard or chat.
+	Source    string `gorm:"not null;default:api;index"`
+	StartsAt  int64  `gorm:"not null;index"`
+	EndsAt    int64  `gorm:"not null;index"`
+	CreatedAt int64
+}
+
+// FreezeOverride lets a release's PRs be merged during a freeze window.
+type FreezeOverride struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	FreezeID  uint   `gorm:"not null;uniqueIndex:idx_freeze_release"`
+	ReleaseID string `gorm:"not null;uniqueIndex:idx_freeze_release"`
+	Actor     string
+	CreatedAt int64
+}
+
+// Summary caches a change summary for a comparison, shared by the bot, CLI
+// and dashboard.
+type Summary struct {
+	ID      uint   `gorm:"primaryKey;autoIncrement"`
+	Repo    string `gorm:"not null;uniqueIndex:idx_summary_key"`
+	BaseSHA string `gorm:"not null;uniqueIndex:idx_summary_key"`
+	HeadSHA string `gorm:"not null;uniqueIndex:idx_summary_key;index"`
+	// Version identifies the summarizer and prompt that produced the summary.
+	Version      string `gorm:"not null;uniqueIndex:idx_summary_key"`
+	Summary      string
+	IsBreaking   bool
+	RiskLevel    string
+	HasMigration bool
+	// APIChanges and ConfigChanges are JSON string arrays.
+	APIChanges    string
+	ConfigChanges string
+	RolloutNotes  string
+	CreatedAt     int64 `gorm:"not null;index"`
+}
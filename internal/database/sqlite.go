@@ -7,14 +7,25 @@ import (
 	"gorm.io/gorm"
 )
 
-func NewSQLiteDB(path string) (*gorm.DB, error) {
+// OpenSQLite opens the database without touching its schema.
+func OpenSQLite(path string) (*gorm.DB, error) {
 	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("opening sqlite database: %w", err)
 	}
+	return db, nil
+}
+
+// NewSQLiteDB opens the database and applies pending migrations. It fails with
+// ErrSchemaTooNew when a newer build has migrated the database.
+func NewSQLiteDB(path string) (*gorm.DB, error) {
+	db, err := OpenSQLite(path)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := db.AutoMigrate(&Release{}, &ReleaseRepo{}, &User{}, &ReleaseHistory{}, &RepoCIStatus{}, &RepoDeploymentStatus{}); err != nil {
-		return nil, fmt.Errorf("auto migrating: %w", err)
+	if _, err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("migrating database: %w", err)
 	}
 
 	return db, nil
@@ -0,0 +1,483 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/logger"
+)
+
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+
+	// CancelAnyPermission is the permissions rule key that lets a user cancel
+	// jobs requested by someone else. It must be granted by an allow rule; the
+	// default policy does not apply.
+	CancelAnyPermission = "cancel-any"
+
+	defaultWorkers      = 2
+	defaultPollInterval = 5 * time.Second
+	defaultRetryDelay   = 30 * time.Second
+)
+
+var (
+	ErrJobNotFound    = errors.New("job not found")
+	ErrAmbiguousID    = errors.New("job ID prefix matches several jobs")
+	ErrJobFinished    = errors.New("job already finished")
+	ErrUnknownJobType = errors.New("unknown job type")
+)
+
+// Handler runs one attempt of a job. It should return promptly once ctx is
+// cancelled and report progress through p.
+type Handler func(ctx context.Context, job *database.Job, p *Progress) error
+
+type registration struct {
+	handler     Handler
+	maxAttempts int
+}
+
+// Target is who asked for a job and where its results go.
+type Target struct {
+	RequestedBy string
+	ChannelID   string
+	ThreadID    string
+}
+
+// Manager runs jobs persisted in the jobs table on a fixed pool of workers.
+// Jobs left queued or running by a previous process are picked up again on
+// Start if they have attempts left; failed attempts are retried until the type's attempt limit.
+type Manager struct {
+	db           *gorm.DB
+	workers      int
+	pollInterval time.Duration
+	retryDelay   time.Duration
+	handlers     map[string]registration
+	wake         chan struct{}
+	wg           sync.WaitGroup
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func NewManager(db *gorm.DB, workers int) *Manager {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	return &Manager{
+		db:           db,
+		workers:      workers,
+		pollInterval: defaultPollInterval,
+		retryDelay:   defaultRetryDelay,
+		handlers:     make(map[string]registration),
+		wake:         make(chan struct{}, 1),
+		cancels:      make(map[string]context.CancelFunc),
+	}
+}
+
+func (m *Manager) SetRetryDelay(d time.Duration) {
+	m.retryDelay = d
+}
+
+func (m *Manager) SetPollInterval(d time.Duration) {
+	m.pollInterval = d
+}
+
+// Register sets the handler for a job type. maxAttempts below 1 means 1.
+func (m *Manager) Register(jobType string, maxAttempts int, handler Handler) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	m.handlers[jobType] = registration{handler: handler, maxAttempts: maxAttempts}
+}
+
+func (m *Manager) Enqueue(ctx context.Context, jobType string, target Target, args any) (*database.Job, error) {
+	reg, ok := m.handlers[jobType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobType, jobType)
+	}
+
+	job := &database.Job{
+		ID:          uuid.New().String(),
+		Type:        jobType,
+		Status:      StatusQueued,
+		RequestedBy: target.RequestedBy,
+		ChannelID:   target.ChannelID,
+		ThreadID:    target.ThreadID,
+		MaxAttempts: reg.maxAttempts,
+		CreatedAt:   time.Now().Unix(),
+	}
+	if args != nil {
+		if err := job.SetArgs(args); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.db.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, fmt.Errorf("creating job: %w", err)
+	}
+
+	m.notify()
+	return job, nil
+}
+
+// Start requeues jobs interrupted by a previous process and starts the
+// workers, which stop when ctx is cancelled. An interrupted job that has used
+// all its attempts is failed instead, so non-idempotent jobs (max attempts 1)
+// never run twice.
+func (m *Manager) Start(ctx context.Context) error {
+	result := m.db.WithContext(ctx).Model(&database.Job{}).
+		Where("status = ? AND attempts >= max_attempts", StatusRunning).
+		Updates(map[string]any{
+			"status":      StatusFailed,
+			"error":       "interrupted by restart",
+			"finished_at": time.Now().Unix(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failing interrupted jobs: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		logger.Warn().Int64("count", result.RowsAffected).Msg("Failed jobs interrupted by restart on their last attempt")
+	}
+
+	result = m.db.WithContext(ctx).Model(&database.Job{}).
+		Where("status = ?", StatusRunning).
+		Updates(map[string]any{"status": StatusQueued, "run_after": 0})
+	if result.Error != nil {
+		return fmt.Errorf("requeuing interrupted jobs: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		logger.Info().Int64("count", result.RowsAffected).Msg("Requeued jobs interrupted by restart")
+	}
+
+	for i := 0; i < m.workers; i++ {
+		m.wg.Add(1)
+		go m.worker(ctx)
+	}
+	return nil
+}
+
+// Wait blocks until the workers have stopped after their context was
+// cancelled. Jobs they were running are requeued for the next process unless
+// they were on their last attempt.
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+func (m *Manager) notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) worker(ctx context.Context) {
+	defer m.wg.Done()
+	for {
+		job, err := m.claim(ctx)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to claim job")
+		}
+		if job != nil {
+			m.run(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.wake:
+		case <-time.After(m.pollInterval):
+		}
+	}
+}
+
+// claim marks the oldest runnable queued job as running and returns it, or
+// nil when there is none.
+func (m *Manager) claim(ctx context.Context) (*database.Job, error) {
+	now := time.Now().Unix()
+	for {
+		var job database.Job
+		err := m.db.WithContext(ctx).
+			Where("status = ? AND run_after <= ?", StatusQueued, now).
+			Order("created_at ASC").
+			First(&job).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("finding queued job: %w", err)
+		}
+
+		result := m.db.WithContext(ctx).Model(&database.Job{}).
+			Where("id = ? AND status = ?", job.ID, StatusQueued).
+			Updates(map[string]any{
+				"status":     StatusRunning,
+				"attempts":   job.Attempts + 1,
+				"started_at": now,
+			})
+		if result.Error != nil {
+			return nil, fmt.Errorf("claiming job: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// Another worker claimed it or it was cancelled meanwhile.
+			continue
+		}
+
+		job.Status = StatusRunning
+		job.Attempts++
+		job.StartedAt = now
+		return &job, nil
+	}
+}
+
+func (m *Manager) run(ctx context.Context, job *database.Job) {
+	log := logger.Get()
+
+	reg, ok := m.handlers[job.Type]
+	if !ok {
+		m.finish(job.ID, StatusFailed, ErrUnknownJobType.Error())
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	m.mu.Lock()
+	m.cancels[job.ID] = cancel
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		delete(m.cancels, job.ID)
+		m.mu.Unlock()
+		cancel()
+	}()
+
+	// Cancel may have run between claim and registering cancel above; it
+	// leaves cancelled_by set for us to honour.
+	if m.cancelRequested(job.ID) {
+		m.finish(job.ID, StatusCancelled, "")
+		log.Info().Str("job_id", job.ID).Msg("Job cancelled before it started")
+		return
+	}
+
+	log.Info().Str("job_id", job.ID).Str("type", job.Type).Int("attempt", job.Attempts).Msg("Running job")
+
+	err := m.safeRun(jobCtx, reg.handler, job, &Progress{m: m, jobID: job.ID})
+
+	switch {
+	case ctx.Err() != nil && job.Attempts >= job.MaxAttempts:
+		// Shutting down on the last attempt: the job may have done part of
+		// its work and is not safe to run again.
+		m.finish(job.ID, StatusFailed, "interrupted by shutdown")
+		log.Warn().Str("job_id", job.ID).Msg("Job interrupted by shutdown on its last attempt")
+	case ctx.Err() != nil:
+		// Shutting down: leave the job for the next process to pick up.
+		m.update(job.ID, map[string]any{"status": StatusQueued, "attempts": job.Attempts - 1})
+	case jobCtx.Err() != nil:
+		m.finish(job.ID, StatusCancelled, "")
+		log.Info().Str("job_id", job.ID).Msg("Job cancelled")
+	case err == nil:
+		m.finish(job.ID, StatusSucceeded, "")
+		log.Info().Str("job_id", job.ID).Msg("Job succeeded")
+	case job.Attempts < job.MaxAttempts:
+		delay := m.retryDelay * time.Duration(job.Attempts)
+		m.update(job.ID, map[string]any{
+			"status":    StatusQueued,
+			"error":     err.Error(),
+			"run_after": time.Now().Add(delay).Unix(),
+		})
+		log.Warn().Err(err).Str("job_id", job.ID).Dur("retry_in", delay).Msg("Job failed, will retry")
+	default:
+		m.finish(job.ID, StatusFailed, err.Error())
+		log.Error().Err(err).Str("job_id", job.ID).Msg("Job failed")
+	}
+}
+
+func (m *Manager) cancelRequested(id string) bool {
+	var job database.Job
+	if err := m.db.Select("cancelled_by").First(&job, "id = ?", id).Error; err != nil {
+		logger.Error().Err(err).Str("job_id", id).Msg("Failed to check job cancellation")
+		return false
+	}
+	return job.CancelledBy != ""
+}
+
+func (m *Manager) safeRun(ctx context.Context, handler Handler, job *database.Job, p *Progress) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job, p)
+}
+
+func (m *Manager) finish(id, status, errMsg string) {
+	m.update(id, map[string]any{
+		"status":      status,
+		"error":       errMsg,
+		"finished_at": time.Now().Unix(),
+	})
+}
+
+func (m *Manager) update(id string, updates map[string]any) {
+	if err := m.db.Model(&database.Job{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		logger.Error().Err(err).Str("job_id", id).Msg("Failed to update job")
+	}
+}
+
+// FinalAttempt reports whether a failure of the job's current attempt is
+// final, so handlers only report errors to users once.
+func FinalAttempt(job *database.Job) bool {
+	return job.Attempts >= job.MaxAttempts
+}
+
+// Get returns the job with the given ID or unique ID prefix.
+func (m *Manager) Get(ctx context.Context, idOrPrefix string) (*database.Job, error) {
+	if idOrPrefix == "" {
+		return nil, ErrJobNotFound
+	}
+
+	var jobs []database.Job
+	err := m.db.WithContext(ctx).
+		Where("id = ? OR id LIKE ?", idOrPrefix, idOrPrefix+"%").
+		Limit(2).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("getting job: %w", err)
+	}
+	switch {
+	case len(jobs) == 0:
+		return nil, ErrJobNotFound
+	case len(jobs) > 1:
+		return nil, ErrAmbiguousID
+	}
+	return &jobs[0], nil
+}
+
+type ListOptions struct {
+	// Active limits the list to queued and running jobs.
+	Active      bool
+	RequestedBy string
+	Limit       int
+}
+
+// List returns jobs, newest first.
+func (m *Manager) List(ctx context.Context, opts ListOptions) ([]database.Job, error) {
+	query := m.db.WithContext(ctx).Order("created_at DESC")
+	if opts.Active {
+		query = query.Where("status IN ?", []string{StatusQueued, StatusRunning})
+	}
+	if opts.RequestedBy != "" {
+		query = query.Where("requested_by = ?", opts.RequestedBy)
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	var jobs []database.Job
+	if err := query.Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("listing jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Cancel stops a running job through its context, or cancels a queued one
+// before it starts. A job claimed by a worker that has not started it yet is
+// cancelled by that worker.
+func (m *Manager) Cancel(ctx context.Context, id, cancelledBy string) error {
+	job, err := m.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	switch job.Status {
+	case StatusQueued:
+		result := m.db.WithContext(ctx).Model(&database.Job{}).
+			Where("id = ? AND status = ?", job.ID, StatusQueued).
+			Updates(map[string]any{
+				"status":       StatusCancelled,
+				"cancelled_by": cancelledBy,
+				"finished_at":  time.Now().Unix(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("cancelling job: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+		// Claimed by a worker in the meantime; cancel it as running.
+	case StatusRunning:
+	default:
+		return ErrJobFinished
+	}
+
+	// Record the request first: a worker that claimed the job but has not
+	// registered its cancel func yet checks cancelled_by before running it.
+	result := m.db.WithContext(ctx).Model(&database.Job{}).
+		Where("id = ? AND status = ?", job.ID, StatusRunning).
+		Update("cancelled_by", cancelledBy)
+	if result.Error != nil {
+		return fmt.Errorf("cancelling job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobFinished
+	}
+
+	m.mu.Lock()
+	cancel, ok := m.cancels[job.ID]
+	m.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return nil
+}
+
+// Progress records how far a running job has got. It is safe for concurrent
+// use, and a nil *Progress ignores updates, so helpers shared with non-job
+// callers can take one unconditionally.
+type Progress struct {
+	m     *Manager
+	jobID string
+
+	mu          sync.Mutex
+	done, total int
+}
+
+func (p *Progress) SetTotal(total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.total = total
+	done := p.done
+	p.mu.Unlock()
+	p.save(done, total)
+}
+
+// Advance marks one more unit of work done.
+func (p *Progress) Advance() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.done++
+	done, total := p.done, p.total
+	p.mu.Unlock()
+	p.save(done, total)
+}
+
+func (p *Progress) save(done, total int) {
+	if p.m == nil {
+		return
+	}
+	p.m.update(p.jobID, map[string]any{"progress_done": done, "progress_total": total})
+}
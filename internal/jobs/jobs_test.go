@@ -0,0 +1,262 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/jobs"
+)
+
+func setupTestManager(t *testing.T) (*jobs.Manager, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&database.Job{}))
+
+	m := jobs.NewManager(db, 1)
+	m.SetPollInterval(10 * time.Millisecond)
+	m.SetRetryDelay(0)
+	return m, db
+}
+
+func waitForStatus(t *testing.T, m *jobs.Manager, id, status string) *database.Job {
+	var job *database.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(context.Background(), id)
+		return err == nil && job.Status == status
+	}, 2*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestManager_RunsJobWithArgsAndProgress(t *testing.T) {
+	m, _ := setupTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type args struct {
+		Source string `json:"source"`
+	}
+	var got args
+	m.Register("changes", 1, func(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+		require.NoError(t, job.GetArgs(&got))
+		p.SetTotal(2)
+		p.Advance()
+		p.Advance()
+		return nil
+	})
+	require.NoError(t, m.Start(ctx))
+
+	job, err := m.Enqueue(ctx, "changes", jobs.Target{RequestedBy: "alice", ChannelID: "ch1"}, args{Source: "uat"})
+	require.NoError(t, err)
+
+	done := waitForStatus(t, m, job.ID, jobs.StatusSucceeded)
+	require.Equal(t, "uat", got.Source)
+	require.Equal(t, 2, done.ProgressDone)
+	require.Equal(t, 2, done.ProgressTotal)
+	require.Equal(t, 1, done.Attempts)
+	require.Equal(t, "alice", done.RequestedBy)
+}
+
+func TestManager_RetriesUntilMaxAttempts(t *testing.T) {
+	m, _ := setupTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m.Register("flaky", 3, func(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+		return errors.New("boom")
+	})
+	require.NoError(t, m.Start(ctx))
+
+	job, err := m.Enqueue(ctx, "flaky", jobs.Target{RequestedBy: "alice"}, nil)
+	require.NoError(t, err)
+
+	failed := waitForStatus(t, m, job.ID, jobs.StatusFailed)
+	require.Equal(t, 3, failed.Attempts)
+	require.Equal(t, "boom", failed.Error)
+}
+
+func TestManager_CancelRunningJob(t *testing.T) {
+	m, _ := setupTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	m.Register("slow", 3, func(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, m.Start(ctx))
+
+	job, err := m.Enqueue(ctx, "slow", jobs.Target{RequestedBy: "alice"}, nil)
+	require.NoError(t, err)
+	<-started
+
+	require.NoError(t, m.Cancel(ctx, job.ID[:8], "bob"))
+
+	cancelled := waitForStatus(t, m, job.ID, jobs.StatusCancelled)
+	require.Equal(t, "bob", cancelled.CancelledBy)
+	require.ErrorIs(t, m.Cancel(ctx, job.ID, "bob"), jobs.ErrJobFinished)
+}
+
+func TestManager_CancelQueuedJob(t *testing.T) {
+	m, _ := setupTestManager(t)
+	ctx := context.Background()
+	m.Register("changes", 1, func(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+		return nil
+	})
+
+	job, err := m.Enqueue(ctx, "changes", jobs.Target{RequestedBy: "alice"}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, m.Cancel(ctx, job.ID, "alice"))
+
+	cancelled, err := m.Get(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, jobs.StatusCancelled, cancelled.Status)
+}
+
+func TestManager_CancelClaimedJobBeforeItStarts(t *testing.T) {
+	m, db := setupTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ran := make(chan struct{}, 1)
+	m.Register("changes", 1, func(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+		ran <- struct{}{}
+		return nil
+	})
+
+	// Claimed by a worker that has not registered its cancel func yet.
+	require.NoError(t, db.Create(&database.Job{ID: "claimed-job", Type: "changes", Status: jobs.StatusRunning, Attempts: 1, MaxAttempts: 1}).Error)
+	require.NoError(t, m.Cancel(ctx, "claimed-job", "bob"))
+
+	claimed, err := m.Get(ctx, "claimed-job")
+	require.NoError(t, err)
+	require.Equal(t, "bob", claimed.CancelledBy)
+
+	// The worker picks it up and sees the request before running it.
+	require.NoError(t, db.Model(&database.Job{}).Where("id = ?", "claimed-job").Update("status", jobs.StatusQueued).Error)
+	require.NoError(t, m.Start(ctx))
+
+	cancelled := waitForStatus(t, m, "claimed-job", jobs.StatusCancelled)
+	require.Equal(t, "bob", cancelled.CancelledBy)
+	select {
+	case <-ran:
+		t.Fatal("cancelled job ran")
+	default:
+	}
+}
+
+func TestManager_StartRequeuesInterruptedJobs(t *testing.T) {
+	m, db := setupTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, db.Create(&database.Job{
+		ID:          "interrupted-job",
+		Type:        "changes",
+		Status:      jobs.StatusRunning,
+		Attempts:    1,
+		MaxAttempts: 3,
+	}).Error)
+
+	m.Register("changes", 3, func(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+		return nil
+	})
+	require.NoError(t, m.Start(ctx))
+
+	done := waitForStatus(t, m, "interrupted-job", jobs.StatusSucceeded)
+	require.Equal(t, 2, done.Attempts)
+}
+
+func TestManager_StartFailsInterruptedJobOnLastAttempt(t *testing.T) {
+	m, db := setupTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, db.Create(&database.Job{
+		ID:          "interrupted-release",
+		Type:        "create-release",
+		Status:      jobs.StatusRunning,
+		Attempts:    1,
+		MaxAttempts: 1,
+	}).Error)
+
+	ran := make(chan struct{}, 1)
+	m.Register("create-release", 1, func(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+		ran <- struct{}{}
+		return nil
+	})
+	require.NoError(t, m.Start(ctx))
+
+	job, err := m.Get(ctx, "interrupted-release")
+	require.NoError(t, err)
+	require.Equal(t, jobs.StatusFailed, job.Status)
+	require.Equal(t, "interrupted by restart", job.Error)
+	require.NotZero(t, job.FinishedAt)
+
+	select {
+	case <-ran:
+		t.Fatal("interrupted create-release job ran again")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestManager_ShutdownRequeuesRunningJobs(t *testing.T) {
+	type tc struct {
+		name        string
+		maxAttempts int
+		status      string
+		attempts    int
+	}
+
+	cases := []tc{
+		{name: "retryable job is requeued", maxAttempts: 3, status: jobs.StatusQueued, attempts: 0},
+		{name: "last attempt is marked failed", maxAttempts: 1, status: jobs.StatusFailed, attempts: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m, _ := setupTestManager(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			started := make(chan struct{})
+			m.Register("slow", c.maxAttempts, func(ctx context.Context, job *database.Job, p *jobs.Progress) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			require.NoError(t, m.Start(ctx))
+
+			job, err := m.Enqueue(ctx, "slow", jobs.Target{RequestedBy: "alice"}, nil)
+			require.NoError(t, err)
+			<-started
+			cancel()
+			m.Wait()
+
+			stopped, err := m.Get(context.Background(), job.ID)
+			require.NoError(t, err)
+			require.Equal(t, c.status, stopped.Status)
+			require.Equal(t, c.attempts, stopped.Attempts)
+		})
+	}
+}
+
+func TestManager_EnqueueUnknownType(t *testing.T) {
+	m, _ := setupTestManager(t)
+
+	_, err := m.Enqueue(context.Background(), "nope", jobs.Target{}, nil)
+	require.ErrorIs(t, err, jobs.ErrUnknownJobType)
+}
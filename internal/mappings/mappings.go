@@ -1,5 +1,12 @@
 package mappings
 
+import (
+	"context"
+	"sync"
+
+	"github.com/user/mattermost-tools/internal/logger"
+)
+
 // GitHubToMattermost maps GitHub usernames to Mattermost usernames.
 // Mattermost usernames will be prefixed with @ for mentions.
 //
@@ -28,12 +35,54 @@ func init() {
 	}
 }
 
-func GitHubFromMattermost(mmUsername string) (string, bool) {
+// Store holds mappings users linked themselves. It is consulted before the
+// static map, which is also used when the store lookup fails.
+type Store interface {
+	GitHubForMattermost(ctx context.Context, mmUsername string) (string, bool, error)
+	MattermostForGitHub(ctx context.Context, ghUsername string) (string, bool, error)
+}
+
+var (
+	storeMu sync.RWMutex
+	store   Store
+)
+
+// SetStore installs the store consulted before the static map. Pass nil to
+// use only the static map.
+func SetStore(s Store) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	store = s
+}
+
+func currentStore() Store {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return store
+}
+
+func GitHubFromMattermost(ctx context.Context, mmUsername string) (string, bool) {
+	if s := currentStore(); s != nil {
+		gh, ok, err := s.GitHubForMattermost(ctx, mmUsername)
+		if err != nil {
+			logger.Warn().Err(err).Str("user", mmUsername).Msg("Looking up linked GitHub account failed, using static mappings")
+		} else if ok {
+			return gh, true
+		}
+	}
 	gh, ok := mattermostToGitHub[mmUsername]
 	return gh, ok
 }
 
-func MattermostFromGitHub(ghUsername string) (string, bool) {
+func MattermostFromGitHub(ctx context.Context, ghUsername string) (string, bool) {
+	if s := currentStore(); s != nil {
+		mm, ok, err := s.MattermostForGitHub(ctx, ghUsername)
+		if err != nil {
+			logger.Warn().Err(err).Str("github_user", ghUsername).Msg("Looking up linked Mattermost account failed, using static mappings")
+		} else if ok {
+			return mm, true
+		}
+	}
 	mm, ok := GitHubToMattermost[ghUsername]
 	return mm, ok
 }
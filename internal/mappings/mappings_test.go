@@ -0,0 +1,88 @@
+package mappings_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/mappings"
+)
+
+type fakeStore struct {
+	links map[string]string
+	err   error
+}
+
+func (s fakeStore) GitHubForMattermost(ctx context.Context, mmUsername string) (string, bool, error) {
+	for gh, mm := range s.links {
+		if mm == mmUsername {
+			return gh, true, s.err
+		}
+	}
+	return "", false, s.err
+}
+
+func (s fakeStore) MattermostForGitHub(ctx context.Context, ghUsername string) (string, bool, error) {
+	mm, ok := s.links[ghUsername]
+	return mm, ok, s.err
+}
+
+func TestMattermostFromGitHub(t *testing.T) {
+	type tc struct {
+		name   string
+		store  mappings.Store
+		login  string
+		wantMM string
+		wantOK bool
+	}
+
+	cases := []tc{
+		{
+			name:   "static map without store",
+			login:  "Damanox",
+			wantMM: "damanox",
+			wantOK: true,
+		},
+		{
+			name:   "store before static map",
+			store:  fakeStore{links: map[string]string{"Damanox": "linked"}},
+			login:  "Damanox",
+			wantMM: "linked",
+			wantOK: true,
+		},
+		{
+			name:   "static map when store fails",
+			store:  fakeStore{links: map[string]string{"Damanox": "linked"}, err: errors.New("database is locked")},
+			login:  "Damanox",
+			wantMM: "damanox",
+			wantOK: true,
+		},
+		{
+			name:  "unmapped",
+			store: fakeStore{},
+			login: "nobody",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mappings.SetStore(c.store)
+			t.Cleanup(func() { mappings.SetStore(nil) })
+
+			mm, ok := mappings.MattermostFromGitHub(context.Background(), c.login)
+			require.Equal(t, c.wantOK, ok)
+			require.Equal(t, c.wantMM, mm)
+		})
+	}
+}
+
+func TestGitHubFromMattermost_StoreFails(t *testing.T) {
+	mappings.SetStore(fakeStore{err: errors.New("database is locked")})
+	t.Cleanup(func() { mappings.SetStore(nil) })
+
+	gh, ok := mappings.GitHubFromMattermost(context.Background(), "damanox")
+	require.True(t, ok)
+	require.Equal(t, "Damanox", gh)
+}
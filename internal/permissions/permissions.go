@@ -0,0 +1,291 @@
+package permissions
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/pkg/mattermost"
+)
+
+const (
+	PolicyAllow = "allow"
+	PolicyDeny  = "deny"
+
+	RoleSystemAdmin  = "system_admin"
+	RoleTeamAdmin    = "team_admin"
+	RoleChannelAdmin = "channel_admin"
+
+	defaultCacheTTL = 5 * time.Minute
+)
+
+// Lookup is the subset of the Mattermost API needed to resolve groups and
+// roles. *mattermost.Bot implements it.
+type Lookup interface {
+	GetUserByUsername(ctx context.Context, username string) (*mattermost.User, error)
+	GetUserGroups(ctx context.Context, userID string) ([]mattermost.Group, error)
+	GetTeamMember(ctx context.Context, teamID, userID string) (*mattermost.Member, error)
+	GetChannelMember(ctx context.Context, channelID, userID string) (*mattermost.Member, error)
+}
+
+// Subject is who is performing an action and where.
+type Subject struct {
+	UserName  string
+	TeamID    string
+	ChannelID string
+}
+
+type Rule struct {
+	Allow []string
+	Deny  []string
+}
+
+type Checker struct {
+	rules        map[string]Rule
+	defaultAllow bool
+	teamID       string
+	lookup       Lookup
+	ttl          time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	values    []string
+	expiresAt time.Time
+}
+
+// NewChecker builds a Checker from the permissions config. Entries from the
+// legacy command_permissions map are merged in as username allow-lists.
+// lookup may be nil, in which case group and role subjects never match.
+func NewChecker(cfg config.PermissionsConfig, legacy map[string][]string, lookup Lookup) *Checker {
+	rules := make(map[string]Rule)
+	for action, users := range legacy {
+		rule := rules[action]
+		rule.Allow = append(rule.Allow, users...)
+		rules[action] = rule
+	}
+	for action, r := range cfg.Rules {
+		rule := rules[action]
+		rule.Allow = append(rule.Allow, r.Allow...)
+		rule.Deny = append(rule.Deny, r.Deny...)
+		rules[action] = rule
+	}
+
+	ttl := cfg.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	return &Checker{
+		rules:        rules,
+		defaultAllow: !strings.EqualFold(cfg.Default, PolicyDeny),
+		teamID:       cfg.TeamID,
+		lookup:       lookup,
+		ttl:          ttl,
+		cache:        make(map[string]cacheEntry),
+	}
+}
+
+// Allowed reports whether the subject may perform the action. Deny rules win
+// over allow rules; an action with no allow rules falls back to the default
+// policy. Lookup failures deny.
+func (c *Checker) Allowed(ctx context.Context, action string, subject Subject) bool {
+	if c == nil {
+		return true
+	}
+
+	rule, ok := c.rules[action]
+	if !ok {
+		return c.defaultAllow
+	}
+
+	if subject.TeamID == "" {
+		subject.TeamID = c.teamID
+	}
+
+	if len(rule.Deny) > 0 {
+		denied, err := c.matchesAny(ctx, rule.Deny, subject)
+		if err != nil {
+			logLookupError(action, subject, err)
+			return false
+		}
+		if denied {
+			return false
+		}
+	}
+
+	if len(rule.Allow) == 0 {
+		return c.defaultAllow
+	}
+
+	allowed, err := c.matchesAny(ctx, rule.Allow, subject)
+	if err != nil {
+		logLookupError(action, subject, err)
+		return false
+	}
+	return allowed
+}
+
+// Granted is like Allowed for privileged actions: the subject must match an
+// allow rule for the action, whatever the default policy.
+func (c *Checker) Granted(ctx context.Context, action string, subject Subject) bool {
+	if c == nil {
+		return false
+	}
+	if rule, ok := c.rules[action]; !ok || len(rule.Allow) == 0 {
+		return false
+	}
+	return c.Allowed(ctx, action, subject)
+}
+
+func logLookupError(action string, subject Subject, err error) {
+	logger.Warn().Err(err).Str("action", action).Str("user", subject.UserName).Msg("Permission lookup failed, denying")
+}
+
+func (c *Checker) matchesAny(ctx context.Context, entries []string, subject Subject) (bool, error) {
+	for _, entry := range entries {
+		matched, err := c.matches(ctx, entry, subject)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *Checker) matches(ctx context.Context, entry string, subject Subject) (bool, error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "*" {
+		return true, nil
+	}
+
+	kind, value, found := strings.Cut(entry, ":")
+	if !found {
+		kind, value = "user", entry
+	}
+
+	switch strings.ToLower(kind) {
+	case "user":
+		return strings.EqualFold(strings.TrimPrefix(value, "@"), subject.UserName), nil
+	case "group":
+		groups, err := c.groups(ctx, subject.UserName)
+		if err != nil {
+			return false, err
+		}
+		return containsFold(groups, strings.TrimPrefix(value, "@")), nil
+	case "role":
+		roles, err := c.roles(ctx, strings.ToLower(value), subject)
+		if err != nil {
+			return false, err
+		}
+		return containsFold(roles, value), nil
+	default:
+		logger.Warn().Str("entry", entry).Msg("Unknown permission subject type")
+		return false, nil
+	}
+}
+
+func (c *Checker) roles(ctx context.Context, role string, subject Subject) ([]string, error) {
+	switch {
+	case strings.HasPrefix(role, "team_"):
+		if subject.TeamID == "" {
+			return nil, nil
+		}
+		return c.memberRoles(ctx, "team:"+subject.TeamID, subject.UserName, RoleTeamAdmin, func(userID string) (*mattermost.Member, error) {
+			return c.lookup.GetTeamMember(ctx, subject.TeamID, userID)
+		})
+	case strings.HasPrefix(role, "channel_"):
+		if subject.ChannelID == "" {
+			return nil, nil
+		}
+		return c.memberRoles(ctx, "channel:"+subject.ChannelID, subject.UserName, RoleChannelAdmin, func(userID string) (*mattermost.Member, error) {
+			return c.lookup.GetChannelMember(ctx, subject.ChannelID, userID)
+		})
+	default:
+		return c.cached("roles:"+strings.ToLower(subject.UserName), func() ([]string, error) {
+			user, err := c.user(ctx, subject.UserName)
+			if err != nil || user == nil {
+				return nil, err
+			}
+			return strings.Fields(user.Roles), nil
+		})
+	}
+}
+
+func (c *Checker) memberRoles(ctx context.Context, scope, username, adminRole string, get func(userID string) (*mattermost.Member, error)) ([]string, error) {
+	return c.cached(scope+":"+strings.ToLower(username), func() ([]string, error) {
+		user, err := c.user(ctx, username)
+		if err != nil || user == nil {
+			return nil, err
+		}
+		member, err := get(user.ID)
+		if err != nil || member == nil {
+			return nil, err
+		}
+		roles := strings.Fields(member.Roles)
+		if member.SchemeAdmin {
+			roles = append(roles, adminRole)
+		}
+		return roles, nil
+	})
+}
+
+func (c *Checker) groups(ctx context.Context, username string) ([]string, error) {
+	return c.cached("groups:"+strings.ToLower(username), func() ([]string, error) {
+		user, err := c.user(ctx, username)
+		if err != nil || user == nil {
+			return nil, err
+		}
+		groups, err := c.lookup.GetUserGroups(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(groups))
+		for _, g := range groups {
+			names = append(names, g.Name)
+		}
+		return names, nil
+	})
+}
+
+func (c *Checker) user(ctx context.Context, username string) (*mattermost.User, error) {
+	if c.lookup == nil || username == "" {
+		return nil, nil
+	}
+	return c.lookup.GetUserByUsername(ctx, username)
+}
+
+func (c *Checker) cached(key string, load func() ([]string, error)) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.values, nil
+	}
+
+	values, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[key] = cacheEntry{values: values, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return values, nil
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+package permissions_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/permissions"
+	"github.com/user/mattermost-tools/pkg/mattermost"
+)
+
+type fakeLookup struct {
+	users          map[string]*mattermost.User
+	groups         map[string][]mattermost.Group
+	channelMembers map[string]*mattermost.Member
+	teamMembers    map[string]*mattermost.Member
+	err            error
+	calls          int
+}
+
+func (f *fakeLookup) GetUserByUsername(_ context.Context, username string) (*mattermost.User, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.users[username], nil
+}
+
+func (f *fakeLookup) GetUserGroups(_ context.Context, userID string) ([]mattermost.Group, error) {
+	f.calls++
+	return f.groups[userID], nil
+}
+
+func (f *fakeLookup) GetTeamMember(_ context.Context, teamID, userID string) (*mattermost.Member, error) {
+	f.calls++
+	return f.teamMembers[teamID+"/"+userID], nil
+}
+
+func (f *fakeLookup) GetChannelMember(_ context.Context, channelID, userID string) (*mattermost.Member, error) {
+	f.calls++
+	return f.channelMembers[channelID+"/"+userID], nil
+}
+
+func newFakeLookup() *fakeLookup {
+	return &fakeLookup{
+		users: map[string]*mattermost.User{
+			"alice": {ID: "u1", Username: "alice", Roles: "system_user system_admin"},
+			"bob":   {ID: "u2", Username: "bob", Roles: "system_user"},
+			"carol": {ID: "u3", Username: "carol", Roles: "system_user"},
+		},
+		groups: map[string][]mattermost.Group{
+			"u2": {{ID: "g1", Name: "devops"}},
+		},
+		channelMembers: map[string]*mattermost.Member{
+			"ch1/u3": {UserID: "u3", Roles: "channel_user", SchemeAdmin: true},
+		},
+		teamMembers: map[string]*mattermost.Member{
+			"team1/u2": {UserID: "u2", Roles: "team_user team_admin"},
+		},
+	}
+}
+
+func TestChecker_Allowed(t *testing.T) {
+	type tc struct {
+		name    string
+		cfg     config.PermissionsConfig
+		legacy  map[string][]string
+		action  string
+		subject permissions.Subject
+		allowed bool
+	}
+
+	cases := []tc{
+		{
+			name:    "no rule uses allow default",
+			action:  "changes",
+			subject: permissions.Subject{UserName: "carol"},
+			allowed: true,
+		},
+		{
+			name:    "no rule uses deny default",
+			cfg:     config.PermissionsConfig{Default: "deny"},
+			action:  "changes",
+			subject: permissions.Subject{UserName: "carol"},
+			allowed: false,
+		},
+		{
+			name:    "legacy username list",
+			legacy:  map[string][]string{"changes": {"Bob"}},
+			action:  "changes",
+			subject: permissions.Subject{UserName: "bob"},
+			allowed: true,
+		},
+		{
+			name:    "legacy username list rejects others",
+			legacy:  map[string][]string{"changes": {"bob"}},
+			action:  "changes",
+			subject: permissions.Subject{UserName: "carol"},
+			allowed: false,
+		},
+		{
+			name: "group membership",
+			cfg: config.PermissionsConfig{Rules: map[string]config.PermissionRule{
+				"approve": {Allow: []string{"group:devops"}},
+			}},
+			action:  "approve",
+			subject: permissions.Subject{UserName: "bob"},
+			allowed: true,
+		},
+		{
+			name: "system admin role",
+			cfg: config.PermissionsConfig{Rules: map[string]config.PermissionRule{
+				"approve": {Allow: []string{"role:system_admin"}},
+			}},
+			action:  "approve",
+			subject: permissions.Subject{UserName: "alice"},
+			allowed: true,
+		},
+		{
+			name: "team admin role from configured team",
+			cfg: config.PermissionsConfig{TeamID: "team1", Rules: map[string]config.PermissionRule{
+				"approve": {Allow: []string{"role:team_admin"}},
+			}},
+			action:  "approve",
+			subject: permissions.Subject{UserName: "bob"},
+			allowed: true,
+		},
+		{
+			name: "channel admin via scheme",
+			cfg: config.PermissionsConfig{Rules: map[string]config.PermissionRule{
+				"decline": {Allow: []string{"role:channel_admin"}},
+			}},
+			action:  "decline",
+			subject: permissions.Subject{UserName: "carol", ChannelID: "ch1"},
+			allowed: true,
+		},
+		{
+			name: "deny wins over allow",
+			cfg: config.PermissionsConfig{Rules: map[string]config.PermissionRule{
+				"approve": {Allow: []string{"*"}, Deny: []string{"user:bob"}},
+			}},
+			action:  "approve",
+			subject: permissions.Subject{UserName: "bob"},
+			allowed: false,
+		},
+		{
+			name: "deny only rule falls back to default",
+			cfg: config.PermissionsConfig{Rules: map[string]config.PermissionRule{
+				"approve": {Deny: []string{"group:devops"}},
+			}},
+			action:  "approve",
+			subject: permissions.Subject{UserName: "carol"},
+			allowed: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checker := permissions.NewChecker(c.cfg, c.legacy, newFakeLookup())
+			require.Equal(t, c.allowed, checker.Allowed(context.Background(), c.action, c.subject))
+		})
+	}
+}
+
+func TestChecker_CachesLookups(t *testing.T) {
+	lookup := newFakeLookup()
+	checker := permissions.NewChecker(config.PermissionsConfig{Rules: map[string]config.PermissionRule{
+		"approve": {Allow: []string{"group:devops"}},
+	}}, nil, lookup)
+
+	require.True(t, checker.Allowed(context.Background(), "approve", permissions.Subject{UserName: "bob"}))
+	calls := lookup.calls
+	require.True(t, checker.Allowed(context.Background(), "approve", permissions.Subject{UserName: "bob"}))
+	require.Equal(t, calls, lookup.calls)
+}
+
+func TestChecker_LookupErrorDenies(t *testing.T) {
+	lookup := newFakeLookup()
+	lookup.err = errors.New("mattermost down")
+	checker := permissions.NewChecker(config.PermissionsConfig{Rules: map[string]config.PermissionRule{
+		"approve": {Allow: []string{"group:devops"}},
+	}}, nil, lookup)
+
+	require.False(t, checker.Allowed(context.Background(), "approve", permissions.Subject{UserName: "bob"}))
+}
+
+func TestChecker_Granted(t *testing.T) {
+	type tc struct {
+		name     string
+		cfg      config.PermissionsConfig
+		user     string
+		expected bool
+	}
+
+	cases := []tc{
+		{
+			name:     "no rule denies despite allow default",
+			cfg:      config.PermissionsConfig{Default: "allow"},
+			user:     "alice",
+			expected: false,
+		},
+		{
+			name: "matching allow rule grants",
+			cfg: config.PermissionsConfig{Rules: map[string]config.PermissionRule{
+				"freeze-override": {Allow: []string{"group:devops"}},
+			}},
+			user:     "bob",
+			expected: true,
+		},
+		{
+			name: "deny-only rule grants nobody",
+			cfg: config.PermissionsConfig{Rules: map[string]config.PermissionRule{
+				"freeze-override": {Deny: []string{"carol"}},
+			}},
+			user:     "alice",
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checker := permissions.NewChecker(c.cfg, nil, newFakeLookup())
+			require.Equal(t, c.expected, checker.Granted(context.Background(), "freeze-override", permissions.Subject{UserName: c.user}))
+		})
+	}
+
+	var nilChecker *permissions.Checker
+	require.False(t, nilChecker.Granted(context.Background(), "freeze-override", permissions.Subject{UserName: "alice"}))
+}
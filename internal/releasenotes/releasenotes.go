@@ -0,0 +1,198 @@
+// Package releasenotes renders a release as a Markdown, HTML or JSON
+// document from Go templates that can be overridden.
+package releasenotes
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/database"
+)
+
+// Document is everything release notes show about a release.
+type Document struct {
+	ID              string     `json:"id"`
+	SourceBranch    string     `json:"source_branch"`
+	DestBranch      string     `json:"dest_branch"`
+	Status          string     `json:"status"`
+	IsHotfix        bool       `json:"is_hotfix"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	Notes           string     `json:"notes"`
+	BreakingChanges string     `json:"breaking_changes"`
+	Repos           []Repo     `json:"repos"`
+	Waves           []Wave     `json:"waves"`
+	Approvals       []Approval `json:"approvals"`
+	Trail           []Event    `json:"trail"`
+	GeneratedAt     time.Time  `json:"generated_at"`
+}
+
+// Repo is an included repo of the release.
+type Repo struct {
+	Name          string   `json:"name"`
+	Summary       string   `json:"summary"`
+	IsBreaking    bool     `json:"is_breaking"`
+	RiskLevel     string   `json:"risk_level,omitempty"`
+	HasMigration  bool     `json:"has_migration"`
+	APIChanges    []string `json:"api_changes,omitempty"`
+	ConfigChanges []string `json:"config_changes,omitempty"`
+	RolloutNotes  string   `json:"rollout_notes,omitempty"`
+	RiskReasons   []string `json:"risk_reasons,omitempty"`
+	InfraChanges  []string `json:"infra_changes,omitempty"`
+	Contributors  []string `json:"contributors,omitempty"`
+	CommitCount   int      `json:"commit_count"`
+	Additions     int      `json:"additions"`
+	Deletions     int      `json:"deletions"`
+	PRNumber      int      `json:"pr_number,omitempty"`
+	PRURL         string   `json:"pr_url,omitempty"`
+	PRMerged      bool     `json:"pr_merged"`
+	DependsOn     []string `json:"depends_on,omitempty"`
+	// Wave is the repo's deploy order; repos in the same wave can be
+	// deployed together. 0 when the order could not be calculated.
+	Wave int `json:"wave"`
+}
+
+// Wave is a group of repos deployed together, after earlier waves.
+type Wave struct {
+	Number int      `json:"number"`
+	Repos  []string `json:"repos"`
+}
+
+// Approval is a current approval of the release.
+type Approval struct {
+	Type string    `json:"type"`
+	By   string    `json:"by"`
+	At   time.Time `json:"at"`
+}
+
+// Event is an approval-related entry of the release history.
+type Event struct {
+	Action string `json:"action"`
+	Actor  string `json:"actor"`
+	Detail string `json:"detail,omitempty"`
+	// Reason is why the status changed, e.g. why the release was declined.
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Build assembles the document of release. deployOrder maps repo IDs to
+// their deploy wave and may be nil; history may be in any order.
+func Build(release database.Release, repos []database.ReleaseRepo, deployOrder map[uint]int, history []database.ReleaseHistory) *Document {
+	doc := &Document{
+		ID:              release.ID,
+		SourceBranch:    release.SourceBranch,
+		DestBranch:      release.DestBranch,
+		Status:          release.Status,
+		IsHotfix:        release.IsHotfix,
+		CreatedBy:       release.CreatedBy,
+		CreatedAt:       time.Unix(release.CreatedAt, 0).UTC(),
+		Notes:           release.Notes,
+		BreakingChanges: release.BreakingChanges,
+		Repos:           []Repo{},
+		Waves:           []Wave{},
+		Approvals:       []Approval{},
+		Trail:           []Event{},
+		GeneratedAt:     time.Now().UTC(),
+	}
+
+	for _, r := range repos {
+		if r.Excluded {
+			continue
+		}
+		repo := Repo{
+			Name:         r.RepoName,
+			Summary:      r.Summary,
+			IsBreaking:   r.IsBreaking,
+			RiskLevel:    r.RiskLevel,
+			HasMigration: r.HasMigration,
+			RolloutNotes: r.RolloutNotes,
+			CommitCount:  r.CommitCount,
+			Additions:    r.Additions,
+			Deletions:    r.Deletions,
+			PRNumber:     r.PRNumber,
+			PRURL:        r.PRURL,
+			PRMerged:     r.PRMerged,
+			Wave:         deployOrder[r.ID],
+		}
+		// Malformed JSON columns are shown as empty rather than failing the
+		// whole document.
+		repo.APIChanges, _ = r.GetAPIChanges()
+		repo.ConfigChanges, _ = r.GetConfigChanges()
+		repo.RiskReasons, _ = r.GetRiskReasons()
+		repo.InfraChanges, _ = r.GetInfraChanges()
+		repo.Contributors, _ = r.GetContributors()
+		repo.DependsOn, _ = r.GetDependsOn()
+		doc.Repos = append(doc.Repos, repo)
+	}
+
+	sort.SliceStable(doc.Repos, func(i, j int) bool {
+		if doc.Repos[i].Wave != doc.Repos[j].Wave {
+			return doc.Repos[i].Wave < doc.Repos[j].Wave
+		}
+		return doc.Repos[i].Name < doc.Repos[j].Name
+	})
+	for _, r := range doc.Repos {
+		if r.Wave == 0 {
+			continue
+		}
+		if n := len(doc.Waves); n == 0 || doc.Waves[n-1].Number != r.Wave {
+			doc.Waves = append(doc.Waves, Wave{Number: r.Wave})
+		}
+		doc.Waves[len(doc.Waves)-1].Repos = append(doc.Waves[len(doc.Waves)-1].Repos, r.Name)
+	}
+
+	if release.DevApprovedBy != "" {
+		doc.Approvals = append(doc.Approvals, Approval{Type: "dev", By: release.DevApprovedBy, At: time.Unix(release.DevApprovedAt, 0).UTC()})
+	}
+	if release.QAApprovedBy != "" {
+		doc.Approvals = append(doc.Approvals, Approval{Type: "qa", By: release.QAApprovedBy, At: time.Unix(release.QAApprovedAt, 0).UTC()})
+	}
+
+	for _, h := range history {
+		if _, ok := eventDetailKeys[h.Action]; !ok {
+			continue
+		}
+		details := eventDetails(h)
+		event := Event{
+			Action: h.Action,
+			Actor:  h.Actor,
+			Detail: details[eventDetailKeys[h.Action]],
+			At:     time.Unix(h.CreatedAt, 0).UTC(),
+		}
+		if h.Action == "status_changed" {
+			event.Reason = details["reason"]
+		}
+		doc.Trail = append(doc.Trail, event)
+	}
+	sort.SliceStable(doc.Trail, func(i, j int) bool {
+		return doc.Trail[i].At.Before(doc.Trail[j].At)
+	})
+
+	return doc
+}
+
+// eventDetailKeys name the detail shown for each trail action: the
+// approval type, decline reason, action done despite a freeze or new status.
+var eventDetailKeys = map[string]string{
+	"approval_added":    "type",
+	"approval_revoked":  "type",
+	"release_declined":  "reason",
+	"freeze_overridden": "action",
+	"status_changed":    "to",
+}
+
+// eventDetails returns the string values of the history entry's details.
+func eventDetails(h database.ReleaseHistory) map[string]string {
+	var details map[string]any
+	if h.Details == "" || json.Unmarshal([]byte(h.Details), &details) != nil {
+		return nil
+	}
+	result := make(map[string]string, len(details))
+	for k, v := range details {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		}
+	}
+	return result
+}
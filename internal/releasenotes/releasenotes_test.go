@@ -0,0 +1,230 @@
+package releasenotes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/releasenotes"
+)
+
+func testDocument(t *testing.T) *releasenotes.Document {
+	t.Helper()
+
+	release := database.Release{
+		ID:              "rel-1",
+		SourceBranch:    "uat",
+		DestBranch:      "master",
+		Status:          "approved",
+		CreatedBy:       "alice",
+		CreatedAt:       time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC).Unix(),
+		Notes:           "Quarterly release.",
+		BreakingChanges: "Clients must use <v2>.",
+		DevApprovedBy:   "bob",
+		DevApprovedAt:   time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	gateway := database.ReleaseRepo{ID: 2, RepoName: "api-gateway", Summary: "Routes SSO.", CommitCount: 3, Additions: 20, Deletions: 4,
+		PRNumber: 12, PRURL: "https://github.com/org/api-gateway/pull/12", PRMerged: true}
+	require.NoError(t, gateway.SetDependsOn([]string{"auth-service"}))
+	require.NoError(t, gateway.SetContributors([]string{"carol", "dave"}))
+	auth := database.ReleaseRepo{ID: 1, RepoName: "auth-service", Summary: "Adds SSO.", IsBreaking: true, RiskLevel: "high", HasMigration: true, CommitCount: 5}
+	require.NoError(t, auth.SetInfraChanges([]string{"helm/values.yaml"}))
+	require.NoError(t, auth.SetRiskReasons([]string{"new migration db/0042.sql"}))
+	billing := database.ReleaseRepo{ID: 3, RepoName: "billing", Summary: "Adds invoices.", CommitCount: 1}
+	excluded := database.ReleaseRepo{ID: 4, RepoName: "legacy", Excluded: true}
+
+	history := []database.ReleaseHistory{
+		{Action: "approval_added", Actor: "bob", Details: `{"type":"dev","via":"chat"}`, CreatedAt: time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC).Unix()},
+		{Action: "status_changed", Actor: "bob", Details: `{"from":"pending","to":"approved"}`, CreatedAt: time.Date(2026, 3, 3, 10, 0, 1, 0, time.UTC).Unix()},
+		{Action: "repo_confirmed", Actor: "carol", Details: `{"repo":"billing"}`, CreatedAt: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC).Unix()},
+		{Action: "approval_revoked", Actor: "bob", Details: `{"type":"dev"}`, CreatedAt: time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC).Unix()},
+		{Action: "freeze_overridden", Actor: "erin", Details: `{"action":"approve","reason":"Holidays"}`, CreatedAt: time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC).Unix()},
+	}
+
+	deployOrder := map[uint]int{1: 1, 2: 2, 3: 1}
+	doc := releasenotes.Build(release, []database.ReleaseRepo{gateway, auth, billing, excluded}, deployOrder, history)
+	doc.GeneratedAt = time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC)
+	return doc
+}
+
+func TestBuild(t *testing.T) {
+	doc := testDocument(t)
+
+	var names []string
+	for _, r := range doc.Repos {
+		names = append(names, r.Name)
+	}
+	require.Equal(t, []string{"auth-service", "billing", "api-gateway"}, names)
+	require.Equal(t, []releasenotes.Wave{
+		{Number: 1, Repos: []string{"auth-service", "billing"}},
+		{Number: 2, Repos: []string{"api-gateway"}},
+	}, doc.Waves)
+	require.Equal(t, []string{"auth-service"}, doc.Repos[2].DependsOn)
+	require.Equal(t, []releasenotes.Approval{{Type: "dev", By: "bob", At: time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)}}, doc.Approvals)
+
+	var trail []string
+	for _, e := range doc.Trail {
+		trail = append(trail, e.Action+":"+e.Detail)
+	}
+	require.Equal(t, []string{"freeze_overridden:approve", "approval_revoked:dev", "approval_added:dev", "status_changed:approved"}, trail)
+}
+
+func TestRenderer_Markdown(t *testing.T) {
+	renderer, err := releasenotes.NewRenderer("")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderer.Render(&buf, testDocument(t), releasenotes.FormatMarkdown))
+
+	require.Equal(t, "# Release notes: `uat` → `master`\n"+
+		"\n"+
+		"- **Status:** approved\n"+
+		"- **Created:** 2026-03-02 09:00 UTC by alice\n"+
+		"- **Release ID:** rel-1\n"+
+		"\n"+
+		"## Notes\n"+
+		"\n"+
+		"Quarterly release.\n"+
+		"\n"+
+		"## Breaking changes\n"+
+		"\n"+
+		"Clients must use <v2>.\n"+
+		"\n"+
+		"## Repositories\n"+
+		"\n"+
+		"### auth-service 🚨 breaking · high risk · DB migration\n"+
+		"\n"+
+		"Adds SSO.\n"+
+		"\n"+
+		"- **Changes:** 5 commits, +0/-0\n"+
+		"- **Infra:** helm/values.yaml\n"+
+		"- **Flagged:** new migration db/0042.sql\n"+
+		"\n"+
+		"### billing\n"+
+		"\n"+
+		"Adds invoices.\n"+
+		"\n"+
+		"- **Changes:** 1 commits, +0/-0\n"+
+		"\n"+
+		"### api-gateway\n"+
+		"\n"+
+		"Routes SSO.\n"+
+		"\n"+
+		"- **Changes:** 3 commits, +20/-4\n"+
+		"- **PR:** [#12](https://github.com/org/api-gateway/pull/12) (merged)\n"+
+		"- **Contributors:** carol, dave\n"+
+		"\n"+
+		"## Deploy order\n"+
+		"\n"+
+		"1. auth-service, billing\n"+
+		"2. api-gateway\n"+
+		"\n"+
+		"## Approvals\n"+
+		"\n"+
+		"- **DEV** approved by bob on 2026-03-03 10:00 UTC\n"+
+		"\n"+
+		"### Approval trail\n"+
+		"\n"+
+		"- 2026-03-02 10:30 UTC · erin overrode the deployment freeze to approve\n"+
+		"- 2026-03-02 11:00 UTC · bob revoked DEV approval\n"+
+		"- 2026-03-03 10:00 UTC · bob added DEV approval\n"+
+		"- 2026-03-03 10:00 UTC · bob moved the release to approved\n"+
+		"\n"+
+		"_Generated 2026-03-04 08:00 UTC_\n", buf.String())
+}
+
+func TestRenderer_HTMLEscapes(t *testing.T) {
+	renderer, err := releasenotes.NewRenderer("")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderer.Render(&buf, testDocument(t), releasenotes.FormatHTML))
+
+	require.Contains(t, buf.String(), "Clients must use &lt;v2&gt;.")
+	require.Contains(t, buf.String(), `<a href="https://github.com/org/api-gateway/pull/12">#12</a> (merged)`)
+	require.Contains(t, buf.String(), "<li>auth-service, billing</li>")
+}
+
+func TestRenderer_JSON(t *testing.T) {
+	renderer, err := releasenotes.NewRenderer("")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderer.Render(&buf, testDocument(t), releasenotes.FormatJSON))
+
+	var decoded releasenotes.Document
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, *testDocument(t), decoded)
+}
+
+func TestNewRenderer_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md.tmpl"), []byte("{{.SourceBranch}} to {{.DestBranch}}: {{len .Repos}} repos"), 0o644))
+
+	renderer, err := releasenotes.NewRenderer(dir)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderer.Render(&buf, testDocument(t), releasenotes.FormatMarkdown))
+	require.Equal(t, "uat to master: 3 repos", buf.String())
+
+	buf.Reset()
+	require.NoError(t, renderer.Render(&buf, testDocument(t), releasenotes.FormatHTML))
+	require.Contains(t, buf.String(), "<!DOCTYPE html>")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.html.tmpl"), []byte("{{.Missing"), 0o644))
+	_, err = releasenotes.NewRenderer(dir)
+	require.ErrorContains(t, err, "parsing notes.html.tmpl")
+}
+
+func TestRenderer_StatusChangeReason(t *testing.T) {
+	release := database.Release{ID: "rel-2", SourceBranch: "uat", DestBranch: "master", Status: "declined", CreatedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC).Unix()}
+	history := []database.ReleaseHistory{
+		{Action: "status_changed", Actor: "lead", Details: `{"from":"pending","reason":"QA found a regression","to":"declined","via":"chat"}`, CreatedAt: time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC).Unix()},
+	}
+	doc := releasenotes.Build(release, nil, nil, history)
+	require.Len(t, doc.Trail, 1)
+	require.Equal(t, "declined", doc.Trail[0].Detail)
+	require.Equal(t, "QA found a regression", doc.Trail[0].Reason)
+
+	renderer, err := releasenotes.NewRenderer("")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, renderer.Render(&buf, doc, releasenotes.FormatMarkdown))
+	require.Contains(t, buf.String(), "moved the release to declined: QA found a regression")
+}
+
+func TestParseFormat(t *testing.T) {
+	type tc struct {
+		in       string
+		expected string
+		wantErr  bool
+	}
+
+	cases := []tc{
+		{in: "", expected: releasenotes.FormatMarkdown},
+		{in: "markdown", expected: releasenotes.FormatMarkdown},
+		{in: "HTML", expected: releasenotes.FormatHTML},
+		{in: "json", expected: releasenotes.FormatJSON},
+		{in: "pdf", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			format, err := releasenotes.ParseFormat(c.in)
+			if c.wantErr {
+				require.ErrorContains(t, err, "unknown format")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, format)
+		})
+	}
+}
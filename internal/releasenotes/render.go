@@ -0,0 +1,150 @@
+package releasenotes
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+
+	markdownTemplate = "notes.md.tmpl"
+	htmlTemplate     = "notes.html.tmpl"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Renderer renders Documents with the default templates or overrides.
+type Renderer struct {
+	markdown *texttemplate.Template
+	html     *htmltemplate.Template
+}
+
+var funcs = map[string]any{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"date": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 UTC")
+	},
+	"event": describeEvent,
+}
+
+// NewRenderer parses the default templates, replacing each one with
+// notes.md.tmpl or notes.html.tmpl from dir when dir has it. An empty dir
+// uses the defaults only.
+func NewRenderer(dir string) (*Renderer, error) {
+	mdSource, err := templateSource(dir, markdownTemplate)
+	if err != nil {
+		return nil, err
+	}
+	md, err := texttemplate.New(markdownTemplate).Funcs(funcs).Parse(mdSource)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", markdownTemplate, err)
+	}
+
+	htmlSource, err := templateSource(dir, htmlTemplate)
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New(htmlTemplate).Funcs(funcs).Parse(htmlSource)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", htmlTemplate, err)
+	}
+
+	return &Renderer{markdown: md, html: html}, nil
+}
+
+func templateSource(dir, name string) (string, error) {
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("reading template %s: %w", name, err)
+		}
+	}
+	data, err := defaultTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return "", fmt.Errorf("reading default template %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// ParseFormat normalizes a format name; empty means Markdown.
+func ParseFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", "md", "markdown":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: use md, html or json", format)
+	}
+}
+
+// ContentType is the HTTP content type of a format.
+func ContentType(format string) string {
+	switch format {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatJSON:
+		return "application/json"
+	default:
+		return "text/markdown; charset=utf-8"
+	}
+}
+
+// Render writes doc to w in format, which ParseFormat has normalized.
+func (r *Renderer) Render(w io.Writer, doc *Document, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return r.markdown.Execute(w, doc)
+	case FormatHTML:
+		return r.html.Execute(w, doc)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func describeEvent(e Event) string {
+	switch e.Action {
+	case "approval_added":
+		return fmt.Sprintf("added %s approval", strings.ToUpper(e.Detail))
+	case "approval_revoked":
+		return fmt.Sprintf("revoked %s approval", strings.ToUpper(e.Detail))
+	case "release_declined":
+		if e.Detail != "" {
+			return "declined the release: " + e.Detail
+		}
+		return "declined the release"
+	case "freeze_overridden":
+		return "overrode the deployment freeze to " + e.Detail
+	case "status_changed":
+		text := "moved the release to " + strings.ReplaceAll(e.Detail, "_", " ")
+		if e.Reason != "" {
+			text += ": " + e.Reason
+		}
+		return text
+	default:
+		return strings.ReplaceAll(e.Action, "_", " ")
+	}
+}
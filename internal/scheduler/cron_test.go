@@ -0,0 +1,66 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/scheduler"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	type tc struct {
+		name string
+		expr string
+		from string
+		want string
+	}
+
+	cases := []tc{
+		{name: "every minute", expr: "* * * * *", from: "2026-03-02T10:15:30Z", want: "2026-03-02T10:16:00Z"},
+		{name: "step minutes", expr: "*/15 * * * *", from: "2026-03-02T10:15:00Z", want: "2026-03-02T10:30:00Z"},
+		{name: "weekday mornings skip weekend", expr: "0 9 * * 1-5", from: "2026-03-06T09:00:00Z", want: "2026-03-09T09:00:00Z"},
+		{name: "weekday names", expr: "30 8 * * mon,wed", from: "2026-03-02T09:00:00Z", want: "2026-03-04T08:30:00Z"},
+		{name: "sunday as 7", expr: "0 0 * * 7", from: "2026-03-02T00:00:00Z", want: "2026-03-08T00:00:00Z"},
+		{name: "month rollover", expr: "0 0 1 * *", from: "2026-12-15T00:00:00Z", want: "2027-01-01T00:00:00Z"},
+		{name: "day of month or weekday", expr: "0 12 13 * 5", from: "2026-03-02T00:00:00Z", want: "2026-03-06T12:00:00Z"},
+		{name: "descriptor", expr: "@daily", from: "2026-03-02T10:00:00Z", want: "2026-03-03T00:00:00Z"},
+		{name: "impossible date", expr: "0 0 30 2 *", from: "2026-03-02T00:00:00Z", want: ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sched, err := scheduler.Parse(c.expr, time.UTC)
+			require.NoError(t, err)
+
+			from, err := time.Parse(time.RFC3339, c.from)
+			require.NoError(t, err)
+
+			next := sched.Next(from)
+			if c.want == "" {
+				require.True(t, next.IsZero())
+				return
+			}
+			require.Equal(t, c.want, next.UTC().Format(time.RFC3339))
+		})
+	}
+}
+
+func TestSchedule_Next_Timezone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	sched, err := scheduler.Parse("0 9 * * *", loc)
+	require.NoError(t, err)
+
+	next := sched.Next(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, "2026-07-01T06:00:00Z", next.UTC().Format(time.RFC3339))
+}
+
+func TestParse_Failure(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		_, err := scheduler.Parse(expr, time.UTC)
+		require.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/mattermost-tools/internal/logger"
+)
+
+const (
+	defaultTickInterval = 30 * time.Second
+	// defaultMisfireGrace is how late a run may start, e.g. after a restart,
+	// before it is skipped in favour of the next slot.
+	defaultMisfireGrace = 15 * time.Minute
+)
+
+// Store persists when each job last ran so restarts neither repeat nor lose
+// a slot.
+type Store interface {
+	LastRun(ctx context.Context, job string) (time.Time, bool, error)
+	SetLastRun(ctx context.Context, job string, at time.Time) error
+}
+
+type Job struct {
+	Name     string
+	Schedule *Schedule
+	Run      func(ctx context.Context) error
+}
+
+type jobState struct {
+	job     Job
+	next    time.Time
+	running bool
+}
+
+type Scheduler struct {
+	store        Store
+	tickInterval time.Duration
+	misfireGrace time.Duration
+
+	mu     sync.Mutex
+	jobs   map[string]*jobState
+	order  []string
+	loaded bool
+	wg     sync.WaitGroup
+}
+
+func New(store Store) *Scheduler {
+	return &Scheduler{
+		store:        store,
+		tickInterval: defaultTickInterval,
+		misfireGrace: defaultMisfireGrace,
+		jobs:         make(map[string]*jobState),
+	}
+}
+
+func (s *Scheduler) SetMisfireGrace(d time.Duration) {
+	s.misfireGrace = d
+}
+
+func (s *Scheduler) Add(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("job %q added twice", job.Name)
+	}
+	s.jobs[job.Name] = &jobState{job: job}
+	s.order = append(s.order, job.Name)
+	return nil
+}
+
+// Start runs due jobs every tick until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.RunDue(ctx, time.Now())
+	for _, name := range s.order {
+		st := s.jobs[name]
+		logger.Info().Str("job", name).Time("next", st.next).Msg("Scheduled job")
+	}
+
+	ticker := time.NewTicker(s.tickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.RunDue(ctx, now)
+		}
+	}
+}
+
+// RunDue starts every job whose next slot is at or before now, each in its
+// own goroutine. The run is recorded before the job starts, so a crash mid-run
+// does not repeat it. A job still running from an earlier slot is skipped.
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		s.load(ctx, now)
+	}
+
+	for _, name := range s.order {
+		st := s.jobs[name]
+		if st.next.IsZero() || now.Before(st.next) {
+			continue
+		}
+
+		slot := st.next
+		st.next = st.job.Schedule.Next(now)
+
+		if err := s.store.SetLastRun(ctx, name, now); err != nil {
+			logger.Error().Err(err).Str("job", name).Msg("Failed to record job run, skipping")
+			continue
+		}
+
+		if late := now.Sub(slot); late > s.misfireGrace {
+			logger.Warn().Str("job", name).Time("slot", slot).Dur("late", late).Msg("Skipping missed job run")
+			continue
+		}
+		if st.running {
+			logger.Warn().Str("job", name).Msg("Previous run still in progress, skipping")
+			continue
+		}
+
+		st.running = true
+		s.wg.Add(1)
+		go s.run(ctx, st)
+	}
+}
+
+func (s *Scheduler) load(ctx context.Context, now time.Time) {
+	for _, name := range s.order {
+		st := s.jobs[name]
+		last, ok, err := s.store.LastRun(ctx, name)
+		if err != nil {
+			logger.Error().Err(err).Str("job", name).Msg("Failed to load last job run")
+		}
+		if ok {
+			st.next = st.job.Schedule.Next(last)
+		} else {
+			st.next = st.job.Schedule.Next(now)
+		}
+	}
+	s.loaded = true
+}
+
+func (s *Scheduler) run(ctx context.Context, st *jobState) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		st.running = false
+		s.mu.Unlock()
+	}()
+
+	name := st.job.Name
+	started := time.Now()
+	logger.Info().Str("job", name).Msg("Running scheduled job")
+
+	if err := st.job.Run(ctx); err != nil {
+		logger.Error().Err(err).Str("job", name).Msg("Scheduled job failed")
+		return
+	}
+	logger.Info().Str("job", name).Dur("took", time.Since(started)).Msg("Scheduled job finished")
+}
+
+// Wait blocks until all started job runs have returned.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
@@ -0,0 +1,116 @@
+package scheduler_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/scheduler"
+)
+
+func setupTestStore(t *testing.T) scheduler.Store {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&database.ScheduledJobRun{}))
+	return scheduler.NewDBStore(db)
+}
+
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *counter) run(context.Context) error {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *counter) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+func newHourlyScheduler(t *testing.T, store scheduler.Store, runs *counter) *scheduler.Scheduler {
+	sched, err := scheduler.Parse("0 * * * *", time.UTC)
+	require.NoError(t, err)
+
+	s := scheduler.New(store)
+	require.NoError(t, s.Add(scheduler.Job{Name: "hourly", Schedule: sched, Run: runs.run}))
+	return s
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	runs := &counter{}
+	start := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+
+	s := newHourlyScheduler(t, store, runs)
+	s.RunDue(ctx, start)
+	s.RunDue(ctx, start.Add(20*time.Minute))
+	s.Wait()
+	require.Equal(t, 0, runs.count())
+
+	s.RunDue(ctx, start.Add(30*time.Minute))
+	s.RunDue(ctx, start.Add(30*time.Minute+30*time.Second))
+	s.Wait()
+	require.Equal(t, 1, runs.count())
+
+	last, ok, err := store.LastRun(ctx, "hourly")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, start.Add(30*time.Minute).Unix(), last.Unix())
+}
+
+func TestScheduler_RestartDoesNotRepeatRun(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	runs := &counter{}
+	slot := time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.SetLastRun(ctx, "hourly", slot))
+
+	s := newHourlyScheduler(t, store, runs)
+	s.RunDue(ctx, slot.Add(30*time.Second))
+	s.Wait()
+	require.Equal(t, 0, runs.count())
+
+	s.RunDue(ctx, slot.Add(time.Hour))
+	s.Wait()
+	require.Equal(t, 1, runs.count())
+}
+
+func TestScheduler_SkipsRunsMissedBeyondGrace(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	runs := &counter{}
+	slot := time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.SetLastRun(ctx, "hourly", slot))
+
+	s := newHourlyScheduler(t, store, runs)
+	s.SetMisfireGrace(10 * time.Minute)
+
+	s.RunDue(ctx, slot.Add(5*time.Hour+30*time.Minute))
+	s.Wait()
+	require.Equal(t, 0, runs.count())
+
+	s.RunDue(ctx, slot.Add(6*time.Hour+time.Minute))
+	s.Wait()
+	require.Equal(t, 1, runs.count())
+}
+
+func TestScheduler_AddDuplicate(t *testing.T) {
+	s := newHourlyScheduler(t, setupTestStore(t), &counter{})
+
+	require.Error(t, s.Add(scheduler.Job{Name: "hourly"}))
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/database"
+)
+
+type dbStore struct {
+	db *gorm.DB
+}
+
+// NewDBStore keeps last-run times in the scheduled_job_runs table.
+func NewDBStore(db *gorm.DB) Store {
+	return &dbStore{db: db}
+}
+
+func (s *dbStore) LastRun(ctx context.Context, job string) (time.Time, bool, error) {
+	var run database.ScheduledJobRun
+	err := s.db.WithContext(ctx).Where("job_name = ?", job).First(&run).Error
+	if err == gorm.ErrRecordNotFound {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("getting last run: %w", err)
+	}
+	return time.Unix(run.LastRunAt, 0), true, nil
+}
+
+func (s *dbStore) SetLastRun(ctx context.Context, job string, at time.Time) error {
+	run := database.ScheduledJobRun{JobName: job, LastRunAt: at.Unix()}
+	if err := s.db.WithContext(ctx).Save(&run).Error; err != nil {
+		return fmt.Errorf("recording last run: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package summarizer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/user/mattermost-tools/pkg/github"
+)
+
+// maxKeysPerFile caps the removed API keys reported for one file.
+const maxKeysPerFile = 5
+
+var (
+	breakingSubjectRegex = regexp.MustCompile(`^[a-zA-Z]+(\([^)]*\))?!:`)
+	breakingFooterRegex  = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:\s*(.*)$`)
+	destructiveSQLRegex  = regexp.MustCompile(`(?i)\b(DROP\s+(?:TABLE|COLUMN|INDEX)|RENAME\s+(?:COLUMN|TO)|ALTER\s+COLUMN\s+\S+\s+(?:SET\s+NOT\s+NULL|TYPE))\b`)
+	protoFieldRegex      = regexp.MustCompile(`^\s*(?:optional\s+|repeated\s+|required\s+)?(?:map<[^>]+>|[\w.]+)\s+(\w+)\s*=\s*(\d+)\s*[;\[]`)
+	protoRPCRegex        = regexp.MustCompile(`^\s*rpc\s+(\w+)\s*\(`)
+	specKeyRegex         = regexp.MustCompile(`^(\s*)["']?([\w/{}.$-]+)["']?\s*:`)
+	envNameRegex         = regexp.MustCompile(`^\s*-?\s*name:\s*["']?([A-Z_][A-Z0-9_]*)["']?\s*$`)
+	envKeyRegex          = regexp.MustCompile(`^\s*([A-Z_][A-Z0-9_]*):\s`)
+)
+
+// specDocKeys are OpenAPI keys whose removal does not change the contract.
+var specDocKeys = map[string]bool{
+	"description": true, "summary": true, "example": true, "examples": true, "title": true, "externalDocs": true,
+}
+
+// Analysis is what the rule-based analyzer finds in a comparison.
+type Analysis struct {
+	IsBreaking   bool
+	HasMigration bool
+	// Reasons explains each finding in one line.
+	Reasons []string
+}
+
+// Analyze inspects commit messages and file patches for breaking-change
+// markers, migrations, removed or renamed API fields and env var changes,
+// without a model.
+func Analyze(compare *github.CompareResult) Analysis {
+	var a Analysis
+	if compare == nil {
+		return a
+	}
+
+	for _, c := range compare.Commits {
+		ref := "commit"
+		if c.SHA != "" {
+			ref = "commit " + shortSHA(c.SHA)
+		}
+		subject := strings.TrimSpace(strings.Split(c.Commit.Message, "\n")[0])
+		if breakingSubjectRegex.MatchString(subject) {
+			a.breaking("%s is marked breaking: %s", ref, subject)
+		}
+		for _, m := range breakingFooterRegex.FindAllStringSubmatch(c.Commit.Message, -1) {
+			a.breaking("%s has BREAKING CHANGE: %s", ref, strings.TrimSpace(m[1]))
+		}
+	}
+
+	for _, f := range compare.Files {
+		switch {
+		case FileRisk(f.Filename) == riskMigration:
+			a.analyzeMigration(f)
+		case path.Ext(f.Filename) == ".proto":
+			a.analyzeProto(f)
+		case FileRisk(f.Filename) == riskAPI:
+			a.analyzeSpec(f)
+		case isHelmValues(f.Filename):
+			a.analyzeHelmValues(f)
+		}
+	}
+	return a
+}
+
+func (a *Analysis) breaking(format string, args ...any) {
+	a.IsBreaking = true
+	a.note(format, args...)
+}
+
+func (a *Analysis) note(format string, args ...any) {
+	a.Reasons = appendUnique(a.Reasons, fmt.Sprintf(format, args...))
+}
+
+// Apply adds the analysis to r: it can only make r breaking or riskier.
+func (a Analysis) Apply(r Result) Result {
+	if len(a.Reasons) == 0 && !a.HasMigration {
+		return r
+	}
+	r.IsBreaking = r.IsBreaking || a.IsBreaking
+	r.HasMigration = r.HasMigration || a.HasMigration
+	r.Reasons = appendUnique(r.Reasons, a.Reasons...)
+
+	floor := RiskMedium
+	if r.IsBreaking {
+		floor = RiskHigh
+	}
+	if riskRank(r.RiskLevel) < riskRank(floor) {
+		r.RiskLevel = floor
+	}
+	return r
+}
+
+func riskRank(level string) int {
+	switch level {
+	case RiskLow:
+		return 1
+	case RiskMedium:
+		return 2
+	case RiskHigh:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func (a *Analysis) analyzeMigration(f github.FileChange) {
+	switch f.Status {
+	case "added":
+		a.HasMigration = true
+		a.note("new migration %s", f.Filename)
+	case "removed":
+		a.note("migration %s deleted", f.Filename)
+	default:
+		a.note("existing migration %s changed", f.Filename)
+	}
+
+	added, _ := patchLines(f.Patch)
+	for _, line := range added {
+		if m := destructiveSQLRegex.FindStringSubmatch(line); m != nil {
+			a.breaking("destructive migration %s: %s", f.Filename, strings.ToUpper(strings.Join(strings.Fields(m[1]), " ")))
+			return
+		}
+	}
+}
+
+func (a *Analysis) analyzeProto(f github.FileChange) {
+	if f.Status == "removed" {
+		a.breaking("proto file %s removed", f.Filename)
+		return
+	}
+
+	added, removed := patchLines(f.Patch)
+	addedByNumber := make(map[string]string)
+	addedNames := make(map[string]bool)
+	addedRPCs := make(map[string]bool)
+	for _, line := range added {
+		if m := protoFieldRegex.FindStringSubmatch(line); m != nil {
+			addedByNumber[m[2]] = m[1]
+			addedNames[m[1]] = true
+		}
+		if m := protoRPCRegex.FindStringSubmatch(line); m != nil {
+			addedRPCs[m[1]] = true
+		}
+	}
+
+	for _, line := range removed {
+		if m := protoRPCRegex.FindStringSubmatch(line); m != nil && !addedRPCs[m[1]] {
+			a.breaking("%s: rpc %s removed", f.Filename, m[1])
+			continue
+		}
+		m := protoFieldRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name, number := m[1], m[2]
+		switch newName, ok := addedByNumber[number]; {
+		case ok && newName != name:
+			a.breaking("%s: field %s (%s) renamed to %s", f.Filename, name, number, newName)
+		case ok:
+			// Same name and number: the type or options changed.
+			a.note("%s: field %s (%s) changed", f.Filename, name, number)
+		case addedNames[name]:
+			a.breaking("%s: field %s renumbered from %s", f.Filename, name, number)
+		default:
+			a.breaking("%s: field %s (%s) removed", f.Filename, name, number)
+		}
+	}
+}
+
+func (a *Analysis) analyzeSpec(f github.FileChange) {
+	ext := path.Ext(f.Filename)
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return
+	}
+	if f.Status == "removed" {
+		a.breaking("API spec %s removed", f.Filename)
+		return
+	}
+
+	added, removed := patchLines(f.Patch)
+	addedKeys := make(map[string]bool)
+	for _, line := range added {
+		if m := specKeyRegex.FindStringSubmatch(line); m != nil {
+			addedKeys[m[1]+m[2]] = true
+		}
+	}
+
+	var missing []string
+	for _, line := range removed {
+		m := specKeyRegex.FindStringSubmatch(line)
+		if m == nil || specDocKeys[m[2]] || strings.HasPrefix(m[2], "x-") || addedKeys[m[1]+m[2]] {
+			continue
+		}
+		missing = appendUnique(missing, m[2])
+	}
+	if len(missing) == 0 {
+		return
+	}
+	if len(missing) > maxKeysPerFile {
+		missing = append(missing[:maxKeysPerFile], fmt.Sprintf("%d more", len(missing)-maxKeysPerFile))
+	}
+	a.breaking("%s: removed or renamed %s", f.Filename, strings.Join(missing, ", "))
+}
+
+func (a *Analysis) analyzeHelmValues(f github.FileChange) {
+	added, removed := patchLines(f.Patch)
+	addedVars := envVars(added)
+	removedVars := envVars(removed)
+
+	var names []string
+	for name := range addedVars {
+		names = append(names, name)
+	}
+	for name := range removedVars {
+		if !addedVars[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch {
+		case addedVars[name] && removedVars[name]:
+			a.note("env var %s changed in %s", name, f.Filename)
+		case addedVars[name]:
+			a.note("env var %s added in %s", name, f.Filename)
+		default:
+			a.breaking("env var %s removed from %s", name, f.Filename)
+		}
+	}
+}
+
+func isHelmValues(filename string) bool {
+	p := strings.ToLower(filename)
+	base := path.Base(p)
+	ext := path.Ext(p)
+	return (strings.Contains(p, "helm/") || strings.Contains(p, "charts/")) &&
+		strings.HasPrefix(base, "values") && (ext == ".yaml" || ext == ".yml")
+}
+
+func envVars(lines []string) map[string]bool {
+	vars := make(map[string]bool)
+	for _, line := range lines {
+		if m := envNameRegex.FindStringSubmatch(line); m != nil {
+			vars[m[1]] = true
+		} else if m := envKeyRegex.FindStringSubmatch(line); m != nil {
+			vars[m[1]] = true
+		}
+	}
+	return vars
+}
+
+// patchLines returns the added and removed lines of a unified diff patch
+// without their +/- markers.
+func patchLines(patch string) (added, removed []string) {
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			added = append(added, line[1:])
+		case strings.HasPrefix(line, "-"):
+			removed = append(removed, line[1:])
+		}
+	}
+	return added, removed
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range list {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
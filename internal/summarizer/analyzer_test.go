@@ -0,0 +1,190 @@
+package summarizer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/summarizer"
+	"github.com/user/mattermost-tools/pkg/github"
+)
+
+func TestAnalyze(t *testing.T) {
+	type tc struct {
+		name     string
+		messages []string
+		files    []github.FileChange
+		expected summarizer.Analysis
+	}
+
+	cases := []tc{
+		{
+			name:     "nothing notable",
+			messages: []string{"feat: add login"},
+			files:    []github.FileChange{{Filename: "auth.go", Status: "modified", Patch: "-a\n+b"}},
+			expected: summarizer.Analysis{},
+		},
+		{
+			name:     "conventional commit markers",
+			messages: []string{"feat(api)!: drop v1\n\nBREAKING CHANGE: clients must use v2", "fix: typo\n\nBREAKING-CHANGE: renames flag"},
+			expected: summarizer.Analysis{IsBreaking: true, Reasons: []string{
+				"commit abc1234 is marked breaking: feat(api)!: drop v1",
+				"commit abc1234 has BREAKING CHANGE: clients must use v2",
+				"commit abc1234 has BREAKING CHANGE: renames flag",
+			}},
+		},
+		{
+			name: "new and destructive migrations",
+			files: []github.FileChange{
+				{Filename: "db/migrations/0042_add_sso.sql", Status: "added", Patch: "@@ -0,0 +1 @@\n+CREATE TABLE sso (id int);"},
+				{Filename: "db/migrations/0043_drop_name.sql", Status: "added", Patch: "@@ -0,0 +1 @@\n+ALTER TABLE users drop   column name;"},
+				{Filename: "db/migrations/0001_init.sql", Status: "modified", Patch: "-CREATE TABLE a;\n+CREATE TABLE b;"},
+			},
+			expected: summarizer.Analysis{IsBreaking: true, HasMigration: true, Reasons: []string{
+				"new migration db/migrations/0042_add_sso.sql",
+				"new migration db/migrations/0043_drop_name.sql",
+				"destructive migration db/migrations/0043_drop_name.sql: DROP COLUMN",
+				"existing migration db/migrations/0001_init.sql changed",
+			}},
+		},
+		{
+			name: "proto fields and rpcs",
+			files: []github.FileChange{{Filename: "api/user.proto", Status: "modified", Patch: "@@ -1,7 +1,6 @@\n" +
+				" message User {\n" +
+				"-  string name = 2;\n" +
+				"+  string full_name = 2;\n" +
+				"-  int64 created = 3;\n" +
+				"+  int64 created = 4;\n" +
+				"-  repeated string tags = 5;\n" +
+				"-  int32 age = 6;\n" +
+				"+  int64 age = 6;\n" +
+				"+  string email = 7;\n" +
+				" }\n" +
+				"-  rpc DeleteUser(DeleteRequest) returns (Empty);\n" +
+				"-  rpc GetUser(GetRequest) returns (User);\n" +
+				"+  rpc GetUser(GetRequest) returns (User) {}\n"}},
+			expected: summarizer.Analysis{IsBreaking: true, Reasons: []string{
+				"api/user.proto: field name (2) renamed to full_name",
+				"api/user.proto: field created renumbered from 3",
+				"api/user.proto: field tags (5) removed",
+				"api/user.proto: field age (6) changed",
+				"api/user.proto: rpc DeleteUser removed",
+			}},
+		},
+		{
+			name: "openapi keys",
+			files: []github.FileChange{{Filename: "docs/openapi.yaml", Status: "modified", Patch: "@@ -1,9 +1,8 @@\n" +
+				"   /users/{id}:\n" +
+				"-  /teams:\n" +
+				"       properties:\n" +
+				"-        username:\n" +
+				"+        login:\n" +
+				"-          description: The name\n" +
+				"+          description: The login\n" +
+				"-        email:\n" +
+				"+        email:\n"}},
+			expected: summarizer.Analysis{IsBreaking: true, Reasons: []string{
+				"docs/openapi.yaml: removed or renamed /teams, username",
+			}},
+		},
+		{
+			name:  "removed api spec",
+			files: []github.FileChange{{Filename: "api/swagger.json", Status: "removed"}},
+			expected: summarizer.Analysis{IsBreaking: true, Reasons: []string{
+				"API spec api/swagger.json removed",
+			}},
+		},
+		{
+			name: "helm env vars",
+			files: []github.FileChange{{Filename: "deploy/helm/backend/values-prod.yaml", Status: "modified", Patch: "@@ -1,6 +1,6 @@\n" +
+				" env:\n" +
+				"-  - name: LEGACY_AUTH\n" +
+				"-    value: \"true\"\n" +
+				"+  - name: SSO_ISSUER\n" +
+				"+    value: https://sso\n" +
+				"-  LOG_LEVEL: info\n" +
+				"+  LOG_LEVEL: debug\n" +
+				"-  replicas: 2\n" +
+				"+  replicas: 3\n"}},
+			expected: summarizer.Analysis{IsBreaking: true, Reasons: []string{
+				"env var LEGACY_AUTH removed from deploy/helm/backend/values-prod.yaml",
+				"env var LOG_LEVEL changed in deploy/helm/backend/values-prod.yaml",
+				"env var SSO_ISSUER added in deploy/helm/backend/values-prod.yaml",
+			}},
+		},
+		{
+			name:     "env vars outside helm values are ignored",
+			files:    []github.FileChange{{Filename: "config/app.yaml", Status: "modified", Patch: "-  LEGACY_AUTH: true"}},
+			expected: summarizer.Analysis{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			compare := &github.CompareResult{Files: c.files}
+			for _, msg := range c.messages {
+				compare.Commits = append(compare.Commits, github.Commit{SHA: "abc1234567", Commit: github.CommitData{Message: msg}})
+			}
+
+			require.Equal(t, c.expected, summarizer.Analyze(compare))
+		})
+	}
+}
+
+func TestAnalysis_Apply(t *testing.T) {
+	type tc struct {
+		name     string
+		analysis summarizer.Analysis
+		result   summarizer.Result
+		expected summarizer.Result
+	}
+
+	cases := []tc{
+		{
+			name:     "no findings",
+			result:   summarizer.Result{Summary: "Adds login.", RiskLevel: summarizer.RiskLow},
+			expected: summarizer.Result{Summary: "Adds login.", RiskLevel: summarizer.RiskLow},
+		},
+		{
+			name:     "finding raises risk",
+			analysis: summarizer.Analysis{HasMigration: true, Reasons: []string{"new migration a.sql"}},
+			result:   summarizer.Result{Summary: "Adds login.", RiskLevel: summarizer.RiskLow},
+			expected: summarizer.Result{Summary: "Adds login.", RiskLevel: summarizer.RiskMedium, HasMigration: true, Reasons: []string{"new migration a.sql"}},
+		},
+		{
+			name:     "breaking finding overrides the model",
+			analysis: summarizer.Analysis{IsBreaking: true, Reasons: []string{"env var A removed from values.yaml"}},
+			result:   summarizer.Result{Summary: "Tidies config.", RiskLevel: summarizer.RiskMedium, Reasons: []string{"env var A removed from values.yaml"}},
+			expected: summarizer.Result{Summary: "Tidies config.", IsBreaking: true, RiskLevel: summarizer.RiskHigh, Reasons: []string{"env var A removed from values.yaml"}},
+		},
+		{
+			name:     "never lowers risk",
+			analysis: summarizer.Analysis{Reasons: []string{"existing migration a.sql changed"}},
+			result:   summarizer.Result{Summary: "Drops v1.", IsBreaking: true, RiskLevel: summarizer.RiskHigh},
+			expected: summarizer.Result{Summary: "Drops v1.", IsBreaking: true, RiskLevel: summarizer.RiskHigh, Reasons: []string{"existing migration a.sql changed"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, c.analysis.Apply(c.result))
+		})
+	}
+}
+
+func TestSummarize_AnalyzesWithoutModel(t *testing.T) {
+	in := testInput()
+	in.Compare.Commits[0].Commit.Message = "feat!: drop v1 login"
+
+	result, err := summarizer.Summarize(context.Background(), &stubSummarizer{err: errors.New("boom")}, in)
+
+	require.ErrorContains(t, err, "boom")
+	require.Equal(t, summarizer.Result{
+		Summary:    "2 commits (AI summary unavailable)",
+		IsBreaking: true,
+		RiskLevel:  summarizer.RiskHigh,
+		Reasons:    []string{"commit abc1234 is marked breaking: feat!: drop v1 login"},
+	}, result)
+}
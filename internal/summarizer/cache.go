@@ -0,0 +1,198 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/logger"
+)
+
+// DefaultCacheTTL is how long a cached summary is reused.
+const DefaultCacheTTL = 7 * 24 * time.Hour
+
+// Cache stores summaries in the summaries table keyed by repo, merge base
+// SHA, head SHA and summarizer version, so a comparison is summarized once
+// whichever command asks for it.
+type Cache struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+func NewCache(db *gorm.DB, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cache{db: db, ttl: ttl}
+}
+
+// Wrap returns s with its results cached. A nil Cache returns s unchanged.
+func (c *Cache) Wrap(s Summarizer) Summarizer {
+	if c == nil {
+		return s
+	}
+	return &cachedSummarizer{cache: c, next: s}
+}
+
+// Invalidate deletes the cached summaries of repo, or of every repo when
+// repo is empty, optionally only those for headSHA. It returns how many
+// were deleted.
+func (c *Cache) Invalidate(ctx context.Context, repo, headSHA string) (int64, error) {
+	query := c.db.WithContext(ctx).Where("1 = 1")
+	if repo != "" {
+		query = query.Where("repo = ?", repo)
+	}
+	if headSHA != "" {
+		query = query.Where("head_sha = ?", headSHA)
+	}
+	result := query.Delete(&database.Summary{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("deleting summaries: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Prune deletes expired summaries and returns how many were deleted.
+func (c *Cache) Prune(ctx context.Context) (int64, error) {
+	result := c.db.WithContext(ctx).Where("created_at < ?", time.Now().Add(-c.ttl).Unix()).Delete(&database.Summary{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("pruning summaries: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+type cacheKey struct {
+	repo    string
+	baseSHA string
+	headSHA string
+	version string
+}
+
+func (c *Cache) get(ctx context.Context, key cacheKey) (*database.Summary, error) {
+	// Find instead of First: misses are routine and First logs each one.
+	var rows []database.Summary
+	err := c.db.WithContext(ctx).
+		Where("repo = ? AND base_sha = ? AND head_sha = ? AND version = ?", key.repo, key.baseSHA, key.headSHA, key.version).
+		Limit(1).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("fetching summary: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+func (c *Cache) put(ctx context.Context, key cacheKey, result Result) error {
+	existing, err := c.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	row := database.Summary{
+		Repo:          key.repo,
+		BaseSHA:       key.baseSHA,
+		HeadSHA:       key.headSHA,
+		Version:       key.version,
+		Summary:       result.Summary,
+		IsBreaking:    result.IsBreaking,
+		RiskLevel:     result.RiskLevel,
+		HasMigration:  result.HasMigration,
+		APIChanges:    encodeList(result.APIChanges),
+		ConfigChanges: encodeList(result.ConfigChanges),
+		RolloutNotes:  result.RolloutNotes,
+		CreatedAt:     time.Now().Unix(),
+	}
+	if existing != nil {
+		row.ID = existing.ID
+	}
+	if err := c.db.WithContext(ctx).Save(&row).Error; err != nil {
+		return fmt.Errorf("saving summary: %w", err)
+	}
+	return nil
+}
+
+type cachedSummarizer struct {
+	cache *Cache
+	next  Summarizer
+}
+
+func (s *cachedSummarizer) Version() string {
+	return s.next.Version()
+}
+
+func (s *cachedSummarizer) Summarize(ctx context.Context, in Input) (Result, error) {
+	key, ok := s.key(in)
+	if !ok {
+		return s.next.Summarize(ctx, in)
+	}
+
+	row, err := s.cache.get(ctx, key)
+	if err != nil {
+		logger.Warn().Err(err).Str("repo", in.Repo).Msg("Reading summary cache failed")
+	}
+	if row != nil && time.Since(time.Unix(row.CreatedAt, 0)) < s.cache.ttl {
+		return Result{
+			Summary:       row.Summary,
+			IsBreaking:    row.IsBreaking,
+			RiskLevel:     row.RiskLevel,
+			HasMigration:  row.HasMigration,
+			APIChanges:    decodeList(row.APIChanges),
+			ConfigChanges: decodeList(row.ConfigChanges),
+			RolloutNotes:  row.RolloutNotes,
+		}, nil
+	}
+
+	result, err := s.next.Summarize(ctx, in)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := s.cache.put(ctx, key, result); err != nil {
+		logger.Warn().Err(err).Str("repo", in.Repo).Msg("Writing summary cache failed")
+	}
+	return result, nil
+}
+
+// key identifies in, and reports false when the comparison lacks the SHAs
+// to cache it. Summaries that saw the diff are kept apart from those that
+// did not.
+func (s *cachedSummarizer) key(in Input) (cacheKey, bool) {
+	if in.Compare == nil {
+		return cacheKey{}, false
+	}
+	key := cacheKey{
+		repo:    in.Repo,
+		baseSHA: in.Compare.MergeBaseCommit.SHA,
+		headSHA: in.Compare.HeadSHA(),
+		version: s.next.Version(),
+	}
+	if key.baseSHA == "" || key.headSHA == "" {
+		return cacheKey{}, false
+	}
+	if in.Diff != "" {
+		key.version += "+diff"
+	}
+	return key, true
+}
+
+func encodeList(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+func decodeList(data string) []string {
+	if data == "" {
+		return nil
+	}
+	var items []string
+	_ = json.Unmarshal([]byte(data), &items)
+	return items
+}
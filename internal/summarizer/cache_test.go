@@ -0,0 +1,215 @@
+package summarizer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/database"
+	"github.com/user/mattermost-tools/internal/summarizer"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&database.Summary{}))
+	return db
+}
+
+func TestCache_ReusesSummary(t *testing.T) {
+	db := setupTestDB(t)
+	cache := summarizer.NewCache(db, 0)
+	want := summarizer.Result{
+		Summary:       "Adds login.",
+		IsBreaking:    true,
+		RiskLevel:     summarizer.RiskHigh,
+		HasMigration:  true,
+		APIChanges:    []string{"POST /login"},
+		ConfigChanges: []string{"LOGIN_URL"},
+		RolloutNotes:  "Run migrations first.",
+	}
+	stub := &stubSummarizer{result: want}
+	s := cache.Wrap(stub)
+
+	first, err := s.Summarize(context.Background(), testInput())
+	require.NoError(t, err)
+	second, err := s.Summarize(context.Background(), testInput())
+	require.NoError(t, err)
+
+	require.Equal(t, want, first)
+	require.Equal(t, want, second)
+	require.Equal(t, 1, stub.calls)
+
+	var row database.Summary
+	require.NoError(t, db.First(&row).Error)
+	require.Equal(t, "backend", row.Repo)
+	require.Equal(t, "base000000", row.BaseSHA)
+	require.Equal(t, "def7654321", row.HeadSHA)
+	require.Equal(t, "stub", row.Version)
+}
+
+func TestCache_KeyedByComparison(t *testing.T) {
+	db := setupTestDB(t)
+	stub := &stubSummarizer{result: summarizer.Result{Summary: "summary"}}
+	s := summarizer.NewCache(db, 0).Wrap(stub)
+
+	in := testInput()
+	_, err := s.Summarize(context.Background(), in)
+	require.NoError(t, err)
+
+	otherBase := testInput()
+	otherBase.Compare.MergeBaseCommit.SHA = "base111111"
+	_, err = s.Summarize(context.Background(), otherBase)
+	require.NoError(t, err)
+
+	withDiff := testInput()
+	withDiff.Diff = "+line"
+	_, err = s.Summarize(context.Background(), withDiff)
+	require.NoError(t, err)
+
+	otherRepo := testInput()
+	otherRepo.Repo = "frontend"
+	_, err = s.Summarize(context.Background(), otherRepo)
+	require.NoError(t, err)
+
+	require.Equal(t, 4, stub.calls)
+}
+
+func TestCache_SkipsWithoutSHAs(t *testing.T) {
+	db := setupTestDB(t)
+	stub := &stubSummarizer{result: summarizer.Result{Summary: "summary"}}
+	s := summarizer.NewCache(db, 0).Wrap(stub)
+
+	in := testInput()
+	in.Compare.MergeBaseCommit.SHA = ""
+	for range 2 {
+		_, err := s.Summarize(context.Background(), in)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 2, stub.calls)
+	var count int64
+	require.NoError(t, db.Model(&database.Summary{}).Count(&count).Error)
+	require.Zero(t, count)
+}
+
+func TestCache_DoesNotStoreErrors(t *testing.T) {
+	db := setupTestDB(t)
+	stub := &stubSummarizer{err: errors.New("model offline")}
+	s := summarizer.NewCache(db, 0).Wrap(stub)
+
+	_, err := s.Summarize(context.Background(), testInput())
+	require.Error(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&database.Summary{}).Count(&count).Error)
+	require.Zero(t, count)
+}
+
+func TestCache_ExpiredAndPrune(t *testing.T) {
+	db := setupTestDB(t)
+	cache := summarizer.NewCache(db, time.Hour)
+	stub := &stubSummarizer{result: summarizer.Result{Summary: "fresh"}}
+	s := cache.Wrap(stub)
+
+	require.NoError(t, db.Create(&database.Summary{
+		Repo: "backend", BaseSHA: "base000000", HeadSHA: "def7654321", Version: "stub",
+		Summary: "stale", CreatedAt: time.Now().Add(-2 * time.Hour).Unix(),
+	}).Error)
+	require.NoError(t, db.Create(&database.Summary{
+		Repo: "frontend", BaseSHA: "base000000", HeadSHA: "aaa", Version: "stub",
+		Summary: "old", CreatedAt: time.Now().Add(-2 * time.Hour).Unix(),
+	}).Error)
+
+	result, err := s.Summarize(context.Background(), testInput())
+	require.NoError(t, err)
+	require.Equal(t, "fresh", result.Summary)
+	require.Equal(t, 1, stub.calls)
+
+	pruned, err := cache.Prune(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), pruned)
+
+	var rows []database.Summary
+	require.NoError(t, db.Find(&rows).Error)
+	require.Len(t, rows, 1)
+	require.Equal(t, "fresh", rows[0].Summary)
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	type tc struct {
+		name      string
+		repo      string
+		headSHA   string
+		deleted   int64
+		remaining []string
+	}
+
+	cases := []tc{
+		{name: "everything", deleted: 3},
+		{name: "one repo", repo: "backend", deleted: 2, remaining: []string{"frontend"}},
+		{name: "one head", repo: "backend", headSHA: "h1", deleted: 1, remaining: []string{"backend", "frontend"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			now := time.Now().Unix()
+			for _, row := range []database.Summary{
+				{Repo: "backend", BaseSHA: "b", HeadSHA: "h1", Version: "stub", CreatedAt: now},
+				{Repo: "backend", BaseSHA: "b", HeadSHA: "h2", Version: "stub", CreatedAt: now},
+				{Repo: "frontend", BaseSHA: "b", HeadSHA: "h1", Version: "stub", CreatedAt: now},
+			} {
+				require.NoError(t, db.Create(&row).Error)
+			}
+
+			deleted, err := summarizer.NewCache(db, 0).Invalidate(context.Background(), c.repo, c.headSHA)
+			require.NoError(t, err)
+			require.Equal(t, c.deleted, deleted)
+
+			var remaining []string
+			require.NoError(t, db.Model(&database.Summary{}).Order("repo").Pluck("repo", &remaining).Error)
+			if len(c.remaining) == 0 {
+				require.Empty(t, remaining)
+			} else {
+				require.Equal(t, c.remaining, remaining)
+			}
+		})
+	}
+}
+
+func TestNew_CachesFallbackSeparately(t *testing.T) {
+	db := setupTestDB(t)
+	cache := summarizer.NewCache(db, 0)
+	s, err := summarizer.New(config.SummarizerConfig{Type: summarizer.TypeRules, Fallback: summarizer.TypeNone}, cache)
+	require.NoError(t, err)
+
+	_, err = s.Summarize(context.Background(), testInput())
+	require.NoError(t, err)
+
+	var row database.Summary
+	require.NoError(t, db.First(&row).Error)
+	require.Equal(t, "rules:3@diff6000", row.Version)
+}
+
+func TestNew_CacheKeyedByDiffBudget(t *testing.T) {
+	db := setupTestDB(t)
+	cache := summarizer.NewCache(db, 0)
+
+	for _, tokens := range []int{6000, 2000, 2000} {
+		s, err := summarizer.New(config.SummarizerConfig{Type: summarizer.TypeRules, DiffTokens: tokens}, cache)
+		require.NoError(t, err)
+		_, err = s.Summarize(context.Background(), testInput())
+		require.NoError(t, err)
+	}
+
+	var versions []string
+	require.NoError(t, db.Model(&database.Summary{}).Order("version").Pluck("version", &versions).Error)
+	require.Equal(t, []string{"rules:3@diff2000", "rules:3@diff6000"}, versions)
+}
@@ -0,0 +1,58 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultCLICommand = "claude"
+
+// CLI runs a command line tool with the prompt as its last argument and
+// reads the summary from stdout.
+type CLI struct {
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+// NewCLI defaults to "claude -p".
+func NewCLI(command string, args []string, timeout time.Duration) *CLI {
+	if command == "" {
+		command = defaultCLICommand
+		if args == nil {
+			args = []string{"-p"}
+		}
+	}
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	return &CLI{command: command, args: args, timeout: timeout}
+}
+
+func (c *CLI) Summarize(ctx context.Context, in Input) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	args := append(append([]string{}, c.args...), Prompt(in))
+	cmd := exec.CommandContext(ctx, c.command, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return Result{}, fmt.Errorf("%s timed out after %s", c.command, c.timeout)
+		}
+		return Result{}, fmt.Errorf("%s failed: %w (stderr: %s)", c.command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return ParseResponse(stdout.String())
+}
+
+func (c *CLI) Version() string {
+	return fmt.Sprintf("%s:%s:%s", TypeCLI, strings.Join(append([]string{c.command}, c.args...), " "), promptVersion)
+}
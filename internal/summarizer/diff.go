@@ -0,0 +1,184 @@
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultDiffTokens is the patch content budget per summary.
+	DefaultDiffTokens = 6000
+
+	// charsPerToken approximates tokens for budgeting; real tokenizers
+	// average about four characters per token on code.
+	charsPerToken = 4
+	// minPatchTokens is the smallest truncated patch worth sending.
+	minPatchTokens = 100
+)
+
+// File risk tiers; lower tiers are sent to the model first.
+const (
+	riskMigration = iota
+	riskAPI
+	riskConfig
+	riskCode
+	riskTest
+	riskGenerated
+)
+
+// FilePatch is one file's part of a diff.
+type FilePatch struct {
+	Filename string
+	Patch    string
+}
+
+// withDiffBudget fills Input.Diff with patch content, riskiest files first,
+// trimmed to a token budget.
+type withDiffBudget struct {
+	next   Summarizer
+	tokens int
+}
+
+// WithDiffBudget gives s the patch content of each comparison within tokens.
+// A caller-supplied Input.Diff is split per file and budgeted the same way;
+// otherwise the patches GitHub includes in the comparison are used.
+func WithDiffBudget(s Summarizer, tokens int) Summarizer {
+	return &withDiffBudget{next: s, tokens: tokens}
+}
+
+func (d *withDiffBudget) Version() string {
+	return fmt.Sprintf("%s@diff%d", d.next.Version(), d.tokens)
+}
+
+func (d *withDiffBudget) Summarize(ctx context.Context, in Input) (Result, error) {
+	var patches []FilePatch
+	if in.Diff != "" {
+		patches = SplitDiff(in.Diff)
+	} else if in.Compare != nil {
+		for _, f := range in.Compare.Files {
+			if f.Patch != "" {
+				patches = append(patches, FilePatch{Filename: f.Filename, Patch: f.Patch})
+			}
+		}
+	}
+	in.Diff = BudgetDiff(patches, d.tokens)
+	return d.next.Summarize(ctx, in)
+}
+
+// SplitDiff splits a unified diff, as returned by github.Client.GetDiff, into
+// its files.
+func SplitDiff(diff string) []FilePatch {
+	var patches []FilePatch
+	for _, section := range strings.Split(diff, "diff --git ") {
+		if strings.TrimSpace(section) == "" {
+			continue
+		}
+		header, body, _ := strings.Cut(section, "\n")
+		// The header is "a/<path> b/<path>"; the b side names renamed files
+		// by their new path.
+		name := header
+		if i := strings.LastIndex(header, " b/"); i >= 0 {
+			name = header[i+len(" b/"):]
+		}
+		patches = append(patches, FilePatch{Filename: name, Patch: body})
+	}
+	return patches
+}
+
+// BudgetDiff renders patches riskiest first until about tokens are used,
+// truncating the file that crosses the budget and listing the files left
+// out. It returns "" when tokens is not positive or there are no patches.
+func BudgetDiff(patches []FilePatch, tokens int) string {
+	if tokens <= 0 || len(patches) == 0 {
+		return ""
+	}
+
+	ordered := append([]FilePatch(nil), patches...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return FileRisk(ordered[i].Filename) < FileRisk(ordered[j].Filename)
+	})
+
+	var sb strings.Builder
+	var omitted []string
+	remaining := tokens * charsPerToken
+	for _, p := range ordered {
+		section := fmt.Sprintf("--- %s\n%s\n", p.Filename, strings.TrimRight(p.Patch, "\n"))
+		switch {
+		case len(section) <= remaining:
+			sb.WriteString(section)
+			remaining -= len(section)
+		case remaining >= minPatchTokens*charsPerToken:
+			cut := section[:remaining]
+			if i := strings.LastIndex(cut, "\n"); i > 0 {
+				cut = cut[:i+1]
+			}
+			sb.WriteString(cut)
+			sb.WriteString("... (patch truncated)\n")
+			remaining = 0
+		default:
+			omitted = append(omitted, p.Filename)
+		}
+	}
+
+	if len(omitted) > 0 {
+		sb.WriteString(fmt.Sprintf("... (%d files omitted: %s)\n", len(omitted), strings.Join(omitted, ", ")))
+	}
+	return sb.String()
+}
+
+// FileRisk ranks a file by how much its changes matter to a deployment:
+// migrations, then API definitions, configuration, code, tests and docs,
+// and finally lock files and generated code.
+func FileRisk(filename string) int {
+	p := strings.ToLower(filename)
+	base := path.Base(p)
+	ext := path.Ext(p)
+
+	switch {
+	case strings.Contains(p, "migration") || strings.Contains(p, "migrate/") || ext == ".sql" ||
+		base == "schema.prisma" || base == "schema.rb":
+		return riskMigration
+	case strings.Contains(p, "openapi") || strings.Contains(p, "swagger") ||
+		ext == ".proto" || ext == ".graphql" || ext == ".gql":
+		return riskAPI
+	case isGenerated(p, base):
+		return riskGenerated
+	case base == "dockerfile" || strings.HasPrefix(base, ".env") || ext == ".tf" || ext == ".tfvars" ||
+		strings.Contains(p, "helm/") || strings.Contains(p, "charts/") || strings.HasPrefix(base, "values") ||
+		strings.Contains(p, "config/") || strings.Contains(p, ".github/workflows/") ||
+		ext == ".yaml" || ext == ".yml" || ext == ".toml" || ext == ".ini":
+		return riskConfig
+	case strings.HasSuffix(p, "_test.go") || strings.Contains(p, ".test.") || strings.Contains(p, ".spec.") ||
+		strings.Contains(p, "__tests__/") || strings.HasPrefix(p, "test/") || strings.Contains(p, "/test/") ||
+		ext == ".md":
+		return riskTest
+	default:
+		return riskCode
+	}
+}
+
+func isGenerated(p, base string) bool {
+	switch base {
+	case "go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "poetry.lock", "cargo.lock", "composer.lock", "gemfile.lock":
+		return true
+	}
+	return strings.HasPrefix(p, "vendor/") || strings.Contains(p, "/vendor/") || strings.HasPrefix(p, "dist/") ||
+		strings.HasSuffix(p, ".min.js") || strings.HasSuffix(p, ".pb.go") || strings.Contains(base, "generated")
+}
+
+// fileRiskLabel names a FileRisk tier for the prompt.
+func fileRiskLabel(filename string) string {
+	switch FileRisk(filename) {
+	case riskMigration:
+		return "migration"
+	case riskAPI:
+		return "API"
+	case riskConfig:
+		return "config"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,125 @@
+package summarizer_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/summarizer"
+	"github.com/user/mattermost-tools/pkg/github"
+)
+
+type inputRecorder struct {
+	in summarizer.Input
+}
+
+func (r *inputRecorder) Summarize(_ context.Context, in summarizer.Input) (summarizer.Result, error) {
+	r.in = in
+	return summarizer.Result{Summary: "ok"}, nil
+}
+
+func (r *inputRecorder) Version() string {
+	return "recorder"
+}
+
+func TestFileRisk_Order(t *testing.T) {
+	ordered := []string{
+		"db/migrations/0042_add_users.sql",
+		"api/openapi.yaml",
+		"charts/app/values-prod.yaml",
+		"internal/auth/login.go",
+		"internal/auth/login_test.go",
+		"go.sum",
+	}
+
+	for i := 1; i < len(ordered); i++ {
+		require.Less(t, summarizer.FileRisk(ordered[i-1]), summarizer.FileRisk(ordered[i]), "%s before %s", ordered[i-1], ordered[i])
+	}
+}
+
+func TestBudgetDiff(t *testing.T) {
+	patches := []summarizer.FilePatch{
+		{Filename: "main.go", Patch: "@@ -1 +1 @@\n-old\n+new"},
+		{Filename: "migrations/001.sql", Patch: "@@ -0,0 +1 @@\n+CREATE TABLE users (id INT);"},
+	}
+
+	diff := summarizer.BudgetDiff(patches, 1000)
+
+	require.Less(t, strings.Index(diff, "--- migrations/001.sql"), strings.Index(diff, "--- main.go"))
+	require.Contains(t, diff, "+CREATE TABLE users")
+	require.NotContains(t, diff, "omitted")
+}
+
+func TestBudgetDiff_TrimsAndOmits(t *testing.T) {
+	big := strings.Repeat("+line of code\n", 200)
+	patches := []summarizer.FilePatch{
+		{Filename: "service.go", Patch: big},
+		{Filename: "README.md", Patch: "+docs"},
+		{Filename: "config/app.yaml", Patch: "+timeout: 5s"},
+	}
+
+	diff := summarizer.BudgetDiff(patches, 200)
+
+	require.True(t, strings.HasPrefix(diff, "--- config/app.yaml\n+timeout: 5s\n--- service.go\n"))
+	require.Contains(t, diff, "... (patch truncated)")
+	require.Contains(t, diff, "... (1 files omitted: README.md)")
+	require.LessOrEqual(t, len(diff), 200*4+100)
+}
+
+func TestBudgetDiff_Disabled(t *testing.T) {
+	require.Empty(t, summarizer.BudgetDiff([]summarizer.FilePatch{{Filename: "a.go", Patch: "+x"}}, 0))
+	require.Empty(t, summarizer.BudgetDiff(nil, 1000))
+}
+
+func TestSplitDiff(t *testing.T) {
+	diff := "diff --git a/old.go b/new.go\nsimilarity index 90%\n@@ -1 +1 @@\n-a\n+b\n" +
+		"diff --git a/db/001.sql b/db/001.sql\nnew file mode 100644\n@@ -0,0 +1 @@\n+CREATE TABLE t;\n"
+
+	patches := summarizer.SplitDiff(diff)
+
+	require.Len(t, patches, 2)
+	require.Equal(t, "new.go", patches[0].Filename)
+	require.Contains(t, patches[0].Patch, "+b")
+	require.Equal(t, "db/001.sql", patches[1].Filename)
+	require.Contains(t, patches[1].Patch, "+CREATE TABLE t;")
+}
+
+func TestWithDiffBudget(t *testing.T) {
+	t.Run("uses comparison patches", func(t *testing.T) {
+		rec := &inputRecorder{}
+		in := testInput()
+		in.Compare.Files = []github.FileChange{
+			{Filename: "auth.go", Patch: "+func Login() {}"},
+			{Filename: "logo.png"},
+		}
+
+		_, err := summarizer.WithDiffBudget(rec, 1000).Summarize(context.Background(), in)
+
+		require.NoError(t, err)
+		require.Equal(t, "--- auth.go\n+func Login() {}\n", rec.in.Diff)
+	})
+
+	t.Run("budgets a supplied diff", func(t *testing.T) {
+		rec := &inputRecorder{}
+		in := testInput()
+		in.Diff = "diff --git a/auth.go b/auth.go\n@@ -1 +1 @@\n+full patch\n"
+
+		_, err := summarizer.WithDiffBudget(rec, 1000).Summarize(context.Background(), in)
+
+		require.NoError(t, err)
+		require.Equal(t, "--- auth.go\n@@ -1 +1 @@\n+full patch\n", rec.in.Diff)
+	})
+
+	t.Run("negative budget sends no diff", func(t *testing.T) {
+		rec := &inputRecorder{}
+		in := testInput()
+		in.Diff = "diff --git a/auth.go b/auth.go\n+x\n"
+
+		_, err := summarizer.WithDiffBudget(rec, -1).Summarize(context.Background(), in)
+
+		require.NoError(t, err)
+		require.Empty(t, rec.in.Diff)
+	})
+}
@@ -0,0 +1,109 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -destination=mocks/http_doer_mock.go -package=mocks github.com/user/mattermost-tools/internal/summarizer HTTPDoer
+
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// OpenAI calls an OpenAI-compatible chat completions endpoint, such as a
+// local model server.
+type OpenAI struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	timeout    time.Duration
+	httpClient HTTPDoer
+}
+
+// NewOpenAI takes the API base URL including its version, e.g.
+// "http://localhost:11434/v1". apiKey may be empty for local servers.
+func NewOpenAI(baseURL, apiKey, model string, timeout time.Duration) *OpenAI {
+	return NewOpenAIWithHTTP(baseURL, apiKey, model, timeout, &http.Client{})
+}
+
+func NewOpenAIWithHTTP(baseURL, apiKey, model string, timeout time.Duration, httpClient HTTPDoer) *OpenAI {
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	return &OpenAI{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		timeout:    timeout,
+		httpClient: httpClient,
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (o *OpenAI) Summarize(ctx context.Context, in Input) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(chatRequest{
+		Model:    o.model,
+		Messages: []chatMessage{{Role: "user", Content: Prompt(in)}},
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return Result{}, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("completion API error: %d", resp.StatusCode)
+	}
+
+	var completion chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return Result{}, fmt.Errorf("decoding response: %w", err)
+	}
+	if len(completion.Choices) == 0 || strings.TrimSpace(completion.Choices[0].Message.Content) == "" {
+		return Result{}, fmt.Errorf("completion API returned no content")
+	}
+
+	return ParseResponse(completion.Choices[0].Message.Content)
+}
+
+func (o *OpenAI) Version() string {
+	return fmt.Sprintf("%s:%s:%s:%s", TypeOpenAI, o.baseURL, o.model, promptVersion)
+}
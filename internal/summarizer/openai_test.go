@@ -0,0 +1,136 @@
+package summarizer_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/summarizer"
+	"github.com/user/mattermost-tools/internal/summarizer/mocks"
+)
+
+func TestOpenAI_Summarize(t *testing.T) {
+	type tc struct {
+		name     string
+		apiKey   string
+		content  string
+		expected summarizer.Result
+	}
+
+	cases := []tc{
+		{
+			name:     "plain summary",
+			content:  "  Adds login and fixes a nil user crash.\n",
+			expected: summarizer.Result{Summary: "Adds login and fixes a nil user crash."},
+		},
+		{
+			name:   "structured summary with api key",
+			apiKey: "secret",
+			content: `{"summary": "Drops the v1 auth endpoint.", "breaking": true, "risk_level": "medium",
+				"has_db_migration": false, "api_changes": ["DELETE /v1/auth"], "config_changes": [],
+				"rollout_notes": "Deploy clients first."}`,
+			expected: summarizer.Result{
+				Summary:      "Drops the v1 auth endpoint.",
+				IsBreaking:   true,
+				RiskLevel:    summarizer.RiskHigh,
+				APIChanges:   []string{"DELETE /v1/auth"},
+				RolloutNotes: "Deploy clients first.",
+			},
+		},
+		{
+			name:     "plain breaking prefix is case-insensitive",
+			content:  "Breaking: Renames a column.",
+			expected: summarizer.Result{Summary: "Renames a column.", IsBreaking: true, RiskLevel: summarizer.RiskHigh},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+			mockHTTP.EXPECT().
+				Do(gomock.Any()).
+				DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					require.Equal(t, http.MethodPost, req.Method)
+					require.Equal(t, "http://localhost:11434/v1/chat/completions", req.URL.String())
+					if c.apiKey != "" {
+						require.Equal(t, "Bearer "+c.apiKey, req.Header.Get("Authorization"))
+					} else {
+						require.Empty(t, req.Header.Get("Authorization"))
+					}
+
+					var body struct {
+						Model    string `json:"model"`
+						Messages []struct {
+							Role    string `json:"role"`
+							Content string `json:"content"`
+						} `json:"messages"`
+					}
+					require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+					require.Equal(t, "llama3.1", body.Model)
+					require.Len(t, body.Messages, 1)
+					require.Contains(t, body.Messages[0].Content, "Repository: backend")
+
+					reply, err := json.Marshal(map[string]any{
+						"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": c.content}}},
+					})
+					require.NoError(t, err)
+					return &http.Response{
+						StatusCode: 200,
+						Body:       io.NopCloser(strings.NewReader(string(reply))),
+					}, nil
+				})
+
+			s := summarizer.NewOpenAIWithHTTP("http://localhost:11434/v1/", c.apiKey, "llama3.1", 0, mockHTTP)
+			result, err := s.Summarize(context.Background(), testInput())
+
+			require.NoError(t, err)
+			require.Equal(t, c.expected, result)
+		})
+	}
+}
+
+func TestOpenAI_Summarize_Errors(t *testing.T) {
+	type tc struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}
+
+	cases := []tc{
+		{name: "server error", status: 500, body: `{}`, wantErr: "completion API error: 500"},
+		{name: "no choices", status: 200, body: `{"choices": []}`, wantErr: "no content"},
+		{name: "empty content", status: 200, body: `{"choices": [{"message": {"content": "  "}}]}`, wantErr: "no content"},
+		{name: "invalid json", status: 200, body: `not json`, wantErr: "decoding response"},
+		{name: "structured without summary", status: 200, body: `{"choices": [{"message": {"content": "{\"breaking\": true}"}}]}`, wantErr: "no summary"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+			mockHTTP.EXPECT().
+				Do(gomock.Any()).
+				Return(&http.Response{
+					StatusCode: c.status,
+					Body:       io.NopCloser(strings.NewReader(c.body)),
+				}, nil)
+
+			s := summarizer.NewOpenAIWithHTTP("http://localhost:11434/v1", "", "llama3.1", 0, mockHTTP)
+			_, err := s.Summarize(context.Background(), testInput())
+
+			require.ErrorContains(t, err, c.wantErr)
+		})
+	}
+}
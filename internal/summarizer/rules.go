@@ -0,0 +1,50 @@
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// rulesHighlights caps the commit subjects quoted in a rules summary.
+const rulesHighlights = 3
+
+// rulesVersion is bumped when the rules summary format changes.
+const rulesVersion = "3"
+
+// Rules summarizes without a model: commit and file counts plus the first
+// commit subjects, flagged by Analyze.
+type Rules struct{}
+
+func (Rules) Summarize(_ context.Context, in Input) (Result, error) {
+	var additions, deletions int
+	hasMigration := false
+	for _, f := range in.Compare.Files {
+		additions += f.Additions
+		deletions += f.Deletions
+		if FileRisk(f.Filename) == riskMigration {
+			hasMigration = true
+		}
+	}
+
+	var subjects []string
+	for _, c := range in.Compare.Commits {
+		subject := strings.TrimSpace(strings.Split(c.Commit.Message, "\n")[0])
+		if strings.HasPrefix(subject, "Merge ") {
+			continue
+		}
+		if len(subjects) < rulesHighlights && subject != "" {
+			subjects = append(subjects, subject)
+		}
+	}
+
+	summary := fmt.Sprintf("%d commits touching %d files (+%d/-%d).", in.Compare.TotalCommits, len(in.Compare.Files), additions, deletions)
+	if len(subjects) > 0 {
+		summary += " Includes: " + strings.Join(subjects, "; ") + "."
+	}
+	return Analyze(in.Compare).Apply(normalize(Result{Summary: summary, HasMigration: hasMigration})), nil
+}
+
+func (Rules) Version() string {
+	return TypeRules + ":" + rulesVersion
+}
@@ -0,0 +1,64 @@
+package summarizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/summarizer"
+	"github.com/user/mattermost-tools/pkg/github"
+)
+
+func TestRules_Summarize(t *testing.T) {
+	type tc struct {
+		name     string
+		messages []string
+		expected summarizer.Result
+	}
+
+	cases := []tc{
+		{
+			name:     "quotes first subjects",
+			messages: []string{"feat: add login\n\nbody", "Merge pull request #1 from org/x", "fix: nil user", "chore: bump deps", "docs: readme"},
+			expected: summarizer.Result{Summary: "5 commits touching 2 files (+12/-3). Includes: feat: add login; fix: nil user; chore: bump deps.", RiskLevel: summarizer.RiskLow},
+		},
+		{
+			name:     "bang marks breaking",
+			messages: []string{"feat(api)!: drop v1 endpoints"},
+			expected: summarizer.Result{Summary: "1 commits touching 2 files (+12/-3). Includes: feat(api)!: drop v1 endpoints.", IsBreaking: true, RiskLevel: summarizer.RiskHigh,
+				Reasons: []string{"commit is marked breaking: feat(api)!: drop v1 endpoints"}},
+		},
+		{
+			name:     "footer marks breaking",
+			messages: []string{"refactor: rename column\n\nBREAKING CHANGE: users.name is now users.full_name"},
+			expected: summarizer.Result{Summary: "1 commits touching 2 files (+12/-3). Includes: refactor: rename column.", IsBreaking: true, RiskLevel: summarizer.RiskHigh,
+				Reasons: []string{"commit has BREAKING CHANGE: users.name is now users.full_name"}},
+		},
+		{
+			name:     "only merges",
+			messages: []string{"Merge branch 'main' into dev"},
+			expected: summarizer.Result{Summary: "1 commits touching 2 files (+12/-3).", RiskLevel: summarizer.RiskLow},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			compare := &github.CompareResult{
+				TotalCommits: len(c.messages),
+				Files: []github.FileChange{
+					{Filename: "a.go", Additions: 10, Deletions: 1},
+					{Filename: "b.go", Additions: 2, Deletions: 2},
+				},
+			}
+			for _, msg := range c.messages {
+				compare.Commits = append(compare.Commits, github.Commit{Commit: github.CommitData{Message: msg}})
+			}
+
+			result, err := summarizer.Rules{}.Summarize(context.Background(), summarizer.Input{Repo: "backend", Compare: compare})
+
+			require.NoError(t, err)
+			require.Equal(t, c.expected, result)
+		})
+	}
+}
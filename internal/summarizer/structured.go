@@ -0,0 +1,210 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+var (
+	codeFenceRegex     = regexp.MustCompile("(?s)^```[a-zA-Z]*\\s*(.*?)\\s*```$")
+	trailingCommaRegex = regexp.MustCompile(`,\s*([}\]])`)
+)
+
+// structuredResponse is the JSON object Prompt asks for. Models do not always
+// follow the schema exactly, so booleans and lists accept a few shapes.
+type structuredResponse struct {
+	Summary        string   `json:"summary"`
+	Breaking       flexBool `json:"breaking"`
+	RiskLevel      string   `json:"risk_level"`
+	HasDBMigration flexBool `json:"has_db_migration"`
+	APIChanges     flexList `json:"api_changes"`
+	ConfigChanges  flexList `json:"config_changes"`
+	RolloutNotes   string   `json:"rollout_notes"`
+}
+
+// flexBool reads true, "true" or "yes".
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = flexBool(v)
+	case string:
+		s := strings.ToLower(strings.TrimSpace(v))
+		*b = s == "true" || s == "yes"
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// flexList reads a list of strings or a single string, dropping empty
+// entries and placeholders such as "none".
+type flexList []string
+
+func (l *flexList) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var items []string
+	switch v := v.(type) {
+	case string:
+		items = []string{v}
+	case []any:
+		for _, item := range v {
+			switch item := item.(type) {
+			case string:
+				items = append(items, item)
+			case nil:
+			default:
+				items = append(items, fmt.Sprint(item))
+			}
+		}
+	}
+
+	*l = nil
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		switch strings.ToLower(item) {
+		case "", "none", "n/a", "no", "-":
+			continue
+		}
+		*l = append(*l, item)
+	}
+	return nil
+}
+
+// ParseResponse reads a model reply to Prompt. It expects the JSON object
+// Prompt asks for, repairing code fences, surrounding prose and trailing
+// commas; replies that are not JSON are read as plain summaries, which start
+// with "BREAKING:" for breaking changes.
+func ParseResponse(text string) (Result, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Result{}, errors.New("empty response")
+	}
+
+	if obj, ok := extractJSON(text); ok {
+		var resp structuredResponse
+		if err := json.Unmarshal([]byte(obj), &resp); err == nil {
+			if strings.TrimSpace(resp.Summary) == "" {
+				return Result{}, errors.New("response has no summary")
+			}
+			return normalize(Result{
+				Summary:       strings.TrimSpace(resp.Summary),
+				IsBreaking:    bool(resp.Breaking),
+				RiskLevel:     resp.RiskLevel,
+				HasMigration:  bool(resp.HasDBMigration),
+				APIChanges:    resp.APIChanges,
+				ConfigChanges: resp.ConfigChanges,
+				RolloutNotes:  strings.TrimSpace(resp.RolloutNotes),
+			}), nil
+		}
+	}
+
+	// A plain reply says nothing about risk unless it is breaking.
+	if len(text) >= len("BREAKING:") && strings.EqualFold(text[:len("BREAKING:")], "BREAKING:") {
+		return Result{Summary: strings.TrimSpace(text[len("BREAKING:"):]), IsBreaking: true, RiskLevel: RiskHigh}, nil
+	}
+	return Result{Summary: text}, nil
+}
+
+// extractJSON finds the JSON object in a reply.
+func extractJSON(text string) (string, bool) {
+	if m := codeFenceRegex.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return "", false
+	}
+	return trailingCommaRegex.ReplaceAllString(text[start:end+1], "$1"), true
+}
+
+// normalize maps the risk level onto RiskLow, RiskMedium or RiskHigh,
+// deriving it from the other fields when it is missing or unrecognised. A
+// breaking change is always high risk.
+func normalize(r Result) Result {
+	switch strings.ToLower(strings.TrimSpace(r.RiskLevel)) {
+	case "low", "minimal", "none":
+		r.RiskLevel = RiskLow
+	case "medium", "moderate":
+		r.RiskLevel = RiskMedium
+	case "high", "critical", "severe":
+		r.RiskLevel = RiskHigh
+	default:
+		r.RiskLevel = RiskLow
+		if r.HasMigration || len(r.APIChanges) > 0 || len(r.ConfigChanges) > 0 {
+			r.RiskLevel = RiskMedium
+		}
+	}
+	if r.IsBreaking {
+		r.RiskLevel = RiskHigh
+	}
+	return r
+}
+
+// Badges labels the notable parts of r for chat messages.
+func (r Result) Badges() []string {
+	var badges []string
+	if r.IsBreaking {
+		badges = append(badges, "🚨 breaking")
+	}
+	switch r.RiskLevel {
+	case RiskHigh:
+		badges = append(badges, "🔴 high risk")
+	case RiskMedium:
+		badges = append(badges, "🟠 medium risk")
+	case RiskLow:
+		badges = append(badges, "🟢 low risk")
+	}
+	if r.HasMigration {
+		badges = append(badges, "🗄️ DB migration")
+	}
+	if len(r.APIChanges) > 0 {
+		badges = append(badges, "🔌 API changes")
+	}
+	if len(r.ConfigChanges) > 0 {
+		badges = append(badges, "⚙️ config changes")
+	}
+	return badges
+}
+
+// Markdown renders r for chat: badges, the summary, and the API, config,
+// rollout and analyzer details when present.
+func (r Result) Markdown() string {
+	var sb strings.Builder
+	if badges := r.Badges(); len(badges) > 0 {
+		sb.WriteString("`" + strings.Join(badges, "` `") + "`\n")
+	}
+	sb.WriteString(r.Summary)
+	if len(r.APIChanges) > 0 {
+		sb.WriteString("\n**API:** " + strings.Join(r.APIChanges, "; "))
+	}
+	if len(r.ConfigChanges) > 0 {
+		sb.WriteString("\n**Config:** " + strings.Join(r.ConfigChanges, "; "))
+	}
+	if r.RolloutNotes != "" {
+		sb.WriteString("\n**Rollout:** " + r.RolloutNotes)
+	}
+	if len(r.Reasons) > 0 {
+		sb.WriteString("\n**Flagged:** " + strings.Join(r.Reasons, "; "))
+	}
+	return sb.String()
+}
@@ -0,0 +1,99 @@
+package summarizer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/summarizer"
+)
+
+func TestParseResponse(t *testing.T) {
+	type tc struct {
+		name     string
+		text     string
+		expected summarizer.Result
+		wantErr  string
+	}
+
+	cases := []tc{
+		{
+			name: "full object",
+			text: `{"summary": "Adds SSO.", "breaking": false, "risk_level": "low", "has_db_migration": true,
+				"api_changes": ["POST /sso/callback"], "config_changes": ["SSO_ISSUER"], "rollout_notes": "Set SSO_ISSUER first."}`,
+			expected: summarizer.Result{
+				Summary:       "Adds SSO.",
+				RiskLevel:     summarizer.RiskLow,
+				HasMigration:  true,
+				APIChanges:    []string{"POST /sso/callback"},
+				ConfigChanges: []string{"SSO_ISSUER"},
+				RolloutNotes:  "Set SSO_ISSUER first.",
+			},
+		},
+		{
+			name:     "code fence and trailing comma",
+			text:     "```json\n{\"summary\": \"Fixes a crash.\", \"risk_level\": \"Moderate\",}\n```",
+			expected: summarizer.Result{Summary: "Fixes a crash.", RiskLevel: summarizer.RiskMedium},
+		},
+		{
+			name:     "surrounding prose",
+			text:     "Here is the analysis:\n{\"summary\": \"Renames a flag.\", \"config_changes\": \"OLD_FLAG renamed to NEW_FLAG\"}\nThanks!",
+			expected: summarizer.Result{Summary: "Renames a flag.", RiskLevel: summarizer.RiskMedium, ConfigChanges: []string{"OLD_FLAG renamed to NEW_FLAG"}},
+		},
+		{
+			name:     "loose types and placeholders",
+			text:     `{"summary": "Drops a column.", "breaking": "yes", "risk_level": "unknown", "api_changes": ["none", ""], "has_db_migration": "true"}`,
+			expected: summarizer.Result{Summary: "Drops a column.", IsBreaking: true, RiskLevel: summarizer.RiskHigh, HasMigration: true},
+		},
+		{
+			name:     "plain text",
+			text:     "Just some fixes.",
+			expected: summarizer.Result{Summary: "Just some fixes."},
+		},
+		{
+			name:     "plain breaking text",
+			text:     "BREAKING: Removes the v1 API.",
+			expected: summarizer.Result{Summary: "Removes the v1 API.", IsBreaking: true, RiskLevel: summarizer.RiskHigh},
+		},
+		{name: "empty", text: "  \n", wantErr: "empty response"},
+		{name: "object without summary", text: `{"breaking": true}`, wantErr: "no summary"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := summarizer.ParseResponse(c.text)
+			if c.wantErr != "" {
+				require.ErrorContains(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, result)
+		})
+	}
+}
+
+func TestResult_Markdown(t *testing.T) {
+	result := summarizer.Result{
+		Summary:      "Drops the v1 API.",
+		IsBreaking:   true,
+		RiskLevel:    summarizer.RiskHigh,
+		HasMigration: true,
+		APIChanges:   []string{"DELETE /v1/users", "DELETE /v1/teams"},
+		RolloutNotes: "Deploy clients first.",
+		Reasons:      []string{"api/user.proto: rpc DeleteUser removed"},
+	}
+
+	require.Equal(t, []string{"🚨 breaking", "🔴 high risk", "🗄️ DB migration", "🔌 API changes"}, result.Badges())
+	require.Equal(t, "`🚨 breaking` `🔴 high risk` `🗄️ DB migration` `🔌 API changes`\n"+
+		"Drops the v1 API.\n"+
+		"**API:** DELETE /v1/users; DELETE /v1/teams\n"+
+		"**Rollout:** Deploy clients first.\n"+
+		"**Flagged:** api/user.proto: rpc DeleteUser removed", result.Markdown())
+}
+
+func TestResult_Markdown_NoBadges(t *testing.T) {
+	result := summarizer.Result{Summary: "3 commits (AI summary unavailable)"}
+
+	require.Empty(t, result.Badges())
+	require.Equal(t, "3 commits (AI summary unavailable)", result.Markdown())
+}
@@ -0,0 +1,232 @@
+// Package summarizer turns branch comparisons into short change summaries,
+// using an AI backend or deterministic rules.
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/logger"
+	"github.com/user/mattermost-tools/pkg/github"
+)
+
+const (
+	TypeCLI    = "cli"
+	TypeOpenAI = "openai"
+	TypeRules  = "rules"
+	TypeNone   = "none"
+
+	defaultTimeout = 60 * time.Second
+
+	// maxDescriptionLen caps the pull request description in the prompt.
+	maxDescriptionLen = 2000
+
+	// promptVersion is part of the AI backends' Version; bump it when Prompt
+	// changes so cached summaries are regenerated.
+	promptVersion = "3"
+)
+
+// Input is a comparison to summarize.
+type Input struct {
+	Repo    string
+	Compare *github.CompareResult
+	// Diff is the optional patch content. Summarizers built by New budget it
+	// and, when it is empty, fill it from the comparison's file patches.
+	Diff string
+	// Title and Description describe the pull request being summarized, if
+	// any.
+	Title       string
+	Description string
+}
+
+type Result struct {
+	Summary    string
+	IsBreaking bool
+	// RiskLevel is RiskLow, RiskMedium or RiskHigh, or "" when unknown.
+	RiskLevel    string
+	HasMigration bool
+	// APIChanges and ConfigChanges list notable changes in one line each.
+	APIChanges    []string
+	ConfigChanges []string
+	RolloutNotes  string
+	// Reasons are the rule-based analyzer's findings, one line each.
+	Reasons []string
+}
+
+type Summarizer interface {
+	Summarize(ctx context.Context, in Input) (Result, error)
+	// Version identifies the backend and its settings; summaries are cached
+	// per version.
+	Version() string
+}
+
+// New builds the summarizer selected by cfg, wrapped with its fallback.
+// The default is the claude CLI without a fallback. Every summary gets patch
+// content within cfg.DiffTokens, and each backend's results are stored in
+// cache unless it is nil, keyed by the backend and the budget.
+func New(cfg config.SummarizerConfig, cache *Cache) (Summarizer, error) {
+	tokens := cfg.DiffTokens
+	if tokens == 0 {
+		tokens = DefaultDiffTokens
+	}
+
+	primary, err := newOfType(cfg.Type, cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := cache.Wrap(WithDiffBudget(primary, tokens))
+	if cfg.Fallback != "" && cfg.Fallback != cfg.Type {
+		fallback, err := newOfType(cfg.Fallback, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("fallback: %w", err)
+		}
+		s = WithFallback(s, cache.Wrap(WithDiffBudget(fallback, tokens)))
+	}
+	return s, nil
+}
+
+func newOfType(typ string, cfg config.SummarizerConfig) (Summarizer, error) {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	switch typ {
+	case "", TypeCLI:
+		return NewCLI(cfg.CLI.Command, cfg.CLI.Args, timeout), nil
+	case TypeOpenAI:
+		if cfg.OpenAI.BaseURL == "" || cfg.OpenAI.Model == "" {
+			return nil, fmt.Errorf("summarizer type %q needs openai.base_url and openai.model", typ)
+		}
+		return NewOpenAI(cfg.OpenAI.BaseURL, cfg.OpenAI.APIKey, cfg.OpenAI.Model, timeout), nil
+	case TypeRules:
+		return Rules{}, nil
+	case TypeNone:
+		return Noop{}, nil
+	default:
+		return nil, fmt.Errorf("unknown summarizer type %q", typ)
+	}
+}
+
+// Unavailable is the result shown when summarizing failed.
+func Unavailable(in Input) Result {
+	return Result{Summary: fmt.Sprintf("%d commits (AI summary unavailable)", in.Compare.TotalCommits)}
+}
+
+// Summarize runs s and adds the rule-based Analyze findings, so breaking
+// changes are flagged even without a model. On error it returns Unavailable
+// alongside the error so callers always have something to show.
+func Summarize(ctx context.Context, s Summarizer, in Input) (Result, error) {
+	analysis := Analyze(in.Compare)
+	result, err := s.Summarize(ctx, in)
+	if err != nil {
+		return analysis.Apply(Unavailable(in)), fmt.Errorf("summarizing %s: %w", in.Repo, err)
+	}
+	return analysis.Apply(result), nil
+}
+
+type fallbackSummarizer struct {
+	primary  Summarizer
+	fallback Summarizer
+}
+
+// WithFallback uses fallback whenever primary fails.
+func WithFallback(primary, fallback Summarizer) Summarizer {
+	return &fallbackSummarizer{primary: primary, fallback: fallback}
+}
+
+func (f *fallbackSummarizer) Summarize(ctx context.Context, in Input) (Result, error) {
+	result, err := f.primary.Summarize(ctx, in)
+	if err == nil {
+		return result, nil
+	}
+	logger.Warn().Err(err).Str("repo", in.Repo).Msg("Summarizer failed, using fallback")
+	return f.fallback.Summarize(ctx, in)
+}
+
+func (f *fallbackSummarizer) Version() string {
+	return f.primary.Version() + "|" + f.fallback.Version()
+}
+
+// Noop summarizes nothing and reports the commit count.
+type Noop struct{}
+
+func (Noop) Summarize(_ context.Context, in Input) (Result, error) {
+	return Result{Summary: fmt.Sprintf("%d commits", in.Compare.TotalCommits)}, nil
+}
+
+func (Noop) Version() string {
+	return TypeNone
+}
+
+// Prompt is the instruction sent to AI backends.
+func Prompt(in Input) string {
+	var commitInfo strings.Builder
+	commitInfo.WriteString(fmt.Sprintf("Repository: %s\n", in.Repo))
+	if in.Title != "" {
+		commitInfo.WriteString(fmt.Sprintf("Pull request: %s\n", in.Title))
+		if desc := strings.TrimSpace(in.Description); desc != "" {
+			commitInfo.WriteString("Description:\n" + truncate(desc, maxDescriptionLen) + "\n")
+		}
+	}
+	commitInfo.WriteString(fmt.Sprintf("Total commits: %d\n\n", in.Compare.TotalCommits))
+
+	commitInfo.WriteString("Commits:\n")
+	for _, c := range in.Compare.Commits {
+		msg := strings.Split(c.Commit.Message, "\n")[0]
+		commitInfo.WriteString(fmt.Sprintf("- %s: %s\n", shortSHA(c.SHA), msg))
+	}
+
+	commitInfo.WriteString("\nFiles changed:\n")
+	for _, f := range in.Compare.Files {
+		label := ""
+		if risk := fileRiskLabel(f.Filename); risk != "" {
+			label = " [" + risk + "]"
+		}
+		commitInfo.WriteString(fmt.Sprintf("- %s (%s, +%d/-%d)%s\n", f.Filename, f.Status, f.Additions, f.Deletions, label))
+	}
+
+	instructions := "Base the summary on the commit messages and file names."
+	if in.Diff != "" {
+		commitInfo.WriteString("\nDiff (riskiest files first, may be truncated):\n")
+		commitInfo.WriteString(in.Diff)
+		instructions = "Base the summary on what the diff actually changes; commit messages may be vague or misleading."
+	}
+
+	return fmt.Sprintf(`Analyze these git changes for a release review.
+%s
+Respond with only a JSON object, without code fences or any other text:
+{
+  "summary": "2-3 sentences on the features and fixes included",
+  "breaking": true if clients, other services or operators must change something,
+  "risk_level": "low", "medium" or "high",
+  "has_db_migration": true if database migrations or schema changes are included,
+  "api_changes": ["one entry per added, removed or changed endpoint, field or message"],
+  "config_changes": ["one entry per added, removed or renamed config key or environment variable"],
+  "rollout_notes": "deployment order, flags or manual steps needed, or an empty string"
+}
+
+%s`, instructions, commitInfo.String())
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
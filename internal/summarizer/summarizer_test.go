@@ -0,0 +1,156 @@
+package summarizer_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/internal/config"
+	"github.com/user/mattermost-tools/internal/summarizer"
+	"github.com/user/mattermost-tools/pkg/github"
+)
+
+type stubSummarizer struct {
+	result summarizer.Result
+	err    error
+	calls  int
+}
+
+func (s *stubSummarizer) Summarize(_ context.Context, _ summarizer.Input) (summarizer.Result, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func (s *stubSummarizer) Version() string {
+	return "stub"
+}
+
+func testInput() summarizer.Input {
+	return summarizer.Input{
+		Repo: "backend",
+		Compare: &github.CompareResult{
+			TotalCommits:    2,
+			MergeBaseCommit: github.Commit{SHA: "base000000"},
+			Commits: []github.Commit{
+				{SHA: "abc1234567", Commit: github.CommitData{Message: "feat: add login\n\nDetails"}},
+				{SHA: "def7654321", Commit: github.CommitData{Message: "fix: handle nil user"}},
+			},
+			Files: []github.FileChange{
+				{Filename: "auth.go", Status: "modified", Additions: 10, Deletions: 2},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	type tc struct {
+		name    string
+		cfg     config.SummarizerConfig
+		wantErr string
+	}
+
+	cases := []tc{
+		{name: "default is cli", cfg: config.SummarizerConfig{}},
+		{name: "rules", cfg: config.SummarizerConfig{Type: summarizer.TypeRules}},
+		{name: "none", cfg: config.SummarizerConfig{Type: summarizer.TypeNone}},
+		{name: "cli with rules fallback", cfg: config.SummarizerConfig{Type: summarizer.TypeCLI, Fallback: summarizer.TypeRules}},
+		{
+			name: "openai",
+			cfg: config.SummarizerConfig{Type: summarizer.TypeOpenAI, OpenAI: config.OpenAIConfig{
+				BaseURL: "http://localhost:11434/v1", Model: "llama3.1",
+			}},
+		},
+		{name: "openai without model", cfg: config.SummarizerConfig{Type: summarizer.TypeOpenAI}, wantErr: "needs openai.base_url"},
+		{name: "unknown type", cfg: config.SummarizerConfig{Type: "magic"}, wantErr: `unknown summarizer type "magic"`},
+		{name: "unknown fallback", cfg: config.SummarizerConfig{Type: summarizer.TypeRules, Fallback: "magic"}, wantErr: "fallback: unknown"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := summarizer.New(c.cfg, nil)
+			if c.wantErr != "" {
+				require.ErrorContains(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, s)
+		})
+	}
+}
+
+func TestWithFallback(t *testing.T) {
+	primary := &stubSummarizer{err: errors.New("model offline")}
+	fallback := &stubSummarizer{result: summarizer.Result{Summary: "fallback summary"}}
+
+	result, err := summarizer.WithFallback(primary, fallback).Summarize(context.Background(), testInput())
+
+	require.NoError(t, err)
+	require.Equal(t, "fallback summary", result.Summary)
+	require.Equal(t, 1, primary.calls)
+	require.Equal(t, 1, fallback.calls)
+}
+
+func TestWithFallback_PrimarySucceeds(t *testing.T) {
+	primary := &stubSummarizer{result: summarizer.Result{Summary: "primary summary", IsBreaking: true}}
+	fallback := &stubSummarizer{}
+
+	result, err := summarizer.WithFallback(primary, fallback).Summarize(context.Background(), testInput())
+
+	require.NoError(t, err)
+	require.Equal(t, summarizer.Result{Summary: "primary summary", IsBreaking: true}, result)
+	require.Equal(t, 0, fallback.calls)
+}
+
+func TestSummarize_Unavailable(t *testing.T) {
+	result, err := summarizer.Summarize(context.Background(), &stubSummarizer{err: errors.New("boom")}, testInput())
+
+	require.ErrorContains(t, err, "summarizing backend: boom")
+	require.Equal(t, "2 commits (AI summary unavailable)", result.Summary)
+	require.False(t, result.IsBreaking)
+}
+
+func TestNoop(t *testing.T) {
+	result, err := summarizer.Noop{}.Summarize(context.Background(), testInput())
+
+	require.NoError(t, err)
+	require.Equal(t, "2 commits", result.Summary)
+}
+
+func TestPrompt(t *testing.T) {
+	in := testInput()
+	in.Diff = "+added line"
+
+	prompt := summarizer.Prompt(in)
+
+	require.Contains(t, prompt, "Repository: backend")
+	require.Contains(t, prompt, "- abc1234: feat: add login\n")
+	require.Contains(t, prompt, "- auth.go (modified, +10/-2)")
+	require.Contains(t, prompt, "(riskiest files first, may be truncated):\n+added line")
+	require.Contains(t, prompt, "what the diff actually changes")
+	require.NotContains(t, prompt, "Details")
+}
+
+func TestPrompt_PullRequest(t *testing.T) {
+	in := testInput()
+	in.Title = "feat: add SSO"
+	in.Description = "  Adds SSO login behind a flag.\n"
+
+	prompt := summarizer.Prompt(in)
+
+	require.Contains(t, prompt, "Pull request: feat: add SSO\nDescription:\nAdds SSO login behind a flag.\nTotal commits: 2")
+}
+
+func TestPrompt_TruncatesDescriptionOnRuneBoundary(t *testing.T) {
+	in := testInput()
+	in.Title = "feat: localize"
+	in.Description = "a" + strings.Repeat("ї", 2000)
+
+	prompt := summarizer.Prompt(in)
+
+	require.True(t, utf8.ValidString(prompt))
+	require.Contains(t, prompt, "їїї…\nTotal commits: 2")
+}
@@ -244,6 +244,34 @@ func (c *Client) FindPullRequest(ctx context.Context, owner, repo, head, base st
 	return &prs[0], nil
 }
 
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo, number string) (*PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%s", c.baseURL, owner, repo, number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
+	}
+
+	var pr PullRequest
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return &pr, nil
+}
+
 func (c *Client) CompareBranches(ctx context.Context, owner, repo, base, head string) (*CompareResult, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s", c.baseURL, owner, repo, base, head)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -413,3 +441,104 @@ func (c *Client) GetJobLogs(ctx context.Context, owner, repo string, jobID int64
 	buf.ReadFrom(resp.Body)
 	return buf.String(), nil
 }
+
+// GetUser returns the user with the given login, or nil if it does not exist.
+func (c *Client) GetUser(ctx context.Context, login string) (*User, error) {
+	url := fmt.Sprintf("%s/users/%s", c.baseURL, login)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
+	}
+
+	var user User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return &user, nil
+}
+
+// ListUserGists returns the user's most recent public gists.
+func (c *Client) ListUserGists(ctx context.Context, login string) ([]Gist, error) {
+	url := fmt.Sprintf("%s/users/%s/gists?per_page=30", c.baseURL, login)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
+	}
+
+	var gists []Gist
+	if err := json.NewDecoder(resp.Body).Decode(&gists); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return gists, nil
+}
+
+// CommitStatus is a status posted on a commit, shown as a check on pull
+// requests. State is "error", "failure", "pending" or "success".
+type CommitStatus struct {
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// SetCommitStatus creates a status on sha, replacing the previous status
+// with the same context.
+func (c *Client) SetCommitStatus(ctx context.Context, owner, repo, sha string, status CommitStatus) error {
+	body, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("encoding status: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/statuses/%s", c.baseURL, owner, repo, sha)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("GitHub API error: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -259,3 +259,164 @@ func TestClient_FindPullRequest_Failure(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetPullRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	responseBody := `{"number": 7, "title": "feat: add SSO", "body": "Adds SSO login.",
+		"head": {"ref": "feat/sso", "sha": "head123"}, "base": {"ref": "main", "sha": "base456"}}`
+
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "https://api.github.com/repos/org/repo/pulls/7", req.URL.String())
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(responseBody)),
+			}, nil
+		})
+
+	client := github.NewClientWithHTTP("test-token", mockHTTP)
+	pr, err := client.GetPullRequest(context.Background(), "org", "repo", "7")
+
+	require.NoError(t, err)
+	require.Equal(t, "feat: add SSO", pr.Title)
+	require.Equal(t, "Adds SSO login.", pr.Body)
+	require.Equal(t, github.PRBranch{Ref: "feat/sso", SHA: "head123"}, pr.Head)
+	require.Equal(t, github.PRBranch{Ref: "main", SHA: "base456"}, pr.Base)
+}
+
+func TestClient_GetUser(t *testing.T) {
+	type tc struct {
+		name       string
+		statusCode int
+		body       string
+		wantLogin  string
+		wantErr    bool
+	}
+
+	cases := []tc{
+		{
+			name:       "existing user",
+			statusCode: 200,
+			body:       `{"login": "Octocat", "html_url": "https://github.com/Octocat"}`,
+			wantLogin:  "Octocat",
+		},
+		{
+			name:       "unknown user",
+			statusCode: 404,
+			body:       `{"message": "Not Found"}`,
+		},
+		{
+			name:       "api error",
+			statusCode: 500,
+			body:       `{}`,
+			wantErr:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+			mockHTTP.EXPECT().
+				Do(gomock.Any()).
+				DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					require.Equal(t, "https://api.github.com/users/octocat", req.URL.String())
+					return &http.Response{
+						StatusCode: c.statusCode,
+						Body:       io.NopCloser(strings.NewReader(c.body)),
+					}, nil
+				})
+
+			client := github.NewClientWithHTTP("test-token", mockHTTP)
+			user, err := client.GetUser(context.Background(), "octocat")
+
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if c.wantLogin == "" {
+				require.Nil(t, user)
+				return
+			}
+			require.Equal(t, c.wantLogin, user.Login)
+		})
+	}
+}
+
+func TestClient_ListUserGists_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "https://api.github.com/users/octocat/gists?per_page=30", req.URL.String())
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(`[{"id": "abc", "description": "mmtools-link-123", "public": true}]`)),
+			}, nil
+		})
+
+	client := github.NewClientWithHTTP("test-token", mockHTTP)
+	gists, err := client.ListUserGists(context.Background(), "octocat")
+
+	require.NoError(t, err)
+	require.Len(t, gists, 1)
+	require.Equal(t, "mmtools-link-123", gists[0].Description)
+}
+
+func TestClient_SetCommitStatus(t *testing.T) {
+	type tc struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}
+
+	cases := []tc{
+		{name: "created", statusCode: 201},
+		{name: "unprocessable", statusCode: 422, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+			mockHTTP.EXPECT().
+				Do(gomock.Any()).
+				DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					require.Equal(t, http.MethodPost, req.Method)
+					require.Equal(t, "/repos/org/repo/statuses/abc123", req.URL.Path)
+					body, err := io.ReadAll(req.Body)
+					require.NoError(t, err)
+					require.JSONEq(t, `{"state":"failure","context":"deployment-freeze","description":"Frozen"}`, string(body))
+					return &http.Response{
+						StatusCode: c.statusCode,
+						Body:       io.NopCloser(strings.NewReader(`{}`)),
+					}, nil
+				})
+
+			client := github.NewClientWithHTTP("test-token", mockHTTP)
+			err := client.SetCommitStatus(context.Background(), "org", "repo", "abc123", github.CommitStatus{
+				State:       "failure",
+				Context:     "deployment-freeze",
+				Description: "Frozen",
+			})
+
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -10,7 +10,15 @@ type Repository struct {
 }
 
 type User struct {
-	Login string `json:"login"`
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url,omitempty"`
+}
+
+type Gist struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	HTMLURL     string `json:"html_url"`
+	Public      bool   `json:"public"`
 }
 
 type Team struct {
@@ -21,6 +29,7 @@ type Team struct {
 type PullRequest struct {
 	Number             int       `json:"number"`
 	Title              string    `json:"title"`
+	Body               string    `json:"body"`
 	HTMLURL            string    `json:"html_url"`
 	State              string    `json:"state"`
 	Draft              bool      `json:"draft"`
@@ -32,6 +41,14 @@ type PullRequest struct {
 	User               User      `json:"user"`
 	RequestedReviewers []User    `json:"requested_reviewers"`
 	RequestedTeams     []Team    `json:"requested_teams"`
+	Head               PRBranch  `json:"head"`
+	Base               PRBranch  `json:"base"`
+}
+
+// PRBranch is the head or base of a pull request.
+type PRBranch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
 }
 
 type IssueComment struct {
@@ -43,18 +60,37 @@ type IssueComment struct {
 }
 
 type CompareResult struct {
-	Status       string       `json:"status"`
-	AheadBy      int          `json:"ahead_by"`
-	BehindBy     int          `json:"behind_by"`
-	TotalCommits int          `json:"total_commits"`
-	Commits      []Commit     `json:"commits"`
-	Files        []FileChange `json:"files"`
+	Status          string       `json:"status"`
+	AheadBy         int          `json:"ahead_by"`
+	BehindBy        int          `json:"behind_by"`
+	TotalCommits    int          `json:"total_commits"`
+	MergeBaseCommit Commit       `json:"merge_base_commit"`
+	Commits         []Commit     `json:"commits"`
+	Files           []FileChange `json:"files"`
+}
+
+// HeadSHA is the last commit of the comparison, or "" when there is none.
+func (c *CompareResult) HeadSHA() string {
+	if len(c.Commits) == 0 {
+		return ""
+	}
+	return c.Commits[len(c.Commits)-1].SHA
 }
 
 type Commit struct {
-	SHA    string     `json:"sha"`
-	Commit CommitData `json:"commit"`
-	Author User       `json:"author"`
+	SHA     string      `json:"sha"`
+	Commit  CommitData  `json:"commit"`
+	Author  User        `json:"author"`
+	Parents []CommitRef `json:"parents"`
+}
+
+type CommitRef struct {
+	SHA string `json:"sha"`
+}
+
+// IsMerge reports whether the commit has more than one parent.
+func (c Commit) IsMerge() bool {
+	return len(c.Parents) > 1
 }
 
 type CommitData struct {
@@ -72,6 +108,9 @@ type FileChange struct {
 	Additions int    `json:"additions"`
 	Deletions int    `json:"deletions"`
 	Changes   int    `json:"changes"`
+	// Patch is the file's unified diff; GitHub omits it for binary and very
+	// large files.
+	Patch string `json:"patch,omitempty"`
 }
 
 type WorkflowRun struct {
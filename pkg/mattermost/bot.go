@@ -22,6 +22,14 @@ func NewBot(baseURL, token string) *Bot {
 	}
 }
 
+func NewBotWithHTTP(baseURL, token string, httpClient HTTPDoer) *Bot {
+	return &Bot{
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
 type postPayload struct {
 	ChannelID string `json:"channel_id"`
 	RootID    string `json:"root_id,omitempty"`
@@ -31,6 +39,8 @@ type postPayload struct {
 type User struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
+	Email    string `json:"email"`
+	Roles    string `json:"roles"`
 }
 
 func (b *Bot) PostMessage(ctx context.Context, channelID, message string) error {
@@ -75,8 +85,13 @@ type postResponse struct {
 }
 
 func (b *Bot) PostMessageWithID(ctx context.Context, channelID, message string) (string, error) {
+	return b.PostMessageInThreadWithID(ctx, channelID, "", message)
+}
+
+func (b *Bot) PostMessageInThreadWithID(ctx context.Context, channelID, rootID, message string) (string, error) {
 	payload := postPayload{
 		ChannelID: channelID,
+		RootID:    rootID,
 		Message:   message,
 	}
 	body, err := json.Marshal(payload)
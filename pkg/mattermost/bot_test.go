@@ -0,0 +1,69 @@
+package mattermost_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/pkg/mattermost"
+	"github.com/user/mattermost-tools/pkg/mattermost/mocks"
+)
+
+func TestBot_PostMessageInThreadWithID_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, http.MethodPost, req.Method)
+			require.Equal(t, "https://mm.example.com/api/v4/posts", req.URL.String())
+
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+			require.Equal(t, "ch1", payload["channel_id"])
+			require.Equal(t, "root1", payload["root_id"])
+			require.Equal(t, "⏳ Working", payload["message"])
+
+			return &http.Response{
+				StatusCode: 201,
+				Body:       io.NopCloser(strings.NewReader(`{"id":"post1"}`)),
+			}, nil
+		})
+
+	bot := mattermost.NewBotWithHTTP("https://mm.example.com", "bot-token", mockHTTP)
+	postID, err := bot.PostMessageInThreadWithID(context.Background(), "ch1", "root1", "⏳ Working")
+
+	require.NoError(t, err)
+	require.Equal(t, "post1", postID)
+}
+
+func TestBot_UpdatePost_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, http.MethodPut, req.Method)
+			require.Equal(t, "https://mm.example.com/api/v4/posts/post1", req.URL.String())
+
+			return &http.Response{
+				StatusCode: 403,
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+			}, nil
+		})
+
+	bot := mattermost.NewBotWithHTTP("https://mm.example.com", "bot-token", mockHTTP)
+	err := bot.UpdatePost(context.Background(), "post1", "done")
+
+	require.Error(t, err)
+}
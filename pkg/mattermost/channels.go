@@ -0,0 +1,147 @@
+package mattermost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	ChannelTypeOpen    = "O"
+	ChannelTypePrivate = "P"
+)
+
+type Channel struct {
+	ID          string `json:"id"`
+	TeamID      string `json:"team_id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+	Purpose     string `json:"purpose"`
+	Header      string `json:"header"`
+	DeleteAt    int64  `json:"delete_at"`
+}
+
+type CreateChannelRequest struct {
+	TeamID      string `json:"team_id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+	Purpose     string `json:"purpose,omitempty"`
+	Header      string `json:"header,omitempty"`
+}
+
+func (b *Bot) CreateChannel(ctx context.Context, req CreateChannelRequest) (*Channel, error) {
+	if req.Type == "" {
+		req.Type = ChannelTypeOpen
+	}
+
+	var channel Channel
+	if err := b.doJSON(ctx, http.MethodPost, "/api/v4/channels", req, &channel); err != nil {
+		return nil, fmt.Errorf("creating channel %s: %w", req.Name, err)
+	}
+	return &channel, nil
+}
+
+// GetChannelByName returns nil without an error when the channel does not exist.
+func (b *Bot) GetChannelByName(ctx context.Context, teamID, name string) (*Channel, error) {
+	path := fmt.Sprintf("/api/v4/teams/%s/channels/name/%s?include_deleted=true", teamID, name)
+
+	var channel Channel
+	if err := b.doJSON(ctx, http.MethodGet, path, nil, &channel); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting channel %s: %w", name, err)
+	}
+	return &channel, nil
+}
+
+type addChannelMemberPayload struct {
+	UserID string `json:"user_id"`
+}
+
+func (b *Bot) AddChannelMember(ctx context.Context, channelID, userID string) error {
+	path := fmt.Sprintf("/api/v4/channels/%s/members", channelID)
+	if err := b.doJSON(ctx, http.MethodPost, path, addChannelMemberPayload{UserID: userID}, nil); err != nil {
+		return fmt.Errorf("adding user %s to channel: %w", userID, err)
+	}
+	return nil
+}
+
+func (b *Bot) PinPost(ctx context.Context, postID string) error {
+	path := fmt.Sprintf("/api/v4/posts/%s/pin", postID)
+	if err := b.doJSON(ctx, http.MethodPost, path, nil, nil); err != nil {
+		return fmt.Errorf("pinning post %s: %w", postID, err)
+	}
+	return nil
+}
+
+// ArchiveChannel soft-deletes the channel; its history stays readable.
+func (b *Bot) ArchiveChannel(ctx context.Context, channelID string) error {
+	path := fmt.Sprintf("/api/v4/channels/%s", channelID)
+	if err := b.doJSON(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("archiving channel %s: %w", channelID, err)
+	}
+	return nil
+}
+
+type apiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("API error: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API error: %d - %s", e.StatusCode, e.Body)
+}
+
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*apiError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+func (b *Bot) doJSON(ctx context.Context, method, path string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshaling payload: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+b.token)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &apiError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
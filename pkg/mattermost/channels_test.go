@@ -0,0 +1,93 @@
+package mattermost_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/mattermost-tools/pkg/mattermost"
+	"github.com/user/mattermost-tools/pkg/mattermost/mocks"
+)
+
+func TestBot_CreateChannel_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, http.MethodPost, req.Method)
+			require.Equal(t, "https://mm.example.com/api/v4/channels", req.URL.String())
+			require.Equal(t, "Bearer bot-token", req.Header.Get("Authorization"))
+
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+			require.Equal(t, "team1", payload["team_id"])
+			require.Equal(t, "release-uat-master-2025-01-14", payload["name"])
+			require.Equal(t, "O", payload["type"])
+
+			return &http.Response{
+				StatusCode: 201,
+				Body:       io.NopCloser(strings.NewReader(`{"id":"ch1","name":"release-uat-master-2025-01-14","team_id":"team1"}`)),
+			}, nil
+		})
+
+	bot := mattermost.NewBotWithHTTP("https://mm.example.com", "bot-token", mockHTTP)
+	channel, err := bot.CreateChannel(context.Background(), mattermost.CreateChannelRequest{
+		TeamID:      "team1",
+		Name:        "release-uat-master-2025-01-14",
+		DisplayName: "Release uat → master",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "ch1", channel.ID)
+}
+
+func TestBot_GetChannelByName_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: 404,
+			Body:       io.NopCloser(strings.NewReader(`{"message":"not found"}`)),
+		}, nil)
+
+	bot := mattermost.NewBotWithHTTP("https://mm.example.com", "bot-token", mockHTTP)
+	channel, err := bot.GetChannelByName(context.Background(), "team1", "missing")
+
+	require.NoError(t, err)
+	require.Nil(t, channel)
+}
+
+func TestBot_ArchiveChannel_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, http.MethodDelete, req.Method)
+			require.Equal(t, "https://mm.example.com/api/v4/channels/ch1", req.URL.String())
+			return &http.Response{
+				StatusCode: 403,
+				Body:       io.NopCloser(strings.NewReader(`{"message":"forbidden"}`)),
+			}, nil
+		})
+
+	bot := mattermost.NewBotWithHTTP("https://mm.example.com", "bot-token", mockHTTP)
+	err := bot.ArchiveChannel(context.Background(), "ch1")
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "403")
+}
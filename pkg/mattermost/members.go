@@ -0,0 +1,60 @@
+package mattermost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type Group struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// Member is a team or channel membership. Roles is space-separated, e.g.
+// "channel_user channel_admin"; SchemeAdmin is set when the admin role comes
+// from a permission scheme instead.
+type Member struct {
+	UserID      string `json:"user_id"`
+	Roles       string `json:"roles"`
+	SchemeAdmin bool   `json:"scheme_admin"`
+}
+
+func (b *Bot) GetUserGroups(ctx context.Context, userID string) ([]Group, error) {
+	path := fmt.Sprintf("/api/v4/users/%s/groups", userID)
+
+	var groups []Group
+	if err := b.doJSON(ctx, http.MethodGet, path, nil, &groups); err != nil {
+		return nil, fmt.Errorf("getting groups for user %s: %w", userID, err)
+	}
+	return groups, nil
+}
+
+// GetTeamMember returns nil without an error when the user is not on the team.
+func (b *Bot) GetTeamMember(ctx context.Context, teamID, userID string) (*Member, error) {
+	path := fmt.Sprintf("/api/v4/teams/%s/members/%s", teamID, userID)
+
+	var member Member
+	if err := b.doJSON(ctx, http.MethodGet, path, nil, &member); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting team member %s: %w", userID, err)
+	}
+	return &member, nil
+}
+
+// GetChannelMember returns nil without an error when the user is not in the channel.
+func (b *Bot) GetChannelMember(ctx context.Context, channelID, userID string) (*Member, error) {
+	path := fmt.Sprintf("/api/v4/channels/%s/members/%s", channelID, userID)
+
+	var member Member
+	if err := b.doJSON(ctx, http.MethodGet, path, nil, &member); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting channel member %s: %w", userID, err)
+	}
+	return &member, nil
+}
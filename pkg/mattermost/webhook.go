@@ -33,13 +33,43 @@ func NewWebhookWithHTTP(url string, httpClient HTTPDoer) *Webhook {
 	}
 }
 
-type webhookPayload struct {
-	Text string `json:"text"`
+// WebhookMessage is the incoming webhook payload. Channel, Username and
+// IconURL override the webhook defaults and require the matching
+// "Enable integrations to override" settings on the Mattermost server.
+type WebhookMessage struct {
+	Text        string                 `json:"text,omitempty"`
+	Channel     string                 `json:"channel,omitempty"`
+	Username    string                 `json:"username,omitempty"`
+	IconURL     string                 `json:"icon_url,omitempty"`
+	Props       map[string]interface{} `json:"props,omitempty"`
+	Attachments []Attachment           `json:"attachments,omitempty"`
+}
+
+type Attachment struct {
+	Fallback   string            `json:"fallback,omitempty"`
+	Color      string            `json:"color,omitempty"`
+	Pretext    string            `json:"pretext,omitempty"`
+	AuthorName string            `json:"author_name,omitempty"`
+	AuthorLink string            `json:"author_link,omitempty"`
+	Title      string            `json:"title,omitempty"`
+	TitleLink  string            `json:"title_link,omitempty"`
+	Text       string            `json:"text,omitempty"`
+	Fields     []AttachmentField `json:"fields,omitempty"`
+	Footer     string            `json:"footer,omitempty"`
+}
+
+type AttachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
 }
 
 func (w *Webhook) Post(ctx context.Context, message string) error {
-	payload := webhookPayload{Text: message}
-	body, err := json.Marshal(payload)
+	return w.Send(ctx, WebhookMessage{Text: message})
+}
+
+func (w *Webhook) Send(ctx context.Context, msg WebhookMessage) error {
+	body, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("marshaling payload: %w", err)
 	}
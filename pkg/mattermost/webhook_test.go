@@ -2,6 +2,7 @@ package mattermost_test
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"strings"
@@ -58,3 +59,57 @@ func TestWebhook_Post_Error(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "500")
 }
+
+func TestWebhook_Send_Overrides(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTP := mocks.NewMockHTTPDoer(ctrl)
+	mockHTTP.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			var payload map[string]interface{}
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+
+			require.Equal(t, "reviews", payload["channel"])
+			require.Equal(t, "pr-bot", payload["username"])
+			require.Equal(t, "https://example.com/icon.png", payload["icon_url"])
+
+			attachments := payload["attachments"].([]interface{})
+			require.Len(t, attachments, 1)
+			attachment := attachments[0].(map[string]interface{})
+			require.Equal(t, "#d0021b", attachment["color"])
+			require.Equal(t, "repo#1", attachment["title"])
+
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader("ok")),
+			}, nil
+		})
+
+	webhook := mattermost.NewWebhookWithHTTP("https://mattermost.example.com/hooks/xxx", mockHTTP)
+	err := webhook.Send(context.Background(), mattermost.WebhookMessage{
+		Text:     "Pending PRs",
+		Channel:  "reviews",
+		Username: "pr-bot",
+		IconURL:  "https://example.com/icon.png",
+		Attachments: []mattermost.Attachment{
+			{Color: "#d0021b", Title: "repo#1"},
+		},
+	})
+
+	require.NoError(t, err)
+}
+
+func TestWebhookMessage_MarshalProps(t *testing.T) {
+	data, err := json.Marshal(mattermost.WebhookMessage{
+		Text:  "Pending PRs",
+		Props: map[string]interface{}{"card": "Details", "from_bot": "true"},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"text":"Pending PRs","props":{"card":"Details","from_bot":"true"}}`, string(data))
+
+	data, err = json.Marshal(mattermost.WebhookMessage{Text: "Pending PRs"})
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "props")
+}
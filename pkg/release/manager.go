@@ -72,7 +72,7 @@ func (m *Manager) CreateRelease(ctx context.Context, teamID, playbookID, ownerUs
 		}
 	}
 
-	summary := m.FormatReleaseSummary(release)
+	summary := m.FormatReleaseSummary(ctx, release)
 	postID, err := m.mmBot.PostMessageWithID(ctx, runResp.ChannelID, summary)
 	if err != nil {
 		fmt.Printf("warning: failed to post summary: %v\n", err)
@@ -187,7 +187,7 @@ func (m *Manager) collectUserIDs(ctx context.Context, repos []RepoStatus, defaul
 	seen := make(map[string]struct{})
 
 	for ghUser := range ghUsers {
-		mmUsername, ok := mappings.MattermostFromGitHub(ghUser)
+		mmUsername, ok := mappings.MattermostFromGitHub(ctx, ghUser)
 		if !ok {
 			continue
 		}
@@ -207,7 +207,7 @@ func (m *Manager) collectUserIDs(ctx context.Context, repos []RepoStatus, defaul
 	return userIDs
 }
 
-func (m *Manager) FormatReleaseSummary(release *Release) string {
+func (m *Manager) FormatReleaseSummary(ctx context.Context, release *Release) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("## Release: `%s` -> `%s`\n\n", release.SourceBranch, release.DestBranch))
@@ -218,7 +218,7 @@ func (m *Manager) FormatReleaseSummary(release *Release) string {
 
 	var missingPRs []RepoStatus
 	for _, repo := range release.Repos {
-		contributors := m.formatContributors(repo.Contributors)
+		contributors := m.formatContributors(ctx, repo.Contributors)
 
 		prStatus := "-"
 		if repo.HasPR {
@@ -251,7 +251,7 @@ func (m *Manager) FormatReleaseSummary(release *Release) string {
 	return sb.String()
 }
 
-func (m *Manager) formatContributors(ghUsers []string) string {
+func (m *Manager) formatContributors(ctx context.Context, ghUsers []string) string {
 	if len(ghUsers) == 0 {
 		return "-"
 	}
@@ -262,7 +262,7 @@ func (m *Manager) formatContributors(ghUsers []string) string {
 			formatted = append(formatted, fmt.Sprintf("+%d more", len(ghUsers)-3))
 			break
 		}
-		mmUsername, ok := mappings.MattermostFromGitHub(ghUser)
+		mmUsername, ok := mappings.MattermostFromGitHub(ctx, ghUser)
 		if ok {
 			formatted = append(formatted, "@"+mmUsername)
 		} else {
@@ -298,7 +298,7 @@ func (m *Manager) RefreshRelease(ctx context.Context, channelID string) (*Releas
 	m.mu.Unlock()
 
 	if existing.SummaryPostID != "" {
-		summary := m.FormatReleaseSummary(existing)
+		summary := m.FormatReleaseSummary(ctx, existing)
 		if err := m.mmBot.UpdatePost(ctx, existing.SummaryPostID, summary); err != nil {
 			return nil, fmt.Errorf("updating summary post: %w", err)
 		}
@@ -1,5 +1,5 @@
 import axios from 'axios'
-import type { Release, ReleaseWithRepos, UserInfo, UserProfile, HistoryEntry, CreateReleaseResponse, CIStatusResponse, DeploymentStatusListResponse } from './types'
+import type { Release, ReleaseWithRepos, UserInfo, UserProfile, HistoryEntry, CreateReleaseResponse, CIStatusResponse, DeploymentStatusListResponse, ReleaseTransitions } from './types'
 
 const api = axios.create({
   baseURL: '/api',
@@ -41,8 +41,9 @@ export const releaseApi = {
     await api.patch(`/releases/${releaseId}/repos/${repoId}`, updates)
   },
 
-  approve: async (id: string, type: 'dev' | 'qa') => {
-    await api.post(`/releases/${id}/approve/${type}`)
+  approve: async (id: string, type: 'dev' | 'qa', overrideFreeze = false) => {
+    const params = overrideFreeze ? { override_freeze: 'true' } : {}
+    await api.post(`/releases/${id}/approve/${type}`, null, { params })
   },
 
   revokeApproval: async (id: string, type: 'dev' | 'qa') => {
@@ -57,6 +58,16 @@ export const releaseApi = {
     await api.post(`/releases/${id}/decline`)
   },
 
+  getTransitions: async (id: string): Promise<ReleaseTransitions> => {
+    const { data } = await api.get(`/releases/${id}/transitions`)
+    return data
+  },
+
+  transition: async (id: string, status: string, reason = '') => {
+    const { data } = await api.post(`/releases/${id}/transition`, { status, reason })
+    return data as Release
+  },
+
   confirmRepo: async (releaseId: string, repoId: number) => {
     await api.post(`/releases/${releaseId}/repos/${repoId}/confirm`)
   },
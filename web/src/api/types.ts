@@ -7,20 +7,30 @@ export interface Release {
   BreakingChanges: string
   CreatedBy: string
   ChannelID: string
+  IsHotfix: boolean
   DevApprovedBy: string
   DevApprovedAt: number
   QAApprovedBy: string
   QAApprovedAt: number
   DeclinedBy: string
   DeclinedAt: number
+  StatusChangedBy: string
+  StatusChangedAt: number
+  DeployedAt: number
   LastRefreshedAt: number
   CreatedAt: number
 }
 
+export interface ReleaseTransitions {
+  status: string
+  next: string[]
+}
+
 export interface ReleaseRepo {
   ID: number
   ReleaseID: string
   RepoName: string
+  SourceRef: string
   CommitCount: number
   Additions: number
   Deletions: number
@@ -33,6 +43,12 @@ export interface ReleaseRepo {
   DeployOrder: number
   Summary: string
   IsBreaking: boolean
+  RiskLevel: '' | 'low' | 'medium' | 'high'
+  HasMigration: boolean
+  APIChanges: string
+  ConfigChanges: string
+  RolloutNotes: string
+  RiskReasons: string
   ConfirmedBy: string
   ConfirmedAt: number
   InfraChanges: string